@@ -0,0 +1,101 @@
+package redis
+
+import (
+	"strconv"
+)
+
+// BitOperation 代表 BITOP 支持的位运算。
+type BitOperation string
+
+// 所有 BITOP 支持的位运算。
+const (
+	BitOpAnd BitOperation = "AND"
+	BitOpOr  BitOperation = "OR"
+	BitOpXor BitOperation = "XOR"
+	BitOpNot BitOperation = "NOT"
+)
+
+// BitFieldType 代表 BITFIELD 中一个整数字段的编码，比如 i8 代表 8 位有符号整数，u16 代表 16 位无符号整数。
+type BitFieldType string
+
+// 常用的 BITFIELD 整数编码，其他位数的编码可以用 SignedBits 或 UnsignedBits 构造。
+const (
+	I8  BitFieldType = "i8"
+	I16 BitFieldType = "i16"
+	I32 BitFieldType = "i32"
+	I64 BitFieldType = "i64"
+	U8  BitFieldType = "u8"
+	U16 BitFieldType = "u16"
+	U32 BitFieldType = "u32"
+	U63 BitFieldType = "u63"
+)
+
+// SignedBits 返回 bits 位有符号整数的编码，bits 最大为 64。
+func SignedBits(bits int) BitFieldType {
+	return BitFieldType("i" + strconv.Itoa(bits))
+}
+
+// UnsignedBits 返回 bits 位无符号整数的编码，bits 最大为 63。
+func UnsignedBits(bits int) BitFieldType {
+	return BitFieldType("u" + strconv.Itoa(bits))
+}
+
+// BitFieldOverflow 代表 BITFIELD 中 SET 和 INCRBY 溢出时的处理方式。
+type BitFieldOverflow string
+
+// 所有 BITFIELD 溢出处理方式。
+const (
+	OverflowWrap BitFieldOverflow = "WRAP" // OverflowWrap 代表溢出时回绕，这是 Redis 的默认行为。
+	OverflowSat  BitFieldOverflow = "SAT"  // OverflowSat 代表溢出时取最大或最小值。
+	OverflowFail BitFieldOverflow = "FAIL" // OverflowFail 代表溢出时不做任何修改，并返回 nil。
+)
+
+// BitFieldOps 代表 BITFIELD 的一系列子命令，通过链式调用构造。
+//
+// 使用方法：
+//
+//     ops := redis.NewBitFieldOps().
+//         Get(redis.U8, 0).
+//         Overflow(redis.OverflowFail).
+//         IncrBy(redis.I16, 8, 100)
+//     values, err := r.BitField("foo", ops)
+//
+// 每个 GET、SET 和 INCRBY 子命令在 values 中都有一个对应的返回值，
+// 如果 OVERFLOW FAIL 导致操作失败，对应的返回值 IsNil 为 true。
+type BitFieldOps struct {
+	args []interface{}
+}
+
+// NewBitFieldOps 创建一个空的 BitFieldOps。
+func NewBitFieldOps() *BitFieldOps {
+	return &BitFieldOps{}
+}
+
+// Get 增加一个 GET type offset 子命令，读取 offset 位置上编码为 t 的整数。
+func (ops *BitFieldOps) Get(t BitFieldType, offset int64) *BitFieldOps {
+	ops.args = append(ops.args, "GET", string(t), offset)
+	return ops
+}
+
+// Set 增加一个 SET type offset value 子命令，将 offset 位置上编码为 t 的整数设置为 value，返回值是旧值。
+func (ops *BitFieldOps) Set(t BitFieldType, offset int64, value int64) *BitFieldOps {
+	ops.args = append(ops.args, "SET", string(t), offset, value)
+	return ops
+}
+
+// IncrBy 增加一个 INCRBY type offset incr 子命令，将 offset 位置上编码为 t 的整数加上 incr，返回值是新值。
+func (ops *BitFieldOps) IncrBy(t BitFieldType, offset int64, incr int64) *BitFieldOps {
+	ops.args = append(ops.args, "INCRBY", string(t), offset, incr)
+	return ops
+}
+
+// Overflow 增加一个 OVERFLOW 子命令，设置后续所有 SET 和 INCRBY 的溢出处理方式。
+func (ops *BitFieldOps) Overflow(overflow BitFieldOverflow) *BitFieldOps {
+	ops.args = append(ops.args, "OVERFLOW", string(overflow))
+	return ops
+}
+
+// Args 返回用于拼接 Redis 命令的参数。
+func (ops *BitFieldOps) Args() []interface{} {
+	return ops.args
+}
@@ -0,0 +1,300 @@
+package redis
+
+import (
+	"container/list"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/altstory/go-redis/internal/driver"
+)
+
+// clientCache 是基于 CLIENT TRACKING 的客户端缓存，缓存 GET、MGET、HGET 和 HGETALL 的应答。
+//
+// 开启缓存后，连接池里的连接都会开启 CLIENT TRACKING，服务器上的 key 被修改时会发送失效消息，
+// clientCache 收到消息后删除这个 key 相关的所有缓存。缓存最多保存 size 个应答，超过之后淘汰最久没有使用的应答。
+//
+// 失效消息是异步到达的，写入命令不会主动删除缓存，因此通过同一个 Redis 写入 key 之后立刻读取，
+// 在失效消息到达之前仍然可能读到写入前缓存的应答。需要读到自己写入的值时不应该开启缓存。
+//
+// 读取 Redis 和收到失效消息是在不同连接上并发发生的，如果失效消息先于应答到达，
+// 直接缓存应答会导致缓存永远不会失效，因此读取之前需要先通过 reserve 登记，
+// 读取期间 key 失效的话，读到的应答不会被缓存。
+type clientCache struct {
+	size int
+
+	mu      sync.Mutex
+	lru     *list.List                                // lru 按照最近使用的顺序保存 *cacheEntry，最近使用的在最前面。
+	entries map[string]*list.Element                  // entries 是缓存 ID 到 lru 元素的映射。
+	keys    map[string]map[string]struct{}            // keys 记录每个 key 相关的缓存 ID。
+	pending map[string]map[*cacheReservation]struct{} // pending 记录每个 key 正在进行的读取。
+}
+
+// cacheEntry 代表一个缓存的应答。
+type cacheEntry struct {
+	key   string
+	id    string
+	reply interface{}
+}
+
+// cacheReservation 代表一次正在进行的读取。
+type cacheReservation struct {
+	keys        []string
+	invalidated bool
+}
+
+// newClientCache 创建一个最多保存 size 个应答的客户端缓存，size 不大于 0 时返回 nil。
+func newClientCache(size int) *clientCache {
+	if size <= 0 {
+		return nil
+	}
+
+	return &clientCache{
+		size:    size,
+		lru:     list.New(),
+		entries: map[string]*list.Element{},
+		keys:    map[string]map[string]struct{}{},
+		pending: map[string]map[*cacheReservation]struct{}{},
+	}
+}
+
+// cacheID 生成命令参数对应的缓存 ID，每个参数都带上长度，保证不同的参数不会生成相同的 ID。
+func cacheID(args ...string) string {
+	var sb strings.Builder
+
+	for _, arg := range args {
+		sb.WriteString(strconv.Itoa(len(arg)))
+		sb.WriteByte(':')
+		sb.WriteString(arg)
+	}
+
+	return sb.String()
+}
+
+// get 返回 id 对应的缓存应答。
+func (c *clientCache) get(id string) (reply interface{}, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[id]
+
+	if !ok {
+		return
+	}
+
+	c.lru.MoveToFront(elem)
+	reply = elem.Value.(*cacheEntry).reply
+	return
+}
+
+// reserve 在读取 keys 之前登记，读取结束之后需要调用 release。
+func (c *clientCache) reserve(keys ...string) *cacheReservation {
+	res := &cacheReservation{
+		keys: keys,
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		m := c.pending[key]
+
+		if m == nil {
+			m = map[*cacheReservation]struct{}{}
+			c.pending[key] = m
+		}
+
+		m[res] = struct{}{}
+	}
+
+	return res
+}
+
+// release 结束 res 登记的读取。
+func (c *clientCache) release(res *cacheReservation) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.unreserve(res)
+}
+
+// set 缓存 key 相关的应答，如果 res 登记之后 key 已经失效，应答不会被缓存。
+func (c *clientCache) set(res *cacheReservation, key, id string, reply interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if res.invalidated {
+		return
+	}
+
+	if elem, ok := c.entries[id]; ok {
+		elem.Value.(*cacheEntry).reply = reply
+		c.lru.MoveToFront(elem)
+		return
+	}
+
+	c.entries[id] = c.lru.PushFront(&cacheEntry{
+		key:   key,
+		id:    id,
+		reply: reply,
+	})
+	ids := c.keys[key]
+
+	if ids == nil {
+		ids = map[string]struct{}{}
+		c.keys[key] = ids
+	}
+
+	ids[id] = struct{}{}
+
+	for c.lru.Len() > c.size {
+		c.remove(c.lru.Back())
+	}
+}
+
+// invalidate 删除 keys 相关的所有缓存，keys 为 nil 时清空所有缓存。
+func (c *clientCache) invalidate(keys []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if keys == nil {
+		for _, m := range c.pending {
+			for res := range m {
+				res.invalidated = true
+			}
+		}
+
+		c.lru.Init()
+		c.entries = map[string]*list.Element{}
+		c.keys = map[string]map[string]struct{}{}
+		c.pending = map[string]map[*cacheReservation]struct{}{}
+		return
+	}
+
+	for _, key := range keys {
+		for res := range c.pending[key] {
+			res.invalidated = true
+			c.unreserve(res)
+		}
+
+		for id := range c.keys[key] {
+			c.remove(c.entries[id])
+		}
+	}
+}
+
+// len 返回缓存的应答数量。
+func (c *clientCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.lru.Len()
+}
+
+// remove 删除一个缓存的应答，调用者需要持有锁。
+func (c *clientCache) remove(elem *list.Element) {
+	entry := c.lru.Remove(elem).(*cacheEntry)
+	delete(c.entries, entry.id)
+	ids := c.keys[entry.key]
+	delete(ids, entry.id)
+
+	if len(ids) == 0 {
+		delete(c.keys, entry.key)
+	}
+}
+
+// unreserve 删除 res 的登记，调用者需要持有锁。
+func (c *clientCache) unreserve(res *cacheReservation) {
+	for _, key := range res.keys {
+		m := c.pending[key]
+		delete(m, res)
+
+		if len(m) == 0 {
+			delete(c.pending, key)
+		}
+	}
+}
+
+// onInvalidate 返回 cache 的失效回调，cache 为 nil 时返回 nil，也就是不开启 CLIENT TRACKING。
+func onInvalidate(cache *clientCache) func(keys []string) {
+	if cache == nil {
+		return nil
+	}
+
+	return cache.invalidate
+}
+
+// processCached 与 process 一样执行 cmd，开启了客户端缓存时会优先使用缓存的应答，
+// key 是 cmd 读取的 key，id 是 cmd 的缓存 ID。
+func (r *redisImpl) processCached(client driver.Client, key, id string, cmd *command) *command {
+	if r.cache == nil {
+		return process(client, cmd)
+	}
+
+	if reply, ok := r.cache.get(id); ok {
+		cmd.SetReply(reply)
+		return cmd
+	}
+
+	res := r.cache.reserve(key)
+	defer r.cache.release(res)
+	cmd.SetTrackedKeys(key)
+
+	if err := client.Process(cmd); err == nil {
+		r.cache.set(res, key, id, cmd.Reply())
+	}
+
+	return cmd
+}
+
+// processMGet 执行 MGET，开启了客户端缓存时只会从 Redis 读取没有缓存的 key。
+func (r *redisImpl) processMGet(client driver.Client, keys []string) *command {
+	cmd := newCmd(appendArgs([]interface{}{"MGET"}, keys)...)
+
+	if r.cache == nil {
+		return process(client, cmd)
+	}
+
+	replies := make([]interface{}, len(keys))
+	var missed []string
+	var missedIdx []int
+
+	for i, key := range keys {
+		if reply, ok := r.cache.get(cacheID("MGET", key)); ok {
+			replies[i] = reply
+			continue
+		}
+
+		missed = append(missed, key)
+		missedIdx = append(missedIdx, i)
+	}
+
+	if len(missed) == 0 {
+		cmd.SetReply(replies)
+		return cmd
+	}
+
+	res := r.cache.reserve(missed...)
+	defer r.cache.release(res)
+
+	missedCmd := newCmd(appendArgs([]interface{}{"MGET"}, missed)...)
+	missedCmd.SetTrackedKeys(missed...)
+
+	if err := client.Process(missedCmd); err != nil {
+		return missedCmd
+	}
+
+	values, ok := missedCmd.Reply().([]interface{})
+
+	if !ok || len(values) != len(missed) {
+		return missedCmd
+	}
+
+	for i, v := range values {
+		replies[missedIdx[i]] = v
+		r.cache.set(res, missed[i], cacheID("MGET", missed[i]), v)
+	}
+
+	cmd.SetReply(replies)
+	return cmd
+}
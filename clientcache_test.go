@@ -0,0 +1,195 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/altstory/go-redis/internal/driver"
+	"github.com/huandu/go-assert"
+)
+
+func TestClientCacheLRU(t *testing.T) {
+	a := assert.New(t)
+	cache := newClientCache(2)
+
+	res := cache.reserve("k1", "k2", "k3")
+	cache.set(res, "k1", cacheID("GET", "k1"), []byte("v1"))
+	cache.set(res, "k2", cacheID("GET", "k2"), []byte("v2"))
+
+	// 访问 k1 之后 k2 是最久没有使用的应答，会被淘汰。
+	_, ok := cache.get(cacheID("GET", "k1"))
+	a.Assert(ok)
+	cache.set(res, "k3", cacheID("GET", "k3"), nil)
+	cache.release(res)
+	a.Equal(cache.len(), 2)
+	_, ok = cache.get(cacheID("GET", "k2"))
+	a.Assert(!ok)
+	reply, ok := cache.get(cacheID("GET", "k3"))
+	a.Assert(ok)
+	a.Equal(reply, nil)
+
+	// 读取期间 key 失效的话，应答不会被缓存。
+	res = cache.reserve("k4")
+	cache.invalidate([]string{"k4"})
+	cache.set(res, "k4", cacheID("GET", "k4"), []byte("v4"))
+	cache.release(res)
+	_, ok = cache.get(cacheID("GET", "k4"))
+	a.Assert(!ok)
+
+	res = cache.reserve("h")
+	cache.set(res, "h", cacheID("HGET", "h", "f"), []byte("v"))
+	cache.release(res)
+	cache.invalidate([]string{"h"})
+	_, ok = cache.get(cacheID("HGET", "h", "f"))
+	a.Assert(!ok)
+	a.Equal(cache.len(), 1)
+
+	res = cache.reserve("k5")
+	cache.invalidate(nil)
+	cache.set(res, "k5", cacheID("GET", "k5"), []byte("v5"))
+	cache.release(res)
+	a.Equal(cache.len(), 0)
+
+	a.Assert(cacheID("HGET", "a:b", "c") != cacheID("HGET", "a", "b:c"))
+	a.Assert(newClientCache(0) == nil)
+}
+
+func TestClientCache(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	writer := factory(t).New(ctx)
+	resetRedis(t, writer)
+
+	for _, protocol := range []int{2, 3} {
+		f := NewFactory(&Config{
+			Client: &ClientConfig{
+				Addr:      testAddr,
+				PoolSize:  2,
+				Protocol:  protocol,
+				CacheSize: 100,
+			},
+		})
+
+		if err := f.Conn(ctx); err != nil {
+			t.Skipf("fail to connect Redis server. [err:%v]", err)
+		}
+
+		r := f.New(ctx)
+		key := fmt.Sprintf("cache-%v", protocol)
+		hash := fmt.Sprintf("cache-hash-%v", protocol)
+		_, err := writer.Set(key, "v1")
+		a.NilError(err)
+		_, err = writer.HSet(hash, "f", "v1")
+		a.NilError(err)
+
+		value, err := r.Get(key)
+		a.NilError(err)
+		a.Equal(value.String(), "v1")
+		values, err := r.MGet(key, "cache-not-exist")
+		a.NilError(err)
+		a.Equal(len(values), 2)
+		a.Equal(values[0].String(), "v1")
+		a.Assert(values[1].IsNull())
+		value, err = r.HGet(hash, "f")
+		a.NilError(err)
+		a.Equal(value.String(), "v1")
+		kvs, err := r.HGetAll(hash)
+		a.NilError(err)
+		a.Equal(kvs, KeyAndValues{{Key: "f", Value: "v1"}})
+		a.Equal(f.cache.len(), 5)
+
+		// 缓存命中时不需要访问 Redis。
+		value, err = r.Get(key)
+		a.NilError(err)
+		a.Equal(value.String(), "v1")
+		kvs, err = r.HGetAll(hash)
+		a.NilError(err)
+		a.Equal(kvs, KeyAndValues{{Key: "f", Value: "v1"}})
+
+		// 其他客户端修改 key 之后，失效消息会删除相关的缓存。
+		_, err = writer.Set(key, "v2")
+		a.NilError(err)
+		_, err = writer.HSet(hash, "f", "v2")
+		a.NilError(err)
+		waitForCache(t, f.cache, 1)
+
+		value, err = r.Get(key)
+		a.NilError(err)
+		a.Equal(value.String(), "v2")
+		value, err = r.HGet(hash, "f")
+		a.NilError(err)
+		a.Equal(value.String(), "v2")
+
+		// 接收失效消息的连接断开之后，所有缓存都会被清空。
+		info, err := r.ClientInfo()
+		a.NilError(err)
+		a.Assert(info.Raw["redir"] != "" && info.Raw["redir"] != "-1")
+		killed, err := writer.ClientKillByFilter("ID", info.Raw["redir"])
+		a.NilError(err)
+		a.Equal(killed, 1)
+		waitForCache(t, f.cache, 0)
+
+		value, err = r.Get(key)
+		a.NilError(err)
+		a.Equal(value.String(), "v2")
+		a.Equal(f.cache.len(), 1)
+		_, err = writer.Set(key, "v3")
+		a.NilError(err)
+		waitForCache(t, f.cache, 0)
+
+		a.NilError(f.Close())
+	}
+}
+
+func TestClientCacheConnClosed(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	writer := factory(t).New(ctx)
+	resetRedis(t, writer)
+
+	f := NewFactory(&Config{
+		Client: &ClientConfig{
+			Addr:      testAddr,
+			PoolSize:  2,
+			CacheSize: 100,
+		},
+	})
+	defer f.Close()
+	a.NilError(f.Conn(ctx))
+	r := f.New(ctx)
+	pool := f.client.(*driver.SingleClient).Pool()
+
+	// k1 和 k2 分别通过不同的连接读取。
+	_, err := r.Get("cache-conn-k1")
+	a.NilError(err)
+	cn, err := pool.Get()
+	a.NilError(err)
+	_, err = r.Get("cache-conn-k2")
+	a.NilError(err)
+	a.Equal(f.cache.len(), 2)
+
+	// 关闭读取 k1 的连接之后，只有 k1 的缓存会失效。
+	pool.Remove(cn)
+	a.Equal(f.cache.len(), 1)
+	_, ok := f.cache.get(cacheID("GET", "cache-conn-k2"))
+	a.Assert(ok)
+
+	_, err = writer.Set("cache-conn-k2", "v")
+	a.NilError(err)
+	waitForCache(t, f.cache, 0)
+}
+
+// waitForCache 等待缓存的应答数量变成 n，失效消息是异步收到的。
+func waitForCache(t *testing.T, cache *clientCache, n int) {
+	deadline := time.Now().Add(time.Second)
+
+	for cache.len() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("client cache is not invalidated in time. [len:%v] [expected:%v]", cache.len(), n)
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,82 @@
+package redis
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ClientInfo 代表 CLIENT LIST 或 CLIENT INFO 返回的一个客户端连接信息。
+type ClientInfo struct {
+	ID        int64         // ID 是连接的唯一 ID。
+	Addr      string        // Addr 是客户端地址。
+	LocalAddr string        // LocalAddr 是服务端地址，需要 Redis 6.2 及以上版本。
+	FD        int           // FD 是连接对应的文件描述符。
+	Name      string        // Name 是连接的名字，通过 CLIENT SETNAME 设置。
+	Age       time.Duration // Age 是连接建立的时长，精度是秒。
+	Idle      time.Duration // Idle 是连接空闲的时长，精度是秒。
+	Flags     string        // Flags 是连接的标记，详见 https://redis.io/commands/client-list。
+	DB        int           // DB 是连接当前选择的数据库。
+	Sub       int           // Sub 是连接订阅的 channel 数量。
+	PSub      int           // PSub 是连接订阅的 pattern 数量。
+	Multi     int           // Multi 是在 MULTI/EXEC 中缓存的命令数量，-1 代表不在事务中。
+	Cmd       string        // Cmd 是连接最近执行的命令。
+	User      string        // User 是连接认证的用户名，需要 Redis 6.0 及以上版本。
+	LibName   string        // LibName 是客户端库的名字，需要 Redis 7.2 及以上版本。
+	LibVer    string        // LibVer 是客户端库的版本，需要 Redis 7.2 及以上版本。
+
+	Raw map[string]string // Raw 保存所有原始字段，用于读取上面没有列出的字段。
+}
+
+// parseClientList 解析 CLIENT LIST 返回的文本，每行代表一个连接。
+func parseClientList(s string) (infos []ClientInfo) {
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+
+		if line == "" {
+			continue
+		}
+
+		infos = append(infos, parseClientInfo(line))
+	}
+
+	return
+}
+
+// parseClientInfo 解析一行形如 id=3 addr=127.0.0.1:50858 name= ... 的连接信息。
+func parseClientInfo(s string) (info ClientInfo) {
+	info.Raw = map[string]string{}
+
+	for _, field := range strings.Fields(s) {
+		idx := strings.IndexByte(field, '=')
+
+		if idx < 0 {
+			continue
+		}
+
+		info.Raw[field[:idx]] = field[idx+1:]
+	}
+
+	atoi := func(key string) int {
+		n, _ := strconv.Atoi(info.Raw[key])
+		return n
+	}
+
+	info.ID = parseInt64(info.Raw["id"])
+	info.Addr = info.Raw["addr"]
+	info.LocalAddr = info.Raw["laddr"]
+	info.FD = atoi("fd")
+	info.Name = info.Raw["name"]
+	info.Age = time.Duration(parseInt64(info.Raw["age"])) * time.Second
+	info.Idle = time.Duration(parseInt64(info.Raw["idle"])) * time.Second
+	info.Flags = info.Raw["flags"]
+	info.DB = atoi("db")
+	info.Sub = atoi("sub")
+	info.PSub = atoi("psub")
+	info.Multi = atoi("multi")
+	info.Cmd = info.Raw["cmd"]
+	info.User = info.Raw["user"]
+	info.LibName = info.Raw["lib-name"]
+	info.LibVer = info.Raw["lib-ver"]
+	return
+}
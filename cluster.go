@@ -1,6 +1,84 @@
 package redis
 
+import (
+	"github.com/altstory/go-redis/internal/driver"
+)
+
 // Cluster 代表 Redis 跟集群相关的接口，详见 https://redis.io/commands#cluster。
 type Cluster interface {
-	// TODO: 补充各种 CLUSTER 接口。
+	ClusterCountKeysInSlot(slot int) (count int, err error)
+	ClusterGetKeysInSlot(slot int, count int) (keys []string, err error)
+	ClusterInfo() (info ClusterInfo, err error)
+	ClusterKeySlot(key string) (slot int, err error)
+	ClusterNodes() (nodes []ClusterNode, err error)
+	ClusterSlots() (slots []ClusterSlot, err error)
+}
+
+func (r *redisImpl) ClusterCountKeysInSlot(slot int) (count int, err error) {
+	err = r.do("CLUSTER COUNTKEYSINSLOT", func(client driver.Client) error {
+		count, err = mustBeInt(client, process(client, newCmd("CLUSTER", "COUNTKEYSINSLOT", slot)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) ClusterGetKeysInSlot(slot int, count int) (keys []string, err error) {
+	err = r.do("CLUSTER GETKEYSINSLOT", func(client driver.Client) error {
+		keys, err = mustBeStrings(client, process(client, newCmd("CLUSTER", "GETKEYSINSLOT", slot, count)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) ClusterInfo() (info ClusterInfo, err error) {
+	err = r.do("CLUSTER INFO", func(client driver.Client) error {
+		var bs BulkString
+		bs, err = mustBeBulkString(client, process(client, newCmd("CLUSTER", "INFO")))
+
+		if err != nil {
+			return err
+		}
+
+		info = parseClusterInfo(bs.String())
+		return nil
+	})
+	return
+}
+
+func (r *redisImpl) ClusterKeySlot(key string) (slot int, err error) {
+	err = r.do("CLUSTER KEYSLOT", func(client driver.Client) error {
+		slot, err = mustBeInt(client, process(client, newCmd("CLUSTER", "KEYSLOT", key)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) ClusterNodes() (nodes []ClusterNode, err error) {
+	err = r.do("CLUSTER NODES", func(client driver.Client) error {
+		var bs BulkString
+		bs, err = mustBeBulkString(client, process(client, newCmd("CLUSTER", "NODES")))
+
+		if err != nil {
+			return err
+		}
+
+		nodes = parseClusterNodes(bs.String())
+		return nil
+	})
+	return
+}
+
+func (r *redisImpl) ClusterSlots() (slots []ClusterSlot, err error) {
+	err = r.do("CLUSTER SLOTS", func(client driver.Client) error {
+		var mvs []MultiValue
+		mvs, err = mustBeMultiValues(client, process(client, newCmd("CLUSTER", "SLOTS")))
+
+		if err != nil {
+			return err
+		}
+
+		slots = parseClusterSlots(mvs)
+		return nil
+	})
+	return
 }
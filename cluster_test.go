@@ -0,0 +1,135 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/huandu/go-assert"
+)
+
+const testClusterAddr = "127.0.0.1:7000"
+
+func clusterFactory(t *testing.T, config *Config) *Factory {
+	f := NewFactory(config)
+	ctx := context.Background()
+
+	if err := f.Conn(ctx); err != nil {
+		t.Skipf("fail to connect Redis cluster. [err:%v]", err)
+	}
+
+	if _, err := f.New(ctx).ClusterInfo(); err != nil {
+		t.Skipf("Redis cluster is not enabled. [err:%v]", err)
+	}
+
+	return f
+}
+
+func TestClusterMethods(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	f := clusterFactory(t, &Config{
+		Client: &ClientConfig{
+			Addr: testClusterAddr,
+		},
+	})
+	r := f.New(ctx)
+	a.NilError(r.FlushAll())
+
+	info, err := r.ClusterInfo()
+	a.NilError(err)
+	a.Equal(info.SlotsAssigned, 16384)
+	a.Assert(info.KnownNodes >= 1)
+	a.Equal(info.Raw["cluster_slots_assigned"], "16384")
+
+	nodes, err := r.ClusterNodes()
+	a.NilError(err)
+	a.Assert(len(nodes) >= 1)
+
+	var myself *ClusterNode
+
+	for i := range nodes {
+		if nodes[i].IsMyself() {
+			myself = &nodes[i]
+		}
+	}
+
+	a.Assert(myself != nil)
+	a.Assert(myself.IsMaster())
+	a.Assert(myself.IsConnected())
+
+	slots, err := r.ClusterSlots()
+	a.NilError(err)
+	a.Assert(len(slots) >= 1)
+	a.Equal(slots[0].Nodes[0].ID, myself.ID)
+
+	slot, err := r.ClusterKeySlot("foo")
+	a.NilError(err)
+	a.Equal(slot, 12182)
+
+	a.NilError(r.Set("foo", "bar"))
+	count, err := r.ClusterCountKeysInSlot(slot)
+	a.NilError(err)
+	a.Equal(count, 1)
+
+	keys, err := r.ClusterGetKeysInSlot(slot, 10)
+	a.NilError(err)
+	a.Equal(keys, []string{"foo"})
+}
+
+func TestClusterScanIter(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	f := clusterFactory(t, &Config{
+		Cluster: &ClusterConfig{
+			Addrs: []string{testClusterAddr},
+		},
+	})
+	r := f.New(ctx)
+
+	var keys []string
+
+	for i := 0; i < 20; i++ {
+		key := fmt.Sprintf("cluster-iter-%v", i)
+		keys = append(keys, key)
+		a.NilError(r.Set(key, "v", Expire(time.Minute)))
+	}
+
+	var scanned []string
+	it := r.ScanIter(Match("cluster-iter-*"))
+
+	for it.Next() {
+		scanned = append(scanned, it.Key())
+	}
+
+	a.NilError(it.Err())
+	sort.Strings(keys)
+	sort.Strings(scanned)
+	a.Equal(scanned, keys)
+}
+
+func TestParseClusterNodes(t *testing.T) {
+	a := assert.New(t)
+	nodes := parseClusterNodes(`07c37dfeb235213a872192d90877d0cd55635b91 127.0.0.1:30004@31004,host-4 slave e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca 0 1426238317239 4 connected
+67ed2db8d677e59ec4a4cefb06858cf2a1a89fa1 127.0.0.1:30002@31002 master - 0 1426238316232 2 connected 5461-10922 [5462->-e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca]
+e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca 127.0.0.1:30001@31001 myself,master - 0 0 1 connected 0-5460 10923
+`)
+	a.Equal(len(nodes), 3)
+	a.Equal(nodes[0], ClusterNode{
+		ID:           "07c37dfeb235213a872192d90877d0cd55635b91",
+		Addr:         "127.0.0.1:30004",
+		BusPort:      31004,
+		Hostname:     "host-4",
+		Flags:        []string{"slave"},
+		MasterID:     "e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca",
+		PongReceived: time.Unix(0, 1426238317239*int64(time.Millisecond)),
+		ConfigEpoch:  4,
+		LinkState:    "connected",
+	})
+	a.Assert(nodes[0].IsReplica())
+	a.Equal(nodes[1].Slots, []ClusterSlotRange{{5461, 10922}})
+	a.Assert(nodes[2].IsMyself())
+	a.Equal(nodes[2].Slots, []ClusterSlotRange{{0, 5460}, {10923, 10923}})
+}
@@ -0,0 +1,259 @@
+package redis
+
+import (
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ClusterInfo 代表 CLUSTER INFO 返回的集群状态。
+type ClusterInfo struct {
+	State                 string // State 是集群状态，ok 或者 fail。
+	SlotsAssigned         int    // SlotsAssigned 是已经分配给结点的 slot 数量。
+	SlotsOK               int    // SlotsOK 是状态正常的 slot 数量。
+	SlotsPFail            int    // SlotsPFail 是处于 PFAIL 状态的 slot 数量。
+	SlotsFail             int    // SlotsFail 是处于 FAIL 状态的 slot 数量。
+	KnownNodes            int    // KnownNodes 是集群中已知的结点数量。
+	Size                  int    // Size 是至少负责一个 slot 的 master 结点数量。
+	CurrentEpoch          int64  // CurrentEpoch 是集群当前的 epoch。
+	MyEpoch               int64  // MyEpoch 是当前结点的 config epoch。
+	StatsMessagesSent     int64  // StatsMessagesSent 是通过集群总线发送的消息数量。
+	StatsMessagesReceived int64  // StatsMessagesReceived 是通过集群总线收到的消息数量。
+
+	Raw map[string]string // Raw 保存所有原始字段，用于读取上面没有列出的字段。
+}
+
+// IsOK 判断集群状态是否为 ok。
+func (ci *ClusterInfo) IsOK() bool {
+	return ci.State == "ok"
+}
+
+func parseClusterInfo(s string) (info ClusterInfo) {
+	info.Raw = parseInfoFields(s)
+	info.State = info.Raw["cluster_state"]
+	info.SlotsAssigned = parseInfoInt(info.Raw, "cluster_slots_assigned")
+	info.SlotsOK = parseInfoInt(info.Raw, "cluster_slots_ok")
+	info.SlotsPFail = parseInfoInt(info.Raw, "cluster_slots_pfail")
+	info.SlotsFail = parseInfoInt(info.Raw, "cluster_slots_fail")
+	info.KnownNodes = parseInfoInt(info.Raw, "cluster_known_nodes")
+	info.Size = parseInfoInt(info.Raw, "cluster_size")
+	info.CurrentEpoch = parseInfoInt64(info.Raw, "cluster_current_epoch")
+	info.MyEpoch = parseInfoInt64(info.Raw, "cluster_my_epoch")
+	info.StatsMessagesSent = parseInfoInt64(info.Raw, "cluster_stats_messages_sent")
+	info.StatsMessagesReceived = parseInfoInt64(info.Raw, "cluster_stats_messages_received")
+	return
+}
+
+// parseInfoFields 解析 INFO 风格的 key:value 文本，忽略空行和注释。
+func parseInfoFields(s string) map[string]string {
+	fields := map[string]string{}
+
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		idx := strings.IndexByte(line, ':')
+
+		if idx < 0 {
+			continue
+		}
+
+		fields[line[:idx]] = line[idx+1:]
+	}
+
+	return fields
+}
+
+func parseInfoInt(fields map[string]string, key string) int {
+	n, _ := strconv.Atoi(fields[key])
+	return n
+}
+
+func parseInfoInt64(fields map[string]string, key string) int64 {
+	return parseInt64(fields[key])
+}
+
+// ClusterNode 代表 CLUSTER NODES 返回的一个结点。
+type ClusterNode struct {
+	ID           string             // ID 是结点 ID。
+	Addr         string             // Addr 是结点的 ip:port。
+	BusPort      int                // BusPort 是结点集群总线的端口。
+	Hostname     string             // Hostname 是结点的 hostname，只有 Redis 7.0 以上并且设置了 hostname 才有值。
+	Flags        []string           // Flags 是结点的所有标记，比如 myself、master、slave、fail? 等。
+	MasterID     string             // MasterID 是 replica 结点对应的 master 结点 ID，master 结点这个值为空。
+	PingSent     time.Time          // PingSent 是最近一次发送 ping 的时间，如果没有未回复的 ping 则为零值。
+	PongReceived time.Time          // PongReceived 是最近一次收到 pong 的时间。
+	ConfigEpoch  int64              // ConfigEpoch 是结点的 config epoch。
+	LinkState    string             // LinkState 是集群总线的连接状态，connected 或者 disconnected。
+	Slots        []ClusterSlotRange // Slots 是结点负责的 slot 范围。
+}
+
+// HasFlag 判断结点是否有 flag 标记。
+func (cn *ClusterNode) HasFlag(flag string) bool {
+	for _, f := range cn.Flags {
+		if f == flag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsMaster 判断结点是否是 master。
+func (cn *ClusterNode) IsMaster() bool {
+	return cn.HasFlag("master")
+}
+
+// IsReplica 判断结点是否是 replica。
+func (cn *ClusterNode) IsReplica() bool {
+	return cn.HasFlag("slave")
+}
+
+// IsMyself 判断结点是否是当前连接的结点。
+func (cn *ClusterNode) IsMyself() bool {
+	return cn.HasFlag("myself")
+}
+
+// IsConnected 判断结点的集群总线是否处于连接状态。
+func (cn *ClusterNode) IsConnected() bool {
+	return cn.LinkState == "connected"
+}
+
+// ClusterSlotRange 代表一段连续的 slot，包含 Start 和 End。
+type ClusterSlotRange struct {
+	Start int
+	End   int
+}
+
+// parseClusterNodes 解析 CLUSTER NODES 返回的文本，每行的格式是：
+//     <id> <ip:port@cport[,hostname]> <flags> <master> <ping-sent> <pong-recv> <config-epoch> <link-state> <slot> ...
+// 正在迁移的 slot（形如 [slot->-id] 或 [slot-<-id]）会被忽略。
+func parseClusterNodes(s string) (nodes []ClusterNode) {
+	for _, line := range strings.Split(s, "\n") {
+		fields := strings.Fields(line)
+
+		if len(fields) < 8 {
+			continue
+		}
+
+		node := ClusterNode{
+			ID:          fields[0],
+			Flags:       strings.Split(fields[2], ","),
+			ConfigEpoch: parseInt64(fields[6]),
+			LinkState:   fields[7],
+		}
+		addr := fields[1]
+
+		if idx := strings.IndexByte(addr, ','); idx >= 0 {
+			node.Hostname = addr[idx+1:]
+			addr = addr[:idx]
+		}
+
+		if idx := strings.IndexByte(addr, '@'); idx >= 0 {
+			node.BusPort = int(parseInt64(addr[idx+1:]))
+			addr = addr[:idx]
+		}
+
+		node.Addr = addr
+
+		if fields[3] != "-" {
+			node.MasterID = fields[3]
+		}
+
+		if ms := parseInt64(fields[4]); ms != 0 {
+			node.PingSent = time.Unix(0, ms*int64(time.Millisecond))
+		}
+
+		if ms := parseInt64(fields[5]); ms != 0 {
+			node.PongReceived = time.Unix(0, ms*int64(time.Millisecond))
+		}
+
+		for _, slot := range fields[8:] {
+			if strings.HasPrefix(slot, "[") {
+				continue
+			}
+
+			start, end := slot, slot
+
+			if idx := strings.IndexByte(slot, '-'); idx >= 0 {
+				start, end = slot[:idx], slot[idx+1:]
+			}
+
+			node.Slots = append(node.Slots, ClusterSlotRange{
+				Start: int(parseInt64(start)),
+				End:   int(parseInt64(end)),
+			})
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	return
+}
+
+func parseInt64(s string) int64 {
+	n, _ := strconv.ParseInt(s, 10, 64)
+	return n
+}
+
+// ClusterSlot 代表 CLUSTER SLOTS 返回的一段 slot 及负责这段 slot 的结点。
+type ClusterSlot struct {
+	ClusterSlotRange
+	Nodes []ClusterSlotNode // Nodes 是负责这段 slot 的结点，第一个是 master，其余都是 replica。
+}
+
+// ClusterSlotNode 代表 CLUSTER SLOTS 中的一个结点。
+type ClusterSlotNode struct {
+	ID   string
+	Addr string
+}
+
+// parseClusterSlots 解析 CLUSTER SLOTS 的返回值，每个元素的格式是：
+//     [start, end, [ip, port, id, ...], [ip, port, id, ...], ...]
+func parseClusterSlots(mvs []MultiValue) (slots []ClusterSlot) {
+	slots = make([]ClusterSlot, 0, len(mvs))
+
+	for _, mv := range mvs {
+		values, ok := mv.MultiValues()
+
+		if !ok || len(values) < 2 {
+			panic(ErrUnexpectedResponseType)
+		}
+
+		start, _ := values[0].Int()
+		end, _ := values[1].Int()
+		cs := ClusterSlot{
+			ClusterSlotRange: ClusterSlotRange{
+				Start: start,
+				End:   end,
+			},
+		}
+
+		for _, v := range values[2:] {
+			node, ok := v.MultiValues()
+
+			if !ok || len(node) < 2 {
+				panic(ErrUnexpectedResponseType)
+			}
+
+			port, _ := node[1].Int()
+			csn := ClusterSlotNode{
+				Addr: net.JoinHostPort(multiValueString(node[0]), strconv.Itoa(port)),
+			}
+
+			if len(node) > 2 {
+				csn.ID = multiValueString(node[2])
+			}
+
+			cs.Nodes = append(cs.Nodes, csn)
+		}
+
+		slots = append(slots, cs)
+	}
+
+	return
+}
@@ -0,0 +1,449 @@
+package redis
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/altstory/go-redis/internal/driver"
+)
+
+// command 代表一个发送给 Redis 的命令。
+// 除了命令参数和应答之外，command 还记录了如何将原始应答解析成更方便使用的 MultiValue，
+// 这样在 transaction 和 pipeline 中的命令也能得到与直接调用时一样的结果。
+type command struct {
+	*driver.Cmd
+	parse replyParser
+}
+
+// replyParser 将原始应答解析成 MakeMultiValue 能接受的值，应答格式不符合预期时 ok 为 false。
+type replyParser func(mv MultiValue) (v interface{}, ok bool)
+
+// newCmd 创建一个不需要额外解析应答的命令。
+func newCmd(args ...interface{}) *command {
+	return &command{
+		Cmd: driver.NewCmd(args...),
+	}
+}
+
+// newCmdWith 创建一个使用 parse 解析应答的命令。
+func newCmdWith(parse replyParser, args ...interface{}) *command {
+	return &command{
+		Cmd:   driver.NewCmd(args...),
+		parse: parse,
+	}
+}
+
+// newBlockingCmd 创建一个会在服务器上阻塞 timeout 时间的命令，读取应答时会相应延长读超时。
+func newBlockingCmd(timeout time.Duration, args ...interface{}) *command {
+	cmd := newCmd(args...)
+	cmd.SetBlockTimeout(timeout)
+	return cmd
+}
+
+// process 执行 cmd 并返回 cmd 本身，执行结果需要用 mustBe* 系列函数解析。
+func process(client driver.Client, cmd *command) *command {
+	client.Process(cmd)
+	return cmd
+}
+
+// appendArgs 将 strs 追加到 args 后面。
+func appendArgs(args []interface{}, strs []string) []interface{} {
+	for _, s := range strs {
+		args = append(args, s)
+	}
+
+	return args
+}
+
+// parseBool 将整数应答或 OK 状态解析成 bool。
+func parseBool(mv MultiValue) (interface{}, bool) {
+	if s, ok := mv.Status(); ok {
+		return s == "OK", true
+	}
+
+	return mv.Bool()
+}
+
+// parseFloat 将 bulk string 形式的浮点数解析成 float64。
+func parseFloat(mv MultiValue) (interface{}, bool) {
+	if f, ok := mv.Float64(); ok {
+		return f, true
+	}
+
+	bs, ok := mv.BulkString()
+
+	if !ok {
+		return nil, false
+	}
+
+	f, err := strconv.ParseFloat(bs.String(), 64)
+
+	if err != nil {
+		return nil, false
+	}
+
+	return f, true
+}
+
+// parseDuration 返回一个将整数应答解析成 time.Duration 的 replyParser，precision 是整数的单位。
+func parseDuration(precision time.Duration) replyParser {
+	return func(mv MultiValue) (interface{}, bool) {
+		n, ok := mv.Int64()
+
+		if !ok {
+			return nil, false
+		}
+
+		return time.Duration(n) * precision, true
+	}
+}
+
+// parsePTTL 解析 PTTL 的应答，PTTL 的特殊值单位是毫秒，这里统一成跟 TTL 一样的 TTLNoExpire 和 TTLNotExist。
+func parsePTTL(mv MultiValue) (interface{}, bool) {
+	n, ok := mv.Int64()
+
+	if !ok {
+		return nil, false
+	}
+
+	switch n {
+	case -1:
+		return TTLNoExpire, true
+	case -2:
+		return TTLNotExist, true
+	}
+
+	return time.Duration(n) * time.Millisecond, true
+}
+
+// parseTime 解析 TIME 的应答，格式是 [unix 秒, 微秒]。
+func parseTime(mv MultiValue) (interface{}, bool) {
+	mvs, ok := mv.MultiValues()
+
+	if !ok || len(mvs) != 2 {
+		return nil, false
+	}
+
+	sec, err := strconv.ParseInt(multiValueString(mvs[0]), 10, 64)
+
+	if err != nil {
+		return nil, false
+	}
+
+	usec, err := strconv.ParseInt(multiValueString(mvs[1]), 10, 64)
+
+	if err != nil {
+		return nil, false
+	}
+
+	return time.Unix(sec, usec*int64(time.Microsecond)), true
+}
+
+// parseKeyAndValues 解析形如 [key1, value1, key2, value2, ...] 的应答，
+// 也兼容 RESP3 下 HRANDFIELD 等命令返回的 [[key1, value1], ...]。
+func parseKeyAndValues(mv MultiValue) (interface{}, bool) {
+	mvs, ok := mv.MultiValues()
+
+	if !ok {
+		return nil, false
+	}
+
+	mvs, ok = flattenPairs(mvs)
+
+	if !ok || len(mvs)%2 != 0 {
+		return nil, false
+	}
+
+	kvs := make(KeyAndValues, 0, len(mvs)/2)
+
+	for i := 0; i < len(mvs); i += 2 {
+		kvs = append(kvs, KeyAndValue{
+			Key:   multiValueString(mvs[i]),
+			Value: multiValueString(mvs[i+1]),
+		})
+	}
+
+	return kvs, true
+}
+
+// parseMemberAndScores 解析形如 [member1, score1, member2, score2, ...] 的应答。
+// 使用 RESP3 时应答的格式是 [[member1, score1], [member2, score2], ...]，会先展开再解析。
+func parseMemberAndScores(mv MultiValue) (interface{}, bool) {
+	mvs, ok := mv.MultiValues()
+
+	if !ok {
+		return nil, false
+	}
+
+	mvs, ok = flattenPairs(mvs)
+
+	if !ok || len(mvs)%2 != 0 {
+		return nil, false
+	}
+
+	mss := make(MemberAndScores, 0, len(mvs)/2)
+
+	for i := 0; i < len(mvs); i += 2 {
+		score, ok := parseFloat(mvs[i+1])
+
+		if !ok {
+			return nil, false
+		}
+
+		mss = append(mss, MemberAndScore{
+			Member: multiValueString(mvs[i]),
+			Score:  score.(float64),
+		})
+	}
+
+	return mss, true
+}
+
+// flattenPairs 将 RESP3 形如 [[a1, b1], [a2, b2], ...] 的应答展开成 [a1, b1, a2, b2, ...]，
+// 如果 mvs 已经是展开的格式则原样返回。
+func flattenPairs(mvs []MultiValue) ([]MultiValue, bool) {
+	if len(mvs) == 0 {
+		return mvs, true
+	}
+
+	if _, ok := mvs[0].data.([]MultiValue); !ok {
+		return mvs, true
+	}
+
+	flattened := make([]MultiValue, 0, len(mvs)*2)
+
+	for _, v := range mvs {
+		pair, ok := v.data.([]MultiValue)
+
+		if !ok || len(pair) != 2 {
+			return nil, false
+		}
+
+		flattened = append(flattened, pair...)
+	}
+
+	return flattened, true
+}
+
+// parseMemberAndScore 解析只包含一个 member 的 [member, score] 应答，空应答会得到零值。
+func parseMemberAndScore(mv MultiValue) (interface{}, bool) {
+	mss, ok := parseMemberAndScores(mv)
+
+	if !ok {
+		return nil, false
+	}
+
+	switch v := mss.(MemberAndScores); len(v) {
+	case 0:
+		return MemberAndScore{}, true
+	case 1:
+		return v[0], true
+	}
+
+	return nil, false
+}
+
+// parseChanAndSubs 解析 PUBSUB NUMSUB 的应答，格式是 [channel1, count1, channel2, count2, ...]。
+func parseChanAndSubs(mv MultiValue) (interface{}, bool) {
+	mvs, ok := mv.MultiValues()
+
+	if !ok || len(mvs)%2 != 0 {
+		return nil, false
+	}
+
+	css := make(ChanAndSubs, 0, len(mvs)/2)
+
+	for i := 0; i < len(mvs); i += 2 {
+		n, ok := mvs[i+1].Int()
+
+		if !ok {
+			return nil, false
+		}
+
+		css = append(css, ChanAndSub{
+			Chan: multiValueString(mvs[i]),
+			Sub:  n,
+		})
+	}
+
+	return css, true
+}
+
+// parseStreamEntries 解析 XRANGE 等命令的应答，格式是 [[id, [field1, value1, ...]], ...]。
+// 已经被删除的 entry 会返回 nil，这些 entry 会被跳过。
+func parseStreamEntries(mv MultiValue) (interface{}, bool) {
+	mvs, ok := mv.MultiValues()
+
+	if !ok {
+		return nil, false
+	}
+
+	ses := make(StreamEntries, 0, len(mvs))
+
+	for _, v := range mvs {
+		if v.IsNil() {
+			continue
+		}
+
+		entry, ok := v.MultiValues()
+
+		if !ok || len(entry) != 2 {
+			return nil, false
+		}
+
+		ses = append(ses, parseStreamEntry(entry))
+	}
+
+	return ses, true
+}
+
+// parseStreamAndEntriesList 解析 XREAD 和 XREADGROUP 的应答，格式是 [[stream, [entry, ...]], ...]。
+// 使用 RESP3 时应答是一个 map，展开之后的格式是 [stream1, [entry, ...], stream2, [entry, ...], ...]。
+func parseStreamAndEntriesList(mv MultiValue) (interface{}, bool) {
+	mvs, ok := mv.MultiValues()
+
+	if !ok {
+		return nil, false
+	}
+
+	mvs, ok = flattenPairs(mvs)
+
+	if !ok || len(mvs)%2 != 0 {
+		return nil, false
+	}
+
+	list := make([]StreamAndEntries, 0, len(mvs)/2)
+
+	for i := 0; i < len(mvs); i += 2 {
+		entries, ok := parseStreamEntries(mvs[i+1])
+
+		if !ok {
+			return nil, false
+		}
+
+		list = append(list, StreamAndEntries{
+			Stream:  multiValueString(mvs[i]),
+			Entries: entries.(StreamEntries),
+		})
+	}
+
+	return list, true
+}
+
+// parseStreamPending 解析 XPENDING 汇总形式的应答，格式是 [count, lower, higher, [[consumer, count], ...]]。
+func parseStreamPending(mv MultiValue) (interface{}, bool) {
+	mvs, ok := mv.MultiValues()
+
+	if !ok || len(mvs) != 4 {
+		return nil, false
+	}
+
+	var sp StreamPending
+	sp.Count, _ = mvs[0].Int()
+	sp.Lower = multiValueString(mvs[1])
+	sp.Higher = multiValueString(mvs[2])
+	consumers, _ := mvs[3].MultiValues()
+
+	if len(consumers) != 0 {
+		sp.Consumers = make(map[string]int, len(consumers))
+
+		for _, c := range consumers {
+			values, ok := c.MultiValues()
+
+			if !ok || len(values) != 2 {
+				return nil, false
+			}
+
+			count, _ := strconv.Atoi(multiValueString(values[1]))
+			sp.Consumers[multiValueString(values[0])] = count
+		}
+	}
+
+	return sp, true
+}
+
+// parseStreamPendingEntries 解析 XPENDING 扩展形式的应答，格式是 [[id, consumer, idle, count], ...]。
+func parseStreamPendingEntries(mv MultiValue) (interface{}, bool) {
+	mvs, ok := mv.MultiValues()
+
+	if !ok {
+		return nil, false
+	}
+
+	entries := make([]StreamPendingEntry, 0, len(mvs))
+
+	for _, v := range mvs {
+		values, ok := v.MultiValues()
+
+		if !ok || len(values) != 4 {
+			return nil, false
+		}
+
+		idle, _ := values[2].Int64()
+		count, _ := values[3].Int()
+		entries = append(entries, StreamPendingEntry{
+			ID:            multiValueString(values[0]),
+			Consumer:      multiValueString(values[1]),
+			Idle:          time.Duration(idle) * time.Millisecond,
+			DeliveryCount: count,
+		})
+	}
+
+	return entries, true
+}
+
+// parseGeoLocationsWith 返回一个解析 GEORADIUS、GEOSEARCH 等命令应答的 replyParser。
+func parseGeoLocationsWith(withCoord, withDist, withHash bool) replyParser {
+	return func(mv MultiValue) (interface{}, bool) {
+		mvs, ok := mv.MultiValues()
+
+		if !ok {
+			return nil, false
+		}
+
+		return parseGeoLocations(mvs, withCoord, withDist, withHash), true
+	}
+}
+
+// parseGeoPositions 解析 GEOPOS 的应答，格式是 [[longitude, latitude], ...]，不存在的 member 对应 nil。
+func parseGeoPositions(mv MultiValue) (interface{}, bool) {
+	mvs, ok := mv.MultiValues()
+
+	if !ok {
+		return nil, false
+	}
+
+	positions := make([]*GeoPos, 0, len(mvs))
+
+	for _, v := range mvs {
+		coord, _ := v.MultiValues()
+
+		if len(coord) != 2 {
+			positions = append(positions, nil)
+			continue
+		}
+
+		pos := MakeGeoPos(parseFloat64(coord[0]), parseFloat64(coord[1]))
+		positions = append(positions, &pos)
+	}
+
+	return positions, true
+}
+
+// parseScanned 解析 SCAN 系列命令的应答，将 bulk string 形式的 cursor 转成整数，
+// 格式是 [cursor, [elem1, elem2, ...]]。
+func parseScanned(mv MultiValue) (interface{}, bool) {
+	mvs, ok := mv.MultiValues()
+
+	if !ok || len(mvs) != 2 {
+		return nil, false
+	}
+
+	cursor, err := strconv.ParseInt(multiValueString(mvs[0]), 10, 64)
+
+	if err != nil {
+		return nil, false
+	}
+
+	return []MultiValue{MakeMultiValue(cursor), mvs[1]}, true
+}
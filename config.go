@@ -21,13 +21,15 @@ const (
 //     - ClientConfig
 //     - ClusterConfig
 //     - FailoverConfig
-//     - RingConfig TODO:
+//     - RingConfig
 //     - SentinelConfig TODO:
-//     - UniversalConfig TODO:
+//     - UniversalConfig
 type Config struct {
-	Client   *ClientConfig   `config:"client"`   // Client 是直连模式的配置。
-	Cluster  *ClusterConfig  `config:"cluster"`  // Cluster 是集群模式的配置。
-	Failover *FailoverConfig `config:"failover"` // Failover 是 failover client 的配置。
+	Client    *ClientConfig    `config:"client"`    // Client 是直连模式的配置。
+	Cluster   *ClusterConfig   `config:"cluster"`   // Cluster 是集群模式的配置。
+	Failover  *FailoverConfig  `config:"failover"`  // Failover 是 failover client 的配置。
+	Ring      *RingConfig      `config:"ring"`      // Ring 是 ring 模式的配置。
+	Universal *UniversalConfig `config:"universal"` // Universal 是自动选择连接模式的配置。
 }
 
 // ClientConfig 代表 Redis 直连模式的配置。
@@ -41,7 +43,10 @@ type ClientConfig struct {
 	ReadTimeout  time.Duration `config:"read_timeout"`  // ReadTimeout 配置读超时，默认是 DefaultReadTimeout。
 	WriteTimeout time.Duration `config:"write_timeout"` // WriteTimeout 配置写超时，默认是 DefaultWriteTimeout。
 
-	PoolSize int `config:"pool_size"` // PoolSize 配置连接池大小。
+	PoolSize   int    `config:"pool_size"`   // PoolSize 配置连接池大小。
+	Protocol   int    `config:"protocol"`    // Protocol 配置 RESP 协议版本，可以是 2 或 3，默认是 3，服务器不支持 RESP3 时自动退回 RESP2。
+	CacheSize  int    `config:"cache_size"`  // CacheSize 配置客户端缓存最多缓存的应答数量，大于 0 时开启基于 CLIENT TRACKING 的客户端缓存，需要 Redis 6 以上版本；缓存在收到失效消息后才会更新，读到的值可能落后于刚刚写入的值，包括自己写入的值。
+	ClientName string `config:"client_name"` // ClientName 配置每个新连接通过 CLIENT SETNAME 设置的名字，使用 Register 时默认是配置的 section 名字。
 }
 
 // ClusterConfig 代表 Redis cluster 配置。
@@ -54,7 +59,10 @@ type ClusterConfig struct {
 	ReadTimeout  time.Duration `config:"read_timeout"`  // ReadTimeout 配置读超时，默认是 DefaultReadTimeout。
 	WriteTimeout time.Duration `config:"write_timeout"` // WriteTimeout 配置写超时，默认是 DefaultWriteTimeout。
 
-	PoolSize int `config:"pool_size"` // PoolSize 配置连接池大小。
+	PoolSize   int    `config:"pool_size"`   // PoolSize 配置连接池大小。
+	Protocol   int    `config:"protocol"`    // Protocol 配置 RESP 协议版本，可以是 2 或 3，默认是 3，服务器不支持 RESP3 时自动退回 RESP2。
+	CacheSize  int    `config:"cache_size"`  // CacheSize 配置客户端缓存最多缓存的应答数量，大于 0 时开启基于 CLIENT TRACKING 的客户端缓存，需要 Redis 6 以上版本；缓存在收到失效消息后才会更新，读到的值可能落后于刚刚写入的值，包括自己写入的值。
+	ClientName string `config:"client_name"` // ClientName 配置每个新连接通过 CLIENT SETNAME 设置的名字，使用 Register 时默认是配置的 section 名字。
 }
 
 // FailoverConfig 代表 Redis failover client 配置。
@@ -68,5 +76,54 @@ type FailoverConfig struct {
 	ReadTimeout  time.Duration `config:"read_timeout"`  // ReadTimeout 配置读超时，默认是 DefaultReadTimeout。
 	WriteTimeout time.Duration `config:"write_timeout"` // WriteTimeout 配置写超时，默认是 DefaultWriteTimeout。
 
-	PoolSize int `config:"pool_size"` // PoolSize 配置连接池大小。
+	PoolSize   int    `config:"pool_size"`   // PoolSize 配置连接池大小。
+	Protocol   int    `config:"protocol"`    // Protocol 配置 RESP 协议版本，可以是 2 或 3，默认是 3，服务器不支持 RESP3 时自动退回 RESP2。
+	CacheSize  int    `config:"cache_size"`  // CacheSize 配置客户端缓存最多缓存的应答数量，大于 0 时开启基于 CLIENT TRACKING 的客户端缓存，需要 Redis 6 以上版本；缓存在收到失效消息后才会更新，读到的值可能落后于刚刚写入的值，包括自己写入的值。
+	ClientName string `config:"client_name"` // ClientName 配置每个新连接通过 CLIENT SETNAME 设置的名字，使用 Register 时默认是配置的 section 名字。
+}
+
+// RingConfig 代表 Redis ring 配置，通过一致性哈希将 key 分布到多个独立的 Redis 服务器上。
+// 不可用的 shard 会被暂时摘除，它负责的 key 会分配到其他 shard 上，因此 ring 模式只适合用于缓存。
+type RingConfig struct {
+	Addrs    map[string]string `config:"addrs"`    // Addrs 配置每个 shard 的名字和地址，一致性哈希使用的是 shard 的名字。
+	Weights  map[string]int    `config:"weights"`  // Weights 配置每个 shard 的权重，默认是 1。
+	Password string            `config:"password"` // Password 配置连接 Redis 的密码。
+	DB       int               `config:"db"`       // DB 配置连接上 Redis 后默认选择的数据库。
+
+	HeartbeatFrequency time.Duration `config:"heartbeat_frequency"` // HeartbeatFrequency 配置检查 shard 是否可用的周期，默认是 500ms。
+
+	DialTimeout  time.Duration `config:"dail_timeout"`  // DialTimeout 配置连接超时，默认是 DefaultDialTimeout。
+	ReadTimeout  time.Duration `config:"read_timeout"`  // ReadTimeout 配置读超时，默认是 DefaultReadTimeout。
+	WriteTimeout time.Duration `config:"write_timeout"` // WriteTimeout 配置写超时，默认是 DefaultWriteTimeout。
+
+	PoolSize   int    `config:"pool_size"`   // PoolSize 配置每个 shard 的连接池大小。
+	Protocol   int    `config:"protocol"`    // Protocol 配置 RESP 协议版本，可以是 2 或 3，默认是 3，服务器不支持 RESP3 时自动退回 RESP2。
+	CacheSize  int    `config:"cache_size"`  // CacheSize 配置客户端缓存最多缓存的应答数量，大于 0 时开启基于 CLIENT TRACKING 的客户端缓存，需要 Redis 6 以上版本；缓存在收到失效消息后才会更新，读到的值可能落后于刚刚写入的值，包括自己写入的值。
+	ClientName string `config:"client_name"` // ClientName 配置每个新连接通过 CLIENT SETNAME 设置的名字，使用 Register 时默认是配置的 section 名字。
+}
+
+// UniversalConfig 代表根据服务器拓扑自动选择连接模式的配置，选择的规则如下：
+//     - 设置了 MasterName：使用 failover client，Addrs 是哨兵地址；
+//     - Addrs 只有一个地址：使用直连模式；
+//     - Addrs 有多个地址：依次向每个地址发送 CLUSTER INFO，如果服务器开启了 cluster 则使用集群模式，否则使用直连模式连接第一个可用的地址。
+//       如果所有地址都无法连接，Factory 的 Conn 会返回包装了 ErrUniversalUnreachable 的错误，下次调用 Conn 时重新探测；
+//       如果认证失败，Conn 直接返回认证错误，不再探测其他地址。
+//
+// 探测服务器拓扑发生在第一次调用 Factory 的 Conn 时，受 Conn 的 ctx 控制，NewFactory 不会连接服务器。
+//
+// 这样同一份配置既可以用于开发环境的单机 Redis，也可以用于线上环境的 Redis cluster。
+type UniversalConfig struct {
+	Addrs      []string `config:"addrs"`       // Addrs 配置 Redis 服务地址，或者设置了 MasterName 时的哨兵地址。
+	MasterName string   `config:"master_name"` // MasterName 配置哨兵中 master 结点的名字，设置之后使用 failover client。
+	Password   string   `config:"password"`    // Password 配置连接 Redis 的密码。
+	DB         int      `config:"db"`          // DB 配置连接上 Redis 后默认选择的数据库，只在直连模式下生效。
+
+	DialTimeout  time.Duration `config:"dail_timeout"`  // DialTimeout 配置连接超时，默认是 DefaultDialTimeout。
+	ReadTimeout  time.Duration `config:"read_timeout"`  // ReadTimeout 配置读超时，默认是 DefaultReadTimeout。
+	WriteTimeout time.Duration `config:"write_timeout"` // WriteTimeout 配置写超时，默认是 DefaultWriteTimeout。
+
+	PoolSize   int    `config:"pool_size"`   // PoolSize 配置连接池大小。
+	Protocol   int    `config:"protocol"`    // Protocol 配置 RESP 协议版本，可以是 2 或 3，默认是 3，服务器不支持 RESP3 时自动退回 RESP2。
+	CacheSize  int    `config:"cache_size"`  // CacheSize 配置客户端缓存最多缓存的应答数量，大于 0 时开启基于 CLIENT TRACKING 的客户端缓存，需要 Redis 6 以上版本；缓存在收到失效消息后才会更新，读到的值可能落后于刚刚写入的值，包括自己写入的值。
+	ClientName string `config:"client_name"` // ClientName 配置每个新连接通过 CLIENT SETNAME 设置的名字，使用 Register 时默认是配置的 section 名字。
 }
@@ -1,6 +1,8 @@
 package redis
 
-import "github.com/altstory/go-redis/internal/driver"
+import (
+	"github.com/altstory/go-redis/internal/driver"
+)
 
 // Connection 代表 Redis 跟连接相关的接口，详见 https://redis.io/commands#connection。
 //
@@ -9,14 +11,62 @@ import "github.com/altstory/go-redis/internal/driver"
 // 可以预计这两个接口应该永远不会用到，因此无需定义。
 //
 // 另外，`AUTH` 接口不做封装，密码验证的工作已经在框架层完成。
+//
+// 由于连接池里的连接是随机选取的，CLIENT 相关的命令只作用于执行这个命令的那个连接，
+// 如果需要给所有连接设置名字，应该使用配置中的 ClientName。
 type Connection interface {
+	ClientGetName() (name string, err error)
+	ClientID() (id int64, err error)
+	ClientInfo() (info ClientInfo, err error)
+	ClientSetName(name string) (err error)
 	Echo(msg string) (echo BulkString, err error)
 	Ping() (err error)
 }
 
+func (r *redisImpl) ClientGetName() (name string, err error) {
+	err = r.do("CLIENT GETNAME", func(client driver.Client) error {
+		var bs BulkString
+		bs, err = mustBeBulkString(client, process(client, newCmd("CLIENT", "GETNAME")))
+		name = bs.String()
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) ClientID() (id int64, err error) {
+	err = r.do("CLIENT ID", func(client driver.Client) error {
+		id, err = mustBeInt64(client, process(client, newCmd("CLIENT", "ID")))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) ClientInfo() (info ClientInfo, err error) {
+	err = r.do("CLIENT INFO", func(client driver.Client) error {
+		var bs BulkString
+		bs, err = mustBeBulkString(client, process(client, newCmd("CLIENT", "INFO")))
+
+		if err != nil {
+			return err
+		}
+
+		info = parseClientInfo(bs.String())
+		return nil
+	})
+	return
+}
+
+func (r *redisImpl) ClientSetName(name string) (err error) {
+	err = r.do("CLIENT SETNAME", func(client driver.Client) error {
+		_, err = mustBeStatus(client, process(client, newCmd("CLIENT", "SETNAME", name)))
+		return err
+	})
+	return
+}
+
 func (r *redisImpl) Echo(msg string) (echo BulkString, err error) {
 	err = r.do("ECHO", func(client driver.Client) error {
-		echo, err = mustBeBulkString(client, client.Echo(msg))
+		echo, err = mustBeBulkString(client, process(client, newCmd("ECHO", msg)))
 		return err
 	})
 	return
@@ -24,7 +74,7 @@ func (r *redisImpl) Echo(msg string) (echo BulkString, err error) {
 
 func (r *redisImpl) Ping() (err error) {
 	err = r.do("PING", func(client driver.Client) error {
-		_, err = mustBeStatus(client, client.Ping())
+		_, err = mustBeStatus(client, process(client, newCmd("PING")))
 		return err
 	})
 	return err
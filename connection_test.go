@@ -0,0 +1,151 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/huandu/go-assert"
+)
+
+func TestClientMethods(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	f := NewFactory(&Config{
+		Client: &ClientConfig{
+			Addr:       testAddr,
+			ClientName: "go-redis-test",
+			PoolSize:   1,
+		},
+	})
+
+	if err := f.Conn(ctx); err != nil {
+		t.Skipf("fail to connect Redis server. [err:%v]", err)
+	}
+
+	defer f.Close()
+	r := f.New(ctx)
+
+	name, err := r.ClientGetName()
+	a.NilError(err)
+	a.Equal(name, "go-redis-test")
+
+	id, err := r.ClientID()
+	a.NilError(err)
+	a.Assert(id > 0)
+
+	info, err := r.ClientInfo()
+	a.NilError(err)
+	a.Equal(info.ID, id)
+	a.Equal(info.Name, "go-redis-test")
+	a.Equal(info.Raw["name"], "go-redis-test")
+
+	infos, err := r.ClientList()
+	a.NilError(err)
+	found := false
+
+	for _, ci := range infos {
+		if ci.ID == id {
+			found = true
+			a.Equal(ci.Addr, info.Addr)
+		}
+	}
+
+	a.Assert(found)
+
+	a.NilError(r.ClientSetName("go-redis-renamed"))
+	name, err = r.ClientGetName()
+	a.NilError(err)
+	a.Equal(name, "go-redis-renamed")
+
+	a.NilError(r.ClientPause(10 * time.Millisecond))
+
+	// 用另一个连接池关掉当前连接。
+	another := factory(t).New(ctx)
+	killed, err := another.ClientKillByFilter("ID", fmt.Sprint(id))
+	a.NilError(err)
+	a.Equal(killed, 1)
+
+	// 连接池会重新建立连接并重新设置名字，第一次使用被关掉的连接可能会失败。
+	name, err = r.ClientGetName()
+
+	if err != nil {
+		name, err = r.ClientGetName()
+	}
+
+	a.NilError(err)
+	a.Equal(name, "go-redis-test")
+}
+
+func TestSetDefaultClientName(t *testing.T) {
+	a := assert.New(t)
+	config := &Config{
+		Client:  &ClientConfig{},
+		Cluster: &ClusterConfig{ClientName: "custom"},
+	}
+	setDefaultClientName(config, "redis")
+	a.Equal(config.Client.ClientName, "redis")
+	a.Equal(config.Cluster.ClientName, "custom")
+}
+
+func TestProtocol(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	key := "protocol-zset"
+	stream := "protocol-stream"
+
+	// 同一个命令在 RESP2 和 RESP3 下应该得到一样的结果。
+	for _, protocol := range []int{2, 3} {
+		f := NewFactory(&Config{
+			Client: &ClientConfig{
+				Addr:     testAddr,
+				PoolSize: 1,
+				Protocol: protocol,
+			},
+		})
+
+		if err := f.Conn(ctx); err != nil {
+			t.Skipf("fail to connect Redis server. [err:%v]", err)
+		}
+
+		r := f.New(ctx)
+		_, err := r.Del(key, stream)
+		a.NilError(err)
+
+		info, err := r.ClientInfo()
+		a.NilError(err)
+		a.Equal(info.Raw["resp"], fmt.Sprint(protocol))
+
+		_, err = r.ZAdd(key, MakeMemberAndScore("m1", 1.5), MakeMemberAndScore("m2", 2))
+		a.NilError(err)
+		mss, err := r.ZRangeWithScores(key, 0, -1)
+		a.NilError(err)
+		a.Equal(mss, MemberAndScores{MakeMemberAndScore("m1", 1.5), MakeMemberAndScore("m2", 2)})
+		score, exists, err := r.ZScore(key, "m1")
+		a.NilError(err)
+		a.Assert(exists)
+		a.Equal(score, 1.5)
+		mss, err = r.ZPopMaxN(key, 2)
+		a.NilError(err)
+		a.Equal(mss, MemberAndScores{MakeMemberAndScore("m2", 2), MakeMemberAndScore("m1", 1.5)})
+
+		id, err := r.XAdd(stream, StreamAutoID, []KeyAndValue{{Key: "f", Value: "v"}})
+		a.NilError(err)
+		streams, err := r.XRead([]string{stream}, []string{"0"})
+		a.NilError(err)
+		a.Equal(len(streams), 1)
+		a.Equal(streams[0].Stream, stream)
+		a.Equal(len(streams[0].Entries), 1)
+		a.Equal(streams[0].Entries[0].ID, id)
+
+		kvs, err := r.ConfigGet("maxmemory")
+		a.NilError(err)
+		a.Equal(len(kvs), 1)
+		a.Equal(kvs[0].Key, "maxmemory")
+
+		_, err = r.Del(key, stream)
+		a.NilError(err)
+		a.NilError(f.Close())
+	}
+}
@@ -4,8 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
-
-	"github.com/go-redis/redis"
+	"net"
+	"sort"
+	"strings"
 
 	"github.com/altstory/go-log"
 	"github.com/altstory/go-redis/internal/driver"
@@ -14,6 +15,9 @@ import (
 
 var (
 	defaultFactory = Register("redis")
+
+	// ErrUniversalUnreachable 代表 UniversalConfig 中所有地址都无法连接，无法判断服务器拓扑。
+	ErrUniversalUnreachable = errors.New("go-redis: fail to detect Redis topology as no server is reachable")
 )
 
 // Factory 管理 Redis 连接池，并提供接口从连接池中取出可用的 Redis 连接。
@@ -22,29 +26,49 @@ type Factory struct {
 
 	addrs  []string
 	client driver.Client
+	cache  *clientCache
 	tested bool
+
+	universal *UniversalConfig // universal 不为 nil 时，连接模式会在第一次调用 Conn 时根据服务器拓扑确定。
 }
 
 // NewFactory 创建一个新的 Redis 连接池。
 func NewFactory(config *Config) *Factory {
 	var client driver.Client
 	var addrs []string
+	var cache *clientCache
 
 	if config.Client != nil {
 		addrs = []string{config.Client.Addr}
-		client = newClientFromClientConfig(config.Client)
+		cache = newClientCache(config.Client.CacheSize)
+		client = newClientFromClientConfig(config.Client, cache)
 	} else if config.Cluster != nil {
 		addrs = append(addrs, config.Cluster.Addrs...)
-		client = newClientFromClusterConfig(config.Cluster)
+		cache = newClientCache(config.Cluster.CacheSize)
+		client = newClientFromClusterConfig(config.Cluster, cache)
+	} else if config.Failover != nil {
+		addrs = append(addrs, config.Failover.SentinelAddrs...)
+		cache = newClientCache(config.Failover.CacheSize)
+		client = newClientFromFailoverConfig(config.Failover, cache)
+	} else if config.Ring != nil {
+		addrs = ringAddrs(config.Ring)
+		cache = newClientCache(config.Ring.CacheSize)
+		client = newClientFromRingConfig(config.Ring, cache)
+	} else if config.Universal != nil {
+		return &Factory{
+			addrs:     config.Universal.Addrs,
+			universal: config.Universal,
+		}
 	}
 
 	return &Factory{
 		addrs:  addrs,
 		client: client,
+		cache:  cache,
 	}
 }
 
-func newClientFromClientConfig(c *ClientConfig) driver.Client {
+func newClientFromClientConfig(c *ClientConfig, cache *clientCache) driver.Client {
 	dialTimeout := c.DialTimeout
 	readTimeout := c.ReadTimeout
 	writeTimeout := c.WriteTimeout
@@ -61,7 +85,7 @@ func newClientFromClientConfig(c *ClientConfig) driver.Client {
 		writeTimeout = DefaultWriteTimeout
 	}
 
-	return redis.NewClient(&redis.Options{
+	return driver.NewSingleClient(&driver.Options{
 		Addr:     c.Addr,
 		Password: c.Password,
 		DB:       c.DB,
@@ -71,10 +95,15 @@ func newClientFromClientConfig(c *ClientConfig) driver.Client {
 		WriteTimeout: writeTimeout,
 
 		PoolSize: c.PoolSize,
+		Protocol: c.Protocol,
+
+		OnConnect: onConnect(c.ClientName),
+
+		OnInvalidate: onInvalidate(cache),
 	})
 }
 
-func newClientFromClusterConfig(c *ClusterConfig) driver.Client {
+func newClientFromClusterConfig(c *ClusterConfig, cache *clientCache) driver.Client {
 	dialTimeout := c.DialTimeout
 	readTimeout := c.ReadTimeout
 	writeTimeout := c.WriteTimeout
@@ -91,7 +120,7 @@ func newClientFromClusterConfig(c *ClusterConfig) driver.Client {
 		writeTimeout = DefaultWriteTimeout
 	}
 
-	return redis.NewClusterClient(&redis.ClusterOptions{
+	return driver.NewClusterClient(&driver.ClusterOptions{
 		Addrs:    c.Addrs,
 		Password: c.Password,
 
@@ -100,10 +129,15 @@ func newClientFromClusterConfig(c *ClusterConfig) driver.Client {
 		WriteTimeout: writeTimeout,
 
 		PoolSize: c.PoolSize,
+		Protocol: c.Protocol,
+
+		OnConnect: onConnect(c.ClientName),
+
+		OnInvalidate: onInvalidate(cache),
 	})
 }
 
-func newClientFromFailoverConfig(c *FailoverConfig) driver.Client {
+func newClientFromFailoverConfig(c *FailoverConfig, cache *clientCache) driver.Client {
 	dialTimeout := c.DialTimeout
 	readTimeout := c.ReadTimeout
 	writeTimeout := c.WriteTimeout
@@ -120,7 +154,7 @@ func newClientFromFailoverConfig(c *FailoverConfig) driver.Client {
 		writeTimeout = DefaultWriteTimeout
 	}
 
-	return redis.NewFailoverClient(&redis.FailoverOptions{
+	return driver.NewFailoverClient(&driver.FailoverOptions{
 		MasterName:    c.MasterName,
 		SentinelAddrs: c.SentinelAddrs,
 		Password:      c.Password,
@@ -130,20 +164,269 @@ func newClientFromFailoverConfig(c *FailoverConfig) driver.Client {
 		WriteTimeout: writeTimeout,
 
 		PoolSize: c.PoolSize,
+		Protocol: c.Protocol,
+
+		OnConnect: onConnect(c.ClientName),
+
+		OnInvalidate: onInvalidate(cache),
 	})
 }
 
+func newClientFromRingConfig(c *RingConfig, cache *clientCache) driver.Client {
+	dialTimeout := c.DialTimeout
+	readTimeout := c.ReadTimeout
+	writeTimeout := c.WriteTimeout
+
+	if dialTimeout == 0 {
+		dialTimeout = DefaultDialTimeout
+	}
+
+	if readTimeout == 0 {
+		readTimeout = DefaultReadTimeout
+	}
+
+	if writeTimeout == 0 {
+		writeTimeout = DefaultWriteTimeout
+	}
+
+	return driver.NewRingClient(&driver.RingOptions{
+		Addrs:    c.Addrs,
+		Weights:  c.Weights,
+		Password: c.Password,
+		DB:       c.DB,
+
+		HeartbeatFrequency: c.HeartbeatFrequency,
+
+		DialTimeout:  dialTimeout,
+		ReadTimeout:  readTimeout,
+		WriteTimeout: writeTimeout,
+
+		PoolSize: c.PoolSize,
+		Protocol: c.Protocol,
+
+		OnConnect: onConnect(c.ClientName),
+
+		OnInvalidate: onInvalidate(cache),
+	})
+}
+
+// ringAddrs 返回 ring 中所有 shard 的地址，按照 shard 名字排序。
+func ringAddrs(c *RingConfig) []string {
+	names := make([]string, 0, len(c.Addrs))
+
+	for name := range c.Addrs {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	addrs := make([]string, 0, len(names))
+
+	for _, name := range names {
+		addrs = append(addrs, c.Addrs[name])
+	}
+
+	return addrs
+}
+
+// resolveUniversalConfig 根据服务器拓扑将 UniversalConfig 转换成具体的配置，探测服务器拓扑时受 ctx 控制。
+func resolveUniversalConfig(ctx context.Context, c *UniversalConfig) (*Config, error) {
+	if c.MasterName != "" {
+		return &Config{
+			Failover: &FailoverConfig{
+				MasterName:    c.MasterName,
+				SentinelAddrs: c.Addrs,
+				Password:      c.Password,
+
+				DialTimeout:  c.DialTimeout,
+				ReadTimeout:  c.ReadTimeout,
+				WriteTimeout: c.WriteTimeout,
+
+				PoolSize:   c.PoolSize,
+				Protocol:   c.Protocol,
+				CacheSize:  c.CacheSize,
+				ClientName: c.ClientName,
+			},
+		}, nil
+	}
+
+	addr := ""
+
+	if len(c.Addrs) != 0 {
+		addr = c.Addrs[0]
+	}
+
+	if len(c.Addrs) > 1 {
+		standalone, cluster, err := probeTopology(ctx, c)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if cluster {
+			return &Config{
+				Cluster: &ClusterConfig{
+					Addrs:    c.Addrs,
+					Password: c.Password,
+
+					DialTimeout:  c.DialTimeout,
+					ReadTimeout:  c.ReadTimeout,
+					WriteTimeout: c.WriteTimeout,
+
+					PoolSize:   c.PoolSize,
+					Protocol:   c.Protocol,
+					CacheSize:  c.CacheSize,
+					ClientName: c.ClientName,
+				},
+			}, nil
+		}
+
+		addr = standalone
+	}
+
+	return &Config{
+		Client: &ClientConfig{
+			Addr:     addr,
+			Password: c.Password,
+			DB:       c.DB,
+
+			DialTimeout:  c.DialTimeout,
+			ReadTimeout:  c.ReadTimeout,
+			WriteTimeout: c.WriteTimeout,
+
+			PoolSize:   c.PoolSize,
+			Protocol:   c.Protocol,
+			CacheSize:  c.CacheSize,
+			ClientName: c.ClientName,
+		},
+	}, nil
+}
+
+// probeTopology 依次向 c.Addrs 中的每个地址发送 CLUSTER INFO，直到有服务器给出应答。
+// 如果服务器开启了 cluster，cluster 为 true，否则 addr 是这个服务器的地址。
+// 如果所有地址都无法连接，返回包装了最后一个错误的 ErrUniversalUnreachable；如果 ctx 被取消，返回 ctx.Err()。
+// 认证失败时所有地址使用的都是同样的密码，不再继续探测，直接返回认证错误。
+func probeTopology(ctx context.Context, c *UniversalConfig) (addr string, cluster bool, err error) {
+	dialTimeout := c.DialTimeout
+	readTimeout := c.ReadTimeout
+
+	if dialTimeout == 0 {
+		dialTimeout = DefaultDialTimeout
+	}
+
+	if readTimeout == 0 {
+		readTimeout = DefaultReadTimeout
+	}
+
+	var lastErr error
+
+	for i := range c.Addrs {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+
+		server := c.Addrs[i]
+		client := driver.NewSingleClient(&driver.Options{
+			Addr:     server,
+			Password: c.Password,
+			Protocol: c.Protocol,
+
+			Dialer: func() (net.Conn, error) {
+				dialer := &net.Dialer{
+					Timeout: dialTimeout,
+				}
+				return dialer.DialContext(ctx, "tcp", server)
+			},
+			ReadTimeout: readTimeout,
+
+			PoolSize: 1,
+		})
+		cmd := driver.NewCmd("CLUSTER", "INFO")
+		cmd.SetInterrupt(ctx.Done())
+		e := client.Process(cmd)
+		client.Close()
+
+		if e == nil {
+			cluster = true
+			return
+		}
+
+		if isClusterDisabled(e) {
+			addr = server
+			return
+		}
+
+		if isAuthFailed(e) {
+			err = e
+			return
+		}
+
+		lastErr = e
+	}
+
+	if err = ctx.Err(); err == nil {
+		err = fmt.Errorf("%w: %v", ErrUniversalUnreachable, lastErr)
+	}
+
+	return
+}
+
+// isClusterDisabled 判断 CLUSTER INFO 的错误是否代表服务器没有开启 cluster。
+func isClusterDisabled(err error) bool {
+	e, ok := err.(driver.Error)
+
+	if !ok {
+		return false
+	}
+
+	msg := strings.ToLower(string(e))
+	return strings.Contains(msg, "cluster support disabled") || strings.HasPrefix(msg, "err unknown command")
+}
+
+// isAuthFailed 判断 err 是否代表服务器拒绝了认证。
+func isAuthFailed(err error) bool {
+	e, ok := err.(driver.Error)
+
+	if !ok {
+		return false
+	}
+
+	msg := string(e)
+	return strings.HasPrefix(msg, "NOAUTH") || strings.HasPrefix(msg, "WRONGPASS")
+}
+
+// onConnect 返回一个在新连接建立时调用的函数，用于设置连接的名字。
+func onConnect(name string) func(*driver.Conn) error {
+	if name == "" {
+		return nil
+	}
+
+	return func(cn *driver.Conn) error {
+		return cn.Process(driver.NewCmd("CLIENT", "SETNAME", name))
+	}
+}
+
 // Conn 连接 Redis 服务器并测试其可用性。
 func (f *Factory) Conn(ctx context.Context) error {
 	if f.unavailable {
 		return errors.New("go-redis: factory is not initialized")
 	}
 
+	if f.client == nil && f.universal != nil {
+		config, err := resolveUniversalConfig(ctx, f.universal)
+
+		if err != nil {
+			return err
+		}
+
+		resolved := NewFactory(config)
+		f.addrs, f.client, f.cache = resolved.addrs, resolved.client, resolved.cache
+	}
+
 	if f.client == nil {
 		return errors.New("go-redis: factory is not initialized")
 	}
 
-	if err := f.client.Ping().Err(); err != nil {
+	if err := f.client.Process(driver.NewCmd("PING")); err != nil {
 		return errors.New("go-redis: fail to connect Redis")
 	}
 
@@ -162,7 +445,9 @@ func (f *Factory) New(ctx context.Context) Redis {
 		return nil
 	}
 
-	return newRedis(ctx, f.client)
+	r := newRedis(ctx, f.client)
+	r.cache = f.cache
+	return r
 }
 
 // Close 关闭连接池。
@@ -187,10 +472,11 @@ func Register(section string) **Factory {
 			return fmt.Errorf("go-redis: missing Redis config `[%v]`", section)
 		}
 
-		if config.Client == nil && config.Cluster == nil && config.Failover == nil {
+		if config.Client == nil && config.Cluster == nil && config.Failover == nil && config.Ring == nil && config.Universal == nil {
 			return fmt.Errorf("go-redis: fail to init Redis as there is no valid config in `[%v]`", section)
 		}
 
+		setDefaultClientName(config, section)
 		f := NewFactory(config)
 
 		if err := f.Conn(ctx); err != nil {
@@ -200,6 +486,10 @@ func Register(section string) **Factory {
 				log.Errorf(ctx, "err=%v||addr=%v||section=%v||go-redis: fail to init Redis in cluster mode", err, config.Cluster.Addrs, section)
 			} else if config.Failover != nil {
 				log.Errorf(ctx, "err=%v||master_name=%v||sentinel_addrs=%v||section=%v||go-redis: fail to init Redis in failover mode", err, config.Failover.MasterName, config.Failover.SentinelAddrs, section)
+			} else if config.Ring != nil {
+				log.Errorf(ctx, "err=%v||addrs=%v||section=%v||go-redis: fail to init Redis in ring mode", err, config.Ring.Addrs, section)
+			} else if config.Universal != nil {
+				log.Errorf(ctx, "err=%v||addrs=%v||master_name=%v||section=%v||go-redis: fail to init Redis in universal mode", err, config.Universal.Addrs, config.Universal.MasterName, section)
 			}
 
 			return err
@@ -211,6 +501,10 @@ func Register(section string) **Factory {
 			log.Tracef(ctx, "addr=%v||section=%v||go-redis: redis is connected", config.Cluster.Addrs, section)
 		} else if config.Failover != nil {
 			log.Tracef(ctx, "master_name=%v||sentinel_addrs=%v||section=%v||go-redis: redis is connected", config.Failover.MasterName, config.Failover.SentinelAddrs, section)
+		} else if config.Ring != nil {
+			log.Tracef(ctx, "addrs=%v||section=%v||go-redis: redis is connected", config.Ring.Addrs, section)
+		} else if config.Universal != nil {
+			log.Tracef(ctx, "addrs=%v||master_name=%v||section=%v||go-redis: redis is connected", config.Universal.Addrs, config.Universal.MasterName, section)
 		}
 
 		initMetrics()
@@ -220,3 +514,26 @@ func Register(section string) **Factory {
 
 	return &factory
 }
+
+// setDefaultClientName 将没有设置 ClientName 的配置默认设置为 section 名字。
+func setDefaultClientName(config *Config, section string) {
+	if config.Client != nil && config.Client.ClientName == "" {
+		config.Client.ClientName = section
+	}
+
+	if config.Cluster != nil && config.Cluster.ClientName == "" {
+		config.Cluster.ClientName = section
+	}
+
+	if config.Failover != nil && config.Failover.ClientName == "" {
+		config.Failover.ClientName = section
+	}
+
+	if config.Ring != nil && config.Ring.ClientName == "" {
+		config.Ring.ClientName = section
+	}
+
+	if config.Universal != nil && config.Universal.ClientName == "" {
+		config.Universal.ClientName = section
+	}
+}
@@ -1,6 +1,7 @@
 package redis
 
 import (
+	"net"
 	"time"
 
 	"github.com/altstory/go-redis/internal/driver"
@@ -10,31 +11,41 @@ import (
 //
 // 注意，WAIT 不是用户使用的命令，这里不支持。
 type Generic interface {
+	Copy(src string, dst string, options ...CopyOption) (copied bool, err error)
 	Del(keys ...string) (deleted int, err error)
 	Dump(key string) (value BulkString, err error)
 	Exists(keys ...string) (existing int, err error)
 	Expire(key string, timeout time.Duration) (isSet bool, err error)
 	ExpireAt(key string, t time.Time) (isSet bool, err error)
 	Keys(pattern string) (keys []BulkString, err error)
-
-	// TODO: Migrate
-	// TODO: Move
-	// TODO: Object
-
+	Migrate(addr string, keys []string, db int, timeout time.Duration, options ...MigrateOption) (migrated bool, err error)
+	Move(key string, db int) (moved bool, err error)
+	ObjectEncoding(key string) (encoding ObjectEncoding, exists bool, err error)
+	ObjectFreq(key string) (freq int, exists bool, err error)
+	ObjectIdleTime(key string) (idle time.Duration, exists bool, err error)
+	ObjectRefCount(key string) (refCount int, exists bool, err error)
 	Persist(key string) (persisted bool, err error)
+	PExpire(key string, timeout time.Duration) (isSet bool, err error)
+	PExpireAt(key string, t time.Time) (isSet bool, err error)
+	PTTL(key string) (ttl time.Duration, err error)
 	RandomKey() (key BulkString, err error)
 	Rename(old, new string) (err error)
 	RenameNX(old, new string) (renamed bool, err error)
-
-	// TODO: Restore
-	// TODO: Store
-
+	Restore(key string, ttl time.Duration, value string, options ...RestoreOption) (err error) // ttl 为 0 代表不过期
+	Sort(key string, options ...SortOption) (values []BulkString, err error)
+	SortStore(key string, dst string, options ...SortOption) (l int, err error) // SORT key ... STORE dst
 	Touch(keys ...string) (touched int, err error)
 	TTL(key string) (ttl time.Duration, err error)
 	Type(key string) (keyType KeyType, err error)
 	Unlink(keys ...string) (unlinked int, err error)
 }
 
+// TTL 和 PTTL 在 key 没有过期时间或者 key 不存在时返回的特殊值。
+const (
+	TTLNoExpire time.Duration = -1 * time.Second // TTLNoExpire 代表 key 存在但没有设置过期时间。
+	TTLNotExist time.Duration = -2 * time.Second // TTLNotExist 代表 key 不存在。
+)
+
 // KeyType 代表 Redis key 所对应的类型。
 type KeyType string
 
@@ -48,13 +59,46 @@ const (
 	TypeStream KeyType = "stream"
 )
 
+// ObjectEncoding 代表 Redis 内部存储 value 时使用的编码。
+type ObjectEncoding string
+
+// 所有 Redis 内部编码，不同版本的 Redis 会使用其中不同的编码。
+const (
+	EncodingRaw        ObjectEncoding = "raw"
+	EncodingInt        ObjectEncoding = "int"
+	EncodingEmbStr     ObjectEncoding = "embstr"
+	EncodingHashTable  ObjectEncoding = "hashtable"
+	EncodingIntSet     ObjectEncoding = "intset"
+	EncodingSkipList   ObjectEncoding = "skiplist"
+	EncodingQuickList  ObjectEncoding = "quicklist"
+	EncodingListPack   ObjectEncoding = "listpack"
+	EncodingZipList    ObjectEncoding = "ziplist"
+	EncodingLinkedList ObjectEncoding = "linkedlist"
+	EncodingStream     ObjectEncoding = "stream"
+)
+
+func (r *redisImpl) Copy(src string, dst string, options ...CopyOption) (copied bool, err error) {
+	args := make([]interface{}, 0, 6) // COPY 最多有这么多参数。
+	args = append(args, "COPY", src, dst)
+
+	for _, opt := range options {
+		args = append(args, opt.Args()...)
+	}
+
+	err = r.do("COPY", func(client driver.Client) error {
+		copied, err = mustBeBool(client, process(client, newCmdWith(parseBool, args...)))
+		return err
+	})
+	return
+}
+
 func (r *redisImpl) Del(keys ...string) (deleted int, err error) {
 	if len(keys) == 0 {
 		return
 	}
 
 	err = r.do("DEL", func(client driver.Client) error {
-		deleted, err = mustBeInt(client, client.Del(keys...))
+		deleted, err = mustBeInt(client, process(client, newCmd(appendArgs([]interface{}{"DEL"}, keys)...)))
 		return err
 	})
 	return
@@ -62,7 +106,7 @@ func (r *redisImpl) Del(keys ...string) (deleted int, err error) {
 
 func (r *redisImpl) Dump(key string) (value BulkString, err error) {
 	err = r.do("DUMP", func(client driver.Client) error {
-		value, err = mustBeBulkString(client, client.Dump(key))
+		value, err = mustBeBulkString(client, process(client, newCmd("DUMP", key)))
 		return err
 	})
 	return
@@ -74,7 +118,7 @@ func (r *redisImpl) Exists(keys ...string) (existing int, err error) {
 	}
 
 	err = r.do("EXISTS", func(client driver.Client) error {
-		existing, err = mustBeInt(client, client.Exists(keys...))
+		existing, err = mustBeInt(client, process(client, newCmd(appendArgs([]interface{}{"EXISTS"}, keys)...)))
 		return err
 	})
 	return
@@ -82,7 +126,7 @@ func (r *redisImpl) Exists(keys ...string) (existing int, err error) {
 
 func (r *redisImpl) Expire(key string, timeout time.Duration) (isSet bool, err error) {
 	err = r.do("EXPIRE", func(client driver.Client) error {
-		isSet, err = mustBeBool(client, client.Expire(key, timeout))
+		isSet, err = mustBeBool(client, process(client, newCmdWith(parseBool, "EXPIRE", key, int64(timeout/time.Second))))
 		return err
 	})
 	return
@@ -90,7 +134,7 @@ func (r *redisImpl) Expire(key string, timeout time.Duration) (isSet bool, err e
 
 func (r *redisImpl) ExpireAt(key string, t time.Time) (isSet bool, err error) {
 	err = r.do("EXPIREAT", func(client driver.Client) error {
-		isSet, err = mustBeBool(client, client.ExpireAt(key, t))
+		isSet, err = mustBeBool(client, process(client, newCmdWith(parseBool, "EXPIREAT", key, t.Unix())))
 		return err
 	})
 	return
@@ -98,7 +142,95 @@ func (r *redisImpl) ExpireAt(key string, t time.Time) (isSet bool, err error) {
 
 func (r *redisImpl) Keys(pattern string) (keys []BulkString, err error) {
 	err = r.do("KEYS", func(client driver.Client) error {
-		keys, err = mustBeBulkStrings(client, client.Keys(pattern))
+		keys, err = mustBeBulkStrings(client, process(client, newCmd("KEYS", pattern)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) Migrate(addr string, keys []string, db int, timeout time.Duration, options ...MigrateOption) (migrated bool, err error) {
+	if len(keys) == 0 {
+		return
+	}
+
+	host, port, err := net.SplitHostPort(addr)
+
+	if err != nil {
+		return
+	}
+
+	key := keys[0]
+
+	// 迁移多个 key 的时候需要使用 KEYS 选项，并且 key 参数必须为空字符串。
+	if len(keys) > 1 {
+		key = ""
+	}
+
+	args := make([]interface{}, 0, 10+len(keys))
+	args = append(args, "MIGRATE", host, port, key, db, int64(timeout/time.Millisecond))
+
+	for _, opt := range options {
+		args = append(args, opt.Args()...)
+	}
+
+	if len(keys) > 1 {
+		args = append(args, "KEYS")
+
+		for _, k := range keys {
+			args = append(args, k)
+		}
+	}
+
+	err = r.do("MIGRATE", func(client driver.Client) error {
+		var status string
+		status, err = mustBeStatus(client, process(client, newCmd(args...)))
+
+		// 所有 key 都不存在的时候 Redis 返回 NOKEY。
+		migrated = status == "OK"
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) Move(key string, db int) (moved bool, err error) {
+	err = r.do("MOVE", func(client driver.Client) error {
+		moved, err = mustBeBool(client, process(client, newCmdWith(parseBool, "MOVE", key, db)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) ObjectEncoding(key string) (encoding ObjectEncoding, exists bool, err error) {
+	err = r.do("OBJECT ENCODING", func(client driver.Client) error {
+		var bs BulkString
+		bs, exists, err = mustBeBulkStringOrNil(client, process(client, newCmd("OBJECT", "ENCODING", key)))
+		encoding = ObjectEncoding(bs.String())
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) ObjectFreq(key string) (freq int, exists bool, err error) {
+	err = r.do("OBJECT FREQ", func(client driver.Client) error {
+		freq, exists, err = mustBeIntOrNil(client, process(client, newCmd("OBJECT", "FREQ", key)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) ObjectIdleTime(key string) (idle time.Duration, exists bool, err error) {
+	err = r.do("OBJECT IDLETIME", func(client driver.Client) error {
+		var seconds int64
+		seconds, exists, err = mustBeInt64OrNil(client, process(client, newCmd("OBJECT", "IDLETIME", key)))
+		idle = time.Duration(seconds) * time.Second
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) ObjectRefCount(key string) (refCount int, exists bool, err error) {
+	err = r.do("OBJECT REFCOUNT", func(client driver.Client) error {
+		refCount, exists, err = mustBeIntOrNil(client, process(client, newCmd("OBJECT", "REFCOUNT", key)))
 		return err
 	})
 	return
@@ -106,7 +238,31 @@ func (r *redisImpl) Keys(pattern string) (keys []BulkString, err error) {
 
 func (r *redisImpl) Persist(key string) (persisted bool, err error) {
 	err = r.do("PERSIST", func(client driver.Client) error {
-		persisted, err = mustBeBool(client, client.Persist(key))
+		persisted, err = mustBeBool(client, process(client, newCmdWith(parseBool, "PERSIST", key)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) PExpire(key string, timeout time.Duration) (isSet bool, err error) {
+	err = r.do("PEXPIRE", func(client driver.Client) error {
+		isSet, err = mustBeBool(client, process(client, newCmdWith(parseBool, "PEXPIRE", key, int64(timeout/time.Millisecond))))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) PExpireAt(key string, t time.Time) (isSet bool, err error) {
+	err = r.do("PEXPIREAT", func(client driver.Client) error {
+		isSet, err = mustBeBool(client, process(client, newCmdWith(parseBool, "PEXPIREAT", key, t.UnixNano()/int64(time.Millisecond))))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) PTTL(key string) (ttl time.Duration, err error) {
+	err = r.do("PTTL", func(client driver.Client) error {
+		ttl, err = mustBeDuration(client, process(client, newCmdWith(parsePTTL, "PTTL", key)))
 		return err
 	})
 	return
@@ -114,7 +270,7 @@ func (r *redisImpl) Persist(key string) (persisted bool, err error) {
 
 func (r *redisImpl) RandomKey() (key BulkString, err error) {
 	err = r.do("RANDOMKEY", func(client driver.Client) error {
-		key, err = mustBeBulkString(client, client.RandomKey())
+		key, err = mustBeBulkString(client, process(client, newCmd("RANDOMKEY")))
 		return err
 	})
 	return
@@ -122,7 +278,7 @@ func (r *redisImpl) RandomKey() (key BulkString, err error) {
 
 func (r *redisImpl) Rename(old, new string) (err error) {
 	err = r.do("RENAME", func(client driver.Client) error {
-		_, err = mustBeStatus(client, client.Rename(old, new))
+		_, err = mustBeStatus(client, process(client, newCmd("RENAME", old, new)))
 		return err
 	})
 	return
@@ -130,7 +286,62 @@ func (r *redisImpl) Rename(old, new string) (err error) {
 
 func (r *redisImpl) RenameNX(old, new string) (renamed bool, err error) {
 	err = r.do("RENAMENX", func(client driver.Client) error {
-		renamed, err = mustBeBool(client, client.RenameNX(old, new))
+		renamed, err = mustBeBool(client, process(client, newCmdWith(parseBool, "RENAMENX", old, new)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) Restore(key string, ttl time.Duration, value string, options ...RestoreOption) (err error) {
+	ms := int64(ttl / time.Millisecond)
+
+	for _, opt := range options {
+		if opt.t == restoreOptionAbsTTL {
+			ms = opt.opt.(time.Time).UnixNano() / int64(time.Millisecond)
+		}
+	}
+
+	args := make([]interface{}, 0, 10) // RESTORE 最多有这么多参数。
+	args = append(args, "RESTORE", key, ms, value)
+
+	for _, opt := range options {
+		args = append(args, opt.Args()...)
+	}
+
+	err = r.do("RESTORE", func(client driver.Client) error {
+		_, err = mustBeStatus(client, process(client, newCmd(args...)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) Sort(key string, options ...SortOption) (values []BulkString, err error) {
+	args := make([]interface{}, 0, 2+3*len(options))
+	args = append(args, "SORT", key)
+
+	for _, opt := range options {
+		args = append(args, opt.Args()...)
+	}
+
+	err = r.do("SORT", func(client driver.Client) error {
+		values, err = mustBeBulkStrings(client, process(client, newCmd(args...)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) SortStore(key string, dst string, options ...SortOption) (l int, err error) {
+	args := make([]interface{}, 0, 4+3*len(options))
+	args = append(args, "SORT", key)
+
+	for _, opt := range options {
+		args = append(args, opt.Args()...)
+	}
+
+	args = append(args, "STORE", dst)
+
+	err = r.do("SORT", func(client driver.Client) error {
+		l, err = mustBeInt(client, process(client, newCmd(args...)))
 		return err
 	})
 	return
@@ -142,7 +353,7 @@ func (r *redisImpl) Touch(keys ...string) (touched int, err error) {
 	}
 
 	err = r.do("TOUCH", func(client driver.Client) error {
-		touched, err = mustBeInt(client, client.Touch(keys...))
+		touched, err = mustBeInt(client, process(client, newCmd(appendArgs([]interface{}{"TOUCH"}, keys)...)))
 		return err
 	})
 	return
@@ -150,7 +361,7 @@ func (r *redisImpl) Touch(keys ...string) (touched int, err error) {
 
 func (r *redisImpl) TTL(key string) (ttl time.Duration, err error) {
 	err = r.do("TTL", func(client driver.Client) error {
-		ttl, err = mustBeDuration(client, client.TTL(key))
+		ttl, err = mustBeDuration(client, process(client, newCmdWith(parseDuration(time.Second), "TTL", key)))
 		return err
 	})
 	return
@@ -159,7 +370,7 @@ func (r *redisImpl) TTL(key string) (ttl time.Duration, err error) {
 func (r *redisImpl) Type(key string) (keyType KeyType, err error) {
 	err = r.do("TYPE", func(client driver.Client) error {
 		var t string
-		t, err = mustBeStatus(client, client.Type(key))
+		t, err = mustBeStatus(client, process(client, newCmd("TYPE", key)))
 		keyType = KeyType(t)
 		return err
 	})
@@ -172,7 +383,7 @@ func (r *redisImpl) Unlink(keys ...string) (unlinked int, err error) {
 	}
 
 	err = r.do("UNLINK", func(client driver.Client) error {
-		unlinked, err = mustBeInt(client, client.Unlink(keys...))
+		unlinked, err = mustBeInt(client, process(client, newCmd(appendArgs([]interface{}{"UNLINK"}, keys)...)))
 		return err
 	})
 	return
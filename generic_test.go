@@ -0,0 +1,205 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/huandu/go-assert"
+)
+
+func TestGenericMethods(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	f := factory(t)
+	r := f.New(ctx)
+	resetRedis(t, r)
+
+	a.NilError(r.Set("generic-key", "v"))
+
+	ttl, err := r.PTTL("generic-key")
+	a.NilError(err)
+	a.Equal(ttl, TTLNoExpire)
+	ttl, err = r.TTL("generic-key")
+	a.NilError(err)
+	a.Equal(ttl, TTLNoExpire)
+	ttl, err = r.PTTL("generic-not-exist")
+	a.NilError(err)
+	a.Equal(ttl, TTLNotExist)
+
+	// pipeline 中的 PTTL 跟直接调用得到一样的特殊值。
+	mvs, err := r.Pipeline(func(p Redis) error {
+		p.PTTL("generic-key")
+		p.PTTL("generic-not-exist")
+		return nil
+	})
+	a.NilError(err)
+	a.Equal(len(mvs), 2)
+	ttl, ok := mvs[0].Duration()
+	a.Assert(ok)
+	a.Equal(ttl, TTLNoExpire)
+	ttl, ok = mvs[1].Duration()
+	a.Assert(ok)
+	a.Equal(ttl, TTLNotExist)
+
+	isSet, err := r.PExpire("generic-key", 1500*time.Millisecond)
+	a.NilError(err)
+	a.Assert(isSet)
+	ttl, err = r.PTTL("generic-key")
+	a.NilError(err)
+	a.Assert(ttl > time.Second && ttl <= 1500*time.Millisecond)
+
+	isSet, err = r.PExpireAt("generic-key", time.Now().Add(time.Minute))
+	a.NilError(err)
+	a.Assert(isSet)
+	ttl, err = r.PTTL("generic-key")
+	a.NilError(err)
+	a.Assert(ttl > 50*time.Second && ttl <= time.Minute)
+
+	persisted, err := r.Persist("generic-key")
+	a.NilError(err)
+	a.Assert(persisted)
+	ttl, err = r.PTTL("generic-key")
+	a.NilError(err)
+	a.Equal(ttl, TTLNoExpire)
+
+	copied, err := r.Copy("generic-key", "generic-copy")
+	a.NilError(err)
+	a.Assert(copied)
+	copied, err = r.Copy("generic-key", "generic-copy")
+	a.NilError(err)
+	a.Assert(!copied)
+	a.NilError(r.Set("generic-key", "v2"))
+	copied, err = r.Copy("generic-key", "generic-copy", CopyReplace())
+	a.NilError(err)
+	a.Assert(copied)
+	value, err := r.Get("generic-copy")
+	a.NilError(err)
+	a.Equal(value.String(), "v2")
+
+	copied, err = r.Copy("generic-key", "generic-copy", CopyDB(1))
+	a.NilError(err)
+	a.Assert(copied)
+	moved, err := r.Move("generic-copy", 1)
+	a.NilError(err)
+	a.Assert(!moved)
+	_, err = r.Del("generic-copy")
+	a.NilError(err)
+	moved, err = r.Move("generic-key", 1)
+	a.NilError(err)
+	a.Assert(moved)
+	existing, err := r.Exists("generic-key")
+	a.NilError(err)
+	a.Equal(existing, 0)
+
+	// 用 Dump 和 Restore 复制一个 key。
+	_, err = r.RPush("generic-list", "3", "1", "2")
+	a.NilError(err)
+	dump, err := r.Dump("generic-list")
+	a.NilError(err)
+	a.NilError(r.Restore("generic-restored", 0, dump.String()))
+	a.Assert(r.Restore("generic-restored", 0, dump.String()) != nil)
+	a.NilError(r.Restore("generic-restored", time.Minute, dump.String(), RestoreReplace(), RestoreIdleTime(time.Hour)))
+	ttl, err = r.PTTL("generic-restored")
+	a.NilError(err)
+	a.Assert(ttl > 50*time.Second && ttl <= time.Minute)
+	idle, _, err := r.ObjectIdleTime("generic-restored")
+	a.NilError(err)
+	a.Assert(idle >= time.Hour)
+
+	a.NilError(r.Restore("generic-restored", 0, dump.String(), RestoreReplace(), RestoreAbsTTL(time.Now().Add(time.Hour))))
+	ttl, err = r.TTL("generic-restored")
+	a.NilError(err)
+	a.Assert(ttl > 59*time.Minute && ttl <= time.Hour)
+
+	values, err := r.Sort("generic-restored")
+	a.NilError(err)
+	a.Equal(bulkStringsToStrings(values), []string{"1", "2", "3"})
+	values, err = r.Sort("generic-restored", SortDesc(), SortLimit(0, 2))
+	a.NilError(err)
+	a.Equal(bulkStringsToStrings(values), []string{"3", "2"})
+
+	a.NilError(r.MSet(
+		MakeKeyAndValue("generic-weight-1", "30"),
+		MakeKeyAndValue("generic-weight-2", "10"),
+		MakeKeyAndValue("generic-weight-3", "20"),
+		MakeKeyAndValue("generic-name-1", "one"),
+		MakeKeyAndValue("generic-name-3", "three"),
+	))
+	values, err = r.Sort("generic-restored", SortBy("generic-weight-*"), SortGet("#"), SortGet("generic-name-*"))
+	a.NilError(err)
+	a.Equal(len(values), 6)
+	a.Equal(values[0].String(), "2")
+	a.Assert(values[1].IsNull())
+	a.Equal(values[2].String(), "3")
+	a.Equal(values[3].String(), "three")
+	a.Equal(values[4].String(), "1")
+	a.Equal(values[5].String(), "one")
+
+	_, err = r.SAdd("generic-set", "b", "c", "a")
+	a.NilError(err)
+	l, err := r.SortStore("generic-set", "generic-sorted", SortAlpha())
+	a.NilError(err)
+	a.Equal(l, 3)
+	sorted, err := r.LRange("generic-sorted", 0, -1)
+	a.NilError(err)
+	a.Equal(bulkStringsToStrings(sorted), []string{"a", "b", "c"})
+
+	migrated, err := r.Migrate("invalid-addr", []string{"generic-set"}, 0, time.Second)
+	a.Assert(err != nil)
+	a.Assert(!migrated)
+}
+
+func TestMigrate(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	f := factory(t)
+	r := f.New(ctx)
+	resetRedis(t, r)
+
+	target := clusterFactory(t, &Config{
+		Client: &ClientConfig{
+			Addr: testClusterAddr,
+		},
+	}).New(ctx)
+	defer target.Del("{migrate}-1", "{migrate}-2", "{migrate}-3")
+	_, err := target.Del("{migrate}-1", "{migrate}-2", "{migrate}-3")
+	a.NilError(err)
+
+	a.NilError(r.MSet(
+		MakeKeyAndValue("{migrate}-1", "v1"),
+		MakeKeyAndValue("{migrate}-2", "v2"),
+		MakeKeyAndValue("{migrate}-3", "v3"),
+	))
+
+	migrated, err := r.Migrate(testClusterAddr, []string{"{migrate}-1"}, 0, time.Second, MigrateCopy())
+	a.NilError(err)
+	a.Assert(migrated)
+	existing, err := r.Exists("{migrate}-1")
+	a.NilError(err)
+	a.Equal(existing, 1)
+
+	migrated, err = r.Migrate(testClusterAddr, []string{"{migrate}-1", "{migrate}-2", "{migrate}-3"}, 0, time.Second, MigrateReplace())
+	a.NilError(err)
+	a.Assert(migrated)
+	existing, err = r.Exists("{migrate}-1", "{migrate}-2", "{migrate}-3")
+	a.NilError(err)
+	a.Equal(existing, 0)
+	existing, err = target.Exists("{migrate}-1", "{migrate}-2", "{migrate}-3")
+	a.NilError(err)
+	a.Equal(existing, 3)
+
+	migrated, err = r.Migrate(testClusterAddr, []string{"{migrate}-1"}, 0, time.Second)
+	a.NilError(err)
+	a.Assert(!migrated)
+}
+
+func bulkStringsToStrings(bss []BulkString) []string {
+	strs := make([]string, 0, len(bss))
+
+	for _, bs := range bss {
+		strs = append(strs, bs.String())
+	}
+
+	return strs
+}
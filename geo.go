@@ -1,6 +1,143 @@
 package redis
 
+import (
+	"github.com/altstory/go-redis/internal/driver"
+)
+
 // GEO 代表 Redis 跟 GEO 相关的接口，详见 https://redis.io/commands#geo。
 type GEO interface {
-	// TODO:
+	GeoAdd(key string, locations ...GeoLocation) (added int, err error)
+	GeoDist(key string, member1 string, member2 string, unit GeoUnit) (dist float64, exists bool, err error)
+	GeoHash(key string, members ...string) (hashes []string, err error)
+	GeoPos(key string, members ...string) (positions []*GeoPos, err error)
+	GeoRadius(key string, longitude float64, latitude float64, radius float64, unit GeoUnit, options ...GeoOption) (locations GeoLocations, err error)
+	GeoRadiusByMember(key string, member string, radius float64, unit GeoUnit, options ...GeoOption) (locations GeoLocations, err error)
+	GeoSearch(key string, center GeoCenter, shape GeoShape, options ...GeoOption) (locations GeoLocations, err error)
+	GeoSearchStore(dst string, src string, center GeoCenter, shape GeoShape, options ...GeoOption) (stored int, err error)
+}
+
+func (r *redisImpl) GeoAdd(key string, locations ...GeoLocation) (added int, err error) {
+	if len(locations) == 0 {
+		return
+	}
+
+	args := make([]interface{}, 0, 2+3*len(locations))
+	args = append(args, "GEOADD", key)
+
+	for _, l := range locations {
+		args = append(args, l.Longitude, l.Latitude, l.Member)
+	}
+
+	err = r.do("GEOADD", func(client driver.Client) error {
+		added, err = mustBeInt(client, process(client, newCmd(args...)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) GeoDist(key string, member1 string, member2 string, unit GeoUnit) (dist float64, exists bool, err error) {
+	err = r.do("GEODIST", func(client driver.Client) error {
+		dist, exists, err = mustBeFloat64OrNil(client, process(client, newCmdWith(parseFloat, "GEODIST", key, member1, member2, string(unit))))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) GeoHash(key string, members ...string) (hashes []string, err error) {
+	if len(members) == 0 {
+		return
+	}
+
+	err = r.do("GEOHASH", func(client driver.Client) error {
+		hashes, err = mustBeStrings(client, process(client, newCmd(appendArgs([]interface{}{"GEOHASH", key}, members)...)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) GeoPos(key string, members ...string) (positions []*GeoPos, err error) {
+	if len(members) == 0 {
+		return
+	}
+
+	err = r.do("GEOPOS", func(client driver.Client) error {
+		positions, err = mustBeGeoPositions(client, process(client, newCmdWith(parseGeoPositions, appendArgs([]interface{}{"GEOPOS", key}, members)...)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) GeoRadius(key string, longitude float64, latitude float64, radius float64, unit GeoUnit, options ...GeoOption) (locations GeoLocations, err error) {
+	args := make([]interface{}, 0, 16)
+	args = append(args, "GEORADIUS", key, longitude, latitude, radius, string(unit))
+	args, parse := appendGeoOptions(args, options)
+
+	err = r.do("GEORADIUS", func(client driver.Client) error {
+		locations, err = mustBeGeoLocations(client, process(client, newCmdWith(parse, args...)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) GeoRadiusByMember(key string, member string, radius float64, unit GeoUnit, options ...GeoOption) (locations GeoLocations, err error) {
+	args := make([]interface{}, 0, 16)
+	args = append(args, "GEORADIUSBYMEMBER", key, member, radius, string(unit))
+	args, parse := appendGeoOptions(args, options)
+
+	err = r.do("GEORADIUSBYMEMBER", func(client driver.Client) error {
+		locations, err = mustBeGeoLocations(client, process(client, newCmdWith(parse, args...)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) GeoSearch(key string, center GeoCenter, shape GeoShape, options ...GeoOption) (locations GeoLocations, err error) {
+	args := make([]interface{}, 0, 16)
+	args = append(args, "GEOSEARCH", key)
+	args = append(args, center.Args()...)
+	args = append(args, shape.Args()...)
+	args, parse := appendGeoOptions(args, options)
+
+	err = r.do("GEOSEARCH", func(client driver.Client) error {
+		locations, err = mustBeGeoLocations(client, process(client, newCmdWith(parse, args...)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) GeoSearchStore(dst string, src string, center GeoCenter, shape GeoShape, options ...GeoOption) (stored int, err error) {
+	args := make([]interface{}, 0, 16)
+	args = append(args, "GEOSEARCHSTORE", dst, src)
+	args = append(args, center.Args()...)
+	args = append(args, shape.Args()...)
+
+	for _, opt := range options {
+		args = append(args, opt.Args()...)
+	}
+
+	err = r.do("GEOSEARCHSTORE", func(client driver.Client) error {
+		stored, err = mustBeInt(client, process(client, newCmd(args...)))
+		return err
+	})
+	return
+}
+
+// appendGeoOptions 将 options 追加到 args 后面，并根据 WITHCOORD/WITHDIST/WITHHASH 返回解析应答的 replyParser。
+func appendGeoOptions(args []interface{}, options []GeoOption) ([]interface{}, replyParser) {
+	withCoord, withDist, withHash := false, false, false
+
+	for _, opt := range options {
+		switch opt.t {
+		case geoOptionWithCoord:
+			withCoord = true
+		case geoOptionWithDist:
+			withDist = true
+		case geoOptionWithHash:
+			withHash = true
+		}
+
+		args = append(args, opt.Args()...)
+	}
+
+	return args, parseGeoLocationsWith(withCoord, withDist, withHash)
 }
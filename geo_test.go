@@ -0,0 +1,86 @@
+package redis
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+func TestGeoMethods(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	f := factory(t)
+	r := f.New(ctx)
+	resetRedis(t, r)
+
+	key := "geo-key"
+	added, err := r.GeoAdd(key,
+		MakeGeoLocation("Palermo", 13.361389, 38.115556),
+		MakeGeoLocation("Catania", 15.087269, 37.502669),
+	)
+	a.NilError(err)
+	a.Equal(added, 2)
+
+	near := func(v, expected, delta float64) bool {
+		return math.Abs(v-expected) < delta
+	}
+
+	dist, exists, err := r.GeoDist(key, "Palermo", "Catania", GeoUnitKilometers)
+	a.NilError(err)
+	a.Assert(exists)
+	a.Assert(near(dist, 166.2742, 0.001))
+
+	_, exists, err = r.GeoDist(key, "Palermo", "Nowhere", GeoUnitMeters)
+	a.NilError(err)
+	a.Assert(!exists)
+
+	hashes, err := r.GeoHash(key, "Palermo", "Catania")
+	a.NilError(err)
+	a.Equal(hashes, []string{"sqc8b49rny0", "sqdtr74hyu0"})
+
+	positions, err := r.GeoPos(key, "Palermo", "Nowhere")
+	a.NilError(err)
+	a.Equal(len(positions), 2)
+	a.Assert(near(positions[0].Longitude, 13.361389, 0.0001))
+	a.Assert(near(positions[0].Latitude, 38.115556, 0.0001))
+	a.Assert(positions[1] == nil)
+
+	locations, err := r.GeoRadius(key, 15, 37, 200, GeoUnitKilometers, WithDist(), WithCoord(), GeoAsc())
+	a.NilError(err)
+	a.Equal(locations.Members(), []string{"Catania", "Palermo"})
+	a.Assert(near(locations[0].Dist, 56.4413, 0.001))
+	a.Assert(near(locations[0].Longitude, 15.087269, 0.0001))
+
+	locations, err = r.GeoRadiusByMember(key, "Palermo", 200, GeoUnitKilometers, GeoDesc(), GeoCount(1))
+	a.NilError(err)
+	a.Equal(locations.Members(), []string{"Catania"})
+
+	locations, err = r.GeoSearch(key, FromLonLat(15, 37), ByRadius(200, GeoUnitKilometers), WithCoord(), WithDist(), WithHash(), GeoAsc())
+	a.NilError(err)
+	a.Equal(locations.Members(), []string{"Catania", "Palermo"})
+	a.Assert(near(locations[0].Dist, 56.4413, 0.001))
+	a.Equal(locations[0].GeoHash, int64(3479447370796909))
+	a.Assert(near(locations[1].Latitude, 38.115556, 0.0001))
+
+	locations, err = r.GeoSearch(key, FromMember("Palermo"), ByBox(400, 400, GeoUnitKilometers), GeoAsc())
+	a.NilError(err)
+	a.Equal(locations, GeoLocations{{Member: "Palermo"}, {Member: "Catania"}})
+
+	stored, err := r.GeoSearchStore("geo-dst", key, FromLonLat(15, 37), ByRadius(100, GeoUnitKilometers), StoreDist())
+	a.NilError(err)
+	a.Equal(stored, 1)
+
+	var future error
+	_, err = r.Pipeline(func(p Redis) error {
+		_, future = p.GeoPos(key, "Catania")
+		return nil
+	})
+	a.NilError(err)
+	mvs, ok := MakeMultiValue(future).MultiValues()
+	a.Assert(ok)
+	pos, ok := mvs[0].GeoPos()
+	a.Assert(ok)
+	a.Assert(near(pos.Longitude, 15.087269, 0.0001))
+}
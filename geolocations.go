@@ -0,0 +1,127 @@
+package redis
+
+import (
+	"strconv"
+)
+
+// GeoUnit 代表 GEO 命令中使用的距离单位。
+type GeoUnit string
+
+const (
+	// GeoUnitMeters 代表单位是米。
+	GeoUnitMeters GeoUnit = "m"
+
+	// GeoUnitKilometers 代表单位是千米。
+	GeoUnitKilometers GeoUnit = "km"
+
+	// GeoUnitMiles 代表单位是英里。
+	GeoUnitMiles GeoUnit = "mi"
+
+	// GeoUnitFeet 代表单位是英尺。
+	GeoUnitFeet GeoUnit = "ft"
+)
+
+// GeoPos 代表一个经纬度坐标。
+type GeoPos struct {
+	Longitude float64
+	Latitude  float64
+}
+
+// MakeGeoPos 可以方便的创建一个 GeoPos 实例。
+func MakeGeoPos(longitude, latitude float64) GeoPos {
+	return GeoPos{
+		Longitude: longitude,
+		Latitude:  latitude,
+	}
+}
+
+// GeoLocations 代表一系列 GEO 查询结果。
+type GeoLocations []GeoLocation
+
+// Members 返回所有的 member。
+func (gls GeoLocations) Members() []string {
+	if len(gls) == 0 {
+		return nil
+	}
+
+	members := make([]string, 0, len(gls))
+
+	for _, gl := range gls {
+		members = append(members, gl.Member)
+	}
+
+	return members
+}
+
+// GeoLocation 代表一个带有坐标的 member。
+// 作为 GEO 查询结果时，只有设置了对应的选项（WithCoord、WithDist、WithHash），相应的字段才有值。
+type GeoLocation struct {
+	Member  string
+	GeoPos          // GeoPos 是 member 的坐标。
+	Dist    float64 // Dist 是 member 离查询中心点的距离，单位和查询时使用的单位相同。
+	GeoHash int64   // GeoHash 是 member 坐标的 52 位 geohash 值。
+}
+
+// MakeGeoLocation 可以方便的创建一个 GeoLocation 实例，一般用于 GeoAdd。
+func MakeGeoLocation(member string, longitude, latitude float64) GeoLocation {
+	return GeoLocation{
+		Member: member,
+		GeoPos: MakeGeoPos(longitude, latitude),
+	}
+}
+
+// parseGeoLocations 解析 GEOSEARCH 等命令的返回值。
+// 如果设置了 WITHCOORD/WITHDIST/WITHHASH，每个元素都是一个数组，
+// 顺序依次是 member、dist、hash、[longitude, latitude]，否则每个元素都只是 member。
+func parseGeoLocations(mvs []MultiValue, withCoord, withDist, withHash bool) (gls GeoLocations) {
+	gls = make(GeoLocations, 0, len(mvs))
+
+	for _, mv := range mvs {
+		values, ok := mv.MultiValues()
+
+		if !ok {
+			gls = append(gls, GeoLocation{
+				Member: multiValueString(mv),
+			})
+			continue
+		}
+
+		if len(values) == 0 {
+			panic(ErrUnexpectedResponseType)
+		}
+
+		gl := GeoLocation{
+			Member: multiValueString(values[0]),
+		}
+		values = values[1:]
+
+		if withDist && len(values) != 0 {
+			gl.Dist = parseFloat64(values[0])
+			values = values[1:]
+		}
+
+		if withHash && len(values) != 0 {
+			gl.GeoHash, _ = values[0].Int64()
+			values = values[1:]
+		}
+
+		if withCoord && len(values) != 0 {
+			if coord, ok := values[0].MultiValues(); ok && len(coord) == 2 {
+				gl.GeoPos = MakeGeoPos(parseFloat64(coord[0]), parseFloat64(coord[1]))
+			}
+		}
+
+		gls = append(gls, gl)
+	}
+
+	return
+}
+
+func parseFloat64(mv MultiValue) float64 {
+	if f, ok := mv.Float64(); ok {
+		return f
+	}
+
+	f, _ := strconv.ParseFloat(multiValueString(mv), 64)
+	return f
+}
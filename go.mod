@@ -6,8 +6,6 @@ require (
 	github.com/altstory/go-log v1.0.5
 	github.com/altstory/go-metrics v1.0.7
 	github.com/altstory/go-runner v1.1.8
-	github.com/go-redis/redis v6.15.7+incompatible
 	github.com/huandu/go-assert v1.1.5
-	github.com/onsi/ginkgo v1.14.1 // indirect
-	github.com/onsi/gomega v1.10.2 // indirect
+	golang.org/x/sys v0.0.0-20200519105757-fe76b779f299 // indirect
 )
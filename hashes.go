@@ -30,7 +30,7 @@ func (r *redisImpl) HDel(key string, fields ...string) (deleted int, err error)
 	}
 
 	err = r.do("HDEL", func(client driver.Client) error {
-		deleted, err = mustBeInt(client, client.HDel(key, fields...))
+		deleted, err = mustBeInt(client, process(client, newCmd(appendArgs([]interface{}{"HDEL", key}, fields)...)))
 		return err
 	})
 	return
@@ -38,7 +38,7 @@ func (r *redisImpl) HDel(key string, fields ...string) (deleted int, err error)
 
 func (r *redisImpl) HExists(key string, field string) (exists bool, err error) {
 	err = r.do("HEXISTS", func(client driver.Client) error {
-		exists, err = mustBeBool(client, client.HExists(key, field))
+		exists, err = mustBeBool(client, process(client, newCmdWith(parseBool, "HEXISTS", key, field)))
 		return err
 	})
 	return
@@ -46,7 +46,7 @@ func (r *redisImpl) HExists(key string, field string) (exists bool, err error) {
 
 func (r *redisImpl) HGet(key string, field string) (value BulkString, err error) {
 	err = r.do("HGET", func(client driver.Client) error {
-		value, err = mustBeBulkString(client, client.HGet(key, field))
+		value, err = mustBeBulkString(client, r.processCached(client, key, cacheID("HGET", key, field), newCmd("HGET", key, field)))
 		return err
 	})
 	return
@@ -54,7 +54,7 @@ func (r *redisImpl) HGet(key string, field string) (value BulkString, err error)
 
 func (r *redisImpl) HGetAll(key string) (fieldAndValues KeyAndValues, err error) {
 	err = r.do("HGETALL", func(client driver.Client) error {
-		fieldAndValues, err = mustBeKeyAndValues(client, client.HGetAll(key))
+		fieldAndValues, err = mustBeKeyAndValues(client, r.processCached(client, key, cacheID("HGETALL", key), newCmdWith(parseKeyAndValues, "HGETALL", key)))
 		return err
 	})
 	return
@@ -62,7 +62,7 @@ func (r *redisImpl) HGetAll(key string) (fieldAndValues KeyAndValues, err error)
 
 func (r *redisImpl) HIncrBy(key string, field string, incr int64) (value int64, err error) {
 	err = r.do("HINCRBY", func(client driver.Client) error {
-		value, err = mustBeInt64(client, client.HIncrBy(key, field, incr))
+		value, err = mustBeInt64(client, process(client, newCmd("HINCRBY", key, field, incr)))
 		return err
 	})
 	return
@@ -70,7 +70,7 @@ func (r *redisImpl) HIncrBy(key string, field string, incr int64) (value int64,
 
 func (r *redisImpl) HIncrByFloat(key string, field string, incr float64) (value float64, err error) {
 	err = r.do("HINCRBYFLOAT", func(client driver.Client) error {
-		value, err = mustBeFloat64(client, client.HIncrByFloat(key, field, incr))
+		value, err = mustBeFloat64(client, process(client, newCmdWith(parseFloat, "HINCRBYFLOAT", key, field, incr)))
 		return err
 	})
 	return
@@ -78,7 +78,7 @@ func (r *redisImpl) HIncrByFloat(key string, field string, incr float64) (value
 
 func (r *redisImpl) HKeys(key string) (keys []BulkString, err error) {
 	err = r.do("HKEYS", func(client driver.Client) error {
-		keys, err = mustBeBulkStrings(client, client.HKeys(key))
+		keys, err = mustBeBulkStrings(client, process(client, newCmd("HKEYS", key)))
 		return err
 	})
 	return
@@ -86,7 +86,7 @@ func (r *redisImpl) HKeys(key string) (keys []BulkString, err error) {
 
 func (r *redisImpl) HLen(key string) (l int, err error) {
 	err = r.do("HLEN", func(client driver.Client) error {
-		l, err = mustBeInt(client, client.HLen(key))
+		l, err = mustBeInt(client, process(client, newCmd("HLEN", key)))
 		return err
 	})
 	return
@@ -98,7 +98,7 @@ func (r *redisImpl) HMGet(key string, fields ...string) (values []BulkString, er
 	}
 
 	err = r.do("HMGET", func(client driver.Client) error {
-		values, err = mustBeBulkStrings(client, client.HMGet(key, fields...))
+		values, err = mustBeBulkStrings(client, process(client, newCmd(appendArgs([]interface{}{"HMGET", key}, fields)...)))
 		return err
 	})
 	return
@@ -109,14 +109,15 @@ func (r *redisImpl) HMSet(key string, fieldAndValues ...KeyAndValue) (err error)
 		return
 	}
 
-	fields := make(map[string]interface{}, len(fieldAndValues))
+	args := make([]interface{}, 0, 2+2*len(fieldAndValues))
+	args = append(args, "HMSET", key)
 
 	for _, fv := range fieldAndValues {
-		fields[fv.Key] = fv.Value
+		args = append(args, fv.Key, fv.Value)
 	}
 
 	err = r.do("HMSET", func(client driver.Client) error {
-		_, err = mustBeStatus(client, client.HMSet(key, fields))
+		_, err = mustBeStatus(client, process(client, newCmd(args...)))
 		return err
 	})
 	return
@@ -124,7 +125,7 @@ func (r *redisImpl) HMSet(key string, fieldAndValues ...KeyAndValue) (err error)
 
 func (r *redisImpl) HSet(key string, field string, value string) (isNew bool, err error) {
 	err = r.do("HSET", func(client driver.Client) error {
-		isNew, err = mustBeBool(client, client.HSet(key, field, value))
+		isNew, err = mustBeBool(client, process(client, newCmdWith(parseBool, "HSET", key, field, value)))
 		return err
 	})
 	return
@@ -132,7 +133,7 @@ func (r *redisImpl) HSet(key string, field string, value string) (isNew bool, er
 
 func (r *redisImpl) HSetNX(key string, field string, value string) (isNew bool, err error) {
 	err = r.do("HSETNX", func(client driver.Client) error {
-		isNew, err = mustBeBool(client, client.HSetNX(key, field, value))
+		isNew, err = mustBeBool(client, process(client, newCmdWith(parseBool, "HSETNX", key, field, value)))
 		return err
 	})
 	return
@@ -140,7 +141,7 @@ func (r *redisImpl) HSetNX(key string, field string, value string) (isNew bool,
 
 // func (r *redisImpl) HStrLen(key string, field string) (l int, err error) {
 // 	err = r.do("HSTRLEN", func(client driver.Client) error {
-// 		l, err = mustBeInt(client, client.HStrLen(key))
+// 		l, err = mustBeInt(client, process(client, newCmd("HSTRLEN", key, field)))
 // 		return err
 // 	})
 // 	return
@@ -148,7 +149,7 @@ func (r *redisImpl) HSetNX(key string, field string, value string) (isNew bool,
 
 func (r *redisImpl) HVals(key string) (values []BulkString, err error) {
 	err = r.do("HVALS", func(client driver.Client) error {
-		values, err = mustBeBulkStrings(client, client.HVals(key))
+		values, err = mustBeBulkStrings(client, process(client, newCmd("HVALS", key)))
 		return err
 	})
 	return
@@ -1,6 +1,40 @@
 package redis
 
+import (
+	"github.com/altstory/go-redis/internal/driver"
+)
+
 // HyperLogLog 代表 Redis 跟 HyperLogLog 相关的接口，详见 https://redis.io/commands#hyperloglog。
 type HyperLogLog interface {
-	// TODO:
+	PFAdd(key string, elements ...string) (changed bool, err error)
+	PFCount(keys ...string) (count int64, err error)
+	PFMerge(dst string, keys ...string) (err error)
+}
+
+func (r *redisImpl) PFAdd(key string, elements ...string) (changed bool, err error) {
+	err = r.do("PFADD", func(client driver.Client) error {
+		changed, err = mustBeBool(client, process(client, newCmdWith(parseBool, appendArgs([]interface{}{"PFADD", key}, elements)...)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) PFCount(keys ...string) (count int64, err error) {
+	if len(keys) == 0 {
+		return
+	}
+
+	err = r.do("PFCOUNT", func(client driver.Client) error {
+		count, err = mustBeInt64(client, process(client, newCmd(appendArgs([]interface{}{"PFCOUNT"}, keys)...)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) PFMerge(dst string, keys ...string) (err error) {
+	err = r.do("PFMERGE", func(client driver.Client) error {
+		_, err = mustBeStatus(client, process(client, newCmd(appendArgs([]interface{}{"PFMERGE", dst}, keys)...)))
+		return err
+	})
+	return
 }
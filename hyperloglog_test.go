@@ -0,0 +1,48 @@
+package redis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+func TestHyperLogLogMethods(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	f := factory(t)
+	r := f.New(ctx)
+	resetRedis(t, r)
+
+	changed, err := r.PFAdd("hll-1", "a", "b", "c")
+	a.NilError(err)
+	a.Assert(changed)
+
+	changed, err = r.PFAdd("hll-1", "a")
+	a.NilError(err)
+	a.Assert(!changed)
+
+	_, err = r.PFAdd("hll-2", "c", "d")
+	a.NilError(err)
+
+	count, err := r.PFCount("hll-1")
+	a.NilError(err)
+	a.Equal(count, int64(3))
+
+	count, err = r.PFCount("hll-1", "hll-2")
+	a.NilError(err)
+	a.Equal(count, int64(4))
+
+	a.NilError(r.PFMerge("hll-merged", "hll-1", "hll-2"))
+
+	var futureCount error
+	mvs, err := r.Multi(func(tx Redis) error {
+		_, futureCount = tx.PFCount("hll-merged")
+		return nil
+	})
+	a.NilError(err)
+	a.Equal(len(mvs), 1)
+	count, ok := MakeMultiValue(futureCount).Int64()
+	a.Assert(ok)
+	a.Equal(count, int64(4))
+}
@@ -1,17 +1,306 @@
 package driver
 
 import (
-	"github.com/go-redis/redis"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"time"
 )
 
-// Client 代表一个 Redis 客户端连接池。
-// TODO: 暂时由于时间关系，直接暴露了 github.com/go-redis/redis 的接口，未来会改掉。
-type Client interface {
-	redis.Cmdable
-	Close() error
+var (
+	// ErrTxFailed 代表由于 WATCH 的 key 被修改，EXEC 没有执行任何命令。
+	ErrTxFailed = errors.New("go-redis/driver: transaction failed")
+)
+
+// SingleClient 代表连接单个 Redis 服务器的客户端，它是并发安全的。
+type SingleClient struct {
+	opt     *Options
+	pool    *Pool
+	onClose func() error
+}
+
+var _ Client = new(SingleClient)
+
+// NewSingleClient 创建一个连接单个 Redis 服务器的客户端。
+func NewSingleClient(opt *Options) *SingleClient {
+	opt = opt.clone()
+	return &SingleClient{
+		opt:  opt,
+		pool: NewPool(opt),
+	}
+}
+
+// Options 返回客户端的配置。
+func (c *SingleClient) Options() *Options {
+	return c.opt
+}
+
+// Pool 返回客户端的连接池。
+func (c *SingleClient) Pool() *Pool {
+	return c.pool
+}
+
+// Process 执行一个命令。
+func (c *SingleClient) Process(cmd Cmder) error {
+	return c.withRetry([]Cmder{cmd}, func(cn *Conn) error {
+		return cn.Process(cmd)
+	})
+}
+
+// ProcessPipeline 通过 pipeline 批量执行命令。
+func (c *SingleClient) ProcessPipeline(cmds []Cmder) error {
+	if len(cmds) == 0 {
+		return nil
+	}
+
+	return c.withRetry(cmds, func(cn *Conn) error {
+		return cn.processPipeline(cmds)
+	})
+}
+
+// ProcessTxPipeline 在 MULTI/EXEC 事务中批量执行命令。
+func (c *SingleClient) ProcessTxPipeline(cmds []Cmder) error {
+	if len(cmds) == 0 {
+		return nil
+	}
+
+	return c.withRetry(cmds, func(cn *Conn) error {
+		return cn.processTxPipeline(cmds)
+	})
+}
+
+// Watch 在同一个连接上 WATCH 所有 keys 并调用 fn，fn 中的 tx 上执行的命令都使用这个连接。
+// 在 fn 返回之后会通过 UNWATCH 取消所有监视。
+func (c *SingleClient) Watch(fn func(tx Client) error, keys ...string) error {
+	cn, err := c.pool.Get()
+
+	if err != nil {
+		return err
+	}
+
+	defer c.pool.Put(cn)
+
+	if len(keys) != 0 {
+		args := make([]interface{}, 0, len(keys)+1)
+		args = append(args, "WATCH")
+
+		for _, key := range keys {
+			args = append(args, key)
+		}
+
+		if err := cn.Process(NewCmd(args...)); err != nil {
+			return err
+		}
+	}
+
+	err = fn(&connClient{cn: cn})
+
+	if !cn.isBroken() {
+		cn.Process(NewCmd("UNWATCH"))
+	}
+
+	return err
+}
+
+// PubSub 创建一个新的订阅，订阅使用独立于连接池的连接。
+func (c *SingleClient) PubSub() *PubSub {
+	return newPubSub(c.pool.NewConn)
+}
+
+// Close 关闭客户端和所有连接。
+func (c *SingleClient) Close() error {
+	err := c.pool.Close()
+
+	if c.onClose != nil {
+		if e := c.onClose(); e != nil && err == nil {
+			err = e
+		}
+	}
+
+	return err
+}
+
+// withRetry 从连接池拿到一个连接并调用 fn，遇到可以重试的错误时会在 MaxRetries 次内重试。
+func (c *SingleClient) withRetry(cmds []Cmder, fn func(cn *Conn) error) (err error) {
+	for attempt := 0; attempt <= c.opt.MaxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(retryBackoff(attempt))
+
+			for _, cmd := range cmds {
+				cmd.SetErr(nil)
+				cmd.SetReply(nil)
+			}
+		}
+
+		var cn *Conn
+		cn, err = c.pool.Get()
+
+		if err != nil {
+			setCmdsErr(cmds, err)
+			return
+		}
+
+		err = fn(cn)
+		c.pool.Put(cn)
+
+		if err == nil || !shouldRetry(err) {
+			return
+		}
+
+		// pipeline 中的其他命令可能已经执行成功，不能因为某个命令的错误应答而整体重试。
+		if _, ok := err.(Error); ok && len(cmds) > 1 {
+			return
+		}
+	}
+
+	return
+}
+
+// connClient 是绑定在一个连接上的 Client，用于 Watch，它不是并发安全的。
+type connClient struct {
+	cn *Conn
+}
+
+func (c *connClient) Process(cmd Cmder) error {
+	return c.cn.Process(cmd)
+}
+
+func (c *connClient) ProcessPipeline(cmds []Cmder) error {
+	if len(cmds) == 0 {
+		return nil
+	}
+
+	return c.cn.processPipeline(cmds)
+}
+
+func (c *connClient) ProcessTxPipeline(cmds []Cmder) error {
+	if len(cmds) == 0 {
+		return nil
+	}
+
+	return c.cn.processTxPipeline(cmds)
+}
+
+func (c *connClient) Close() error {
+	return nil
+}
+
+// processTxPipeline 在这个连接上用 MULTI/EXEC 批量执行命令。
+func (cn *Conn) processTxPipeline(cmds []Cmder) error {
+	multi := NewCmd("MULTI")
+	exec := NewCmd("EXEC")
+	all := make([]Cmder, 0, len(cmds)+2)
+	all = append(all, multi)
+	all = append(all, cmds...)
+	all = append(all, exec)
+
+	if err := cn.writeCmds(all...); err != nil {
+		setCmdsErr(cmds, err)
+		return err
+	}
+
+	if err := cn.readReply(multi); err != nil {
+		if cn.isBroken() {
+			setCmdsErr(cmds, err)
+			return err
+		}
+	}
+
+	// 每个命令都应该返回 QUEUED，如果命令有语法错误，EXEC 会返回 EXECABORT。
+	for _, cmd := range cmds {
+		reply, err := cn.read(nil)
+
+		if err != nil {
+			if cn.isBroken() {
+				setCmdsErr(cmds, err)
+				return err
+			}
+
+			cmd.SetErr(err)
+			continue
+		}
+
+		if status, ok := reply.(Status); !ok || status != "QUEUED" {
+			cn.setBroken()
+			setCmdsErr(cmds, ErrProtocol)
+			return ErrProtocol
+		}
+	}
+
+	reply, err := cn.read(exec)
+
+	if err != nil {
+		setCmdsErr(cmds, err)
+		return err
+	}
+
+	if reply == nil {
+		setCmdsErr(cmds, ErrTxFailed)
+		return ErrTxFailed
+	}
+
+	replies, ok := reply.([]interface{})
+
+	if !ok || len(replies) != len(cmds) {
+		cn.setBroken()
+		setCmdsErr(cmds, ErrProtocol)
+		return ErrProtocol
+	}
+
+	for i, cmd := range cmds {
+		if e, ok := replies[i].(Error); ok {
+			cmd.SetErr(e)
+			continue
+		}
+
+		cmd.SetReply(replies[i])
+	}
+
+	return cmdsFirstErr(cmds)
+}
+
+// shouldRetry 判断遇到 err 之后是否可以重试。
+func shouldRetry(err error) bool {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return true
+	}
+
+	if e, ok := err.(Error); ok {
+		s := string(e)
+		return strings.HasPrefix(s, "LOADING ") ||
+			strings.HasPrefix(s, "READONLY ") ||
+			strings.HasPrefix(s, "CLUSTERDOWN ") ||
+			strings.HasPrefix(s, "TRYAGAIN ") ||
+			strings.HasPrefix(s, "ERR max number of clients reached")
+	}
+
+	// 超时说明服务器可能已经执行了命令，重试可能导致命令被执行多次。
+	if e, ok := err.(net.Error); ok && e.Timeout() {
+		return false
+	}
+
+	_, ok := err.(*net.OpError)
+	return ok
+}
+
+// isDialError 判断 err 是否是建立连接时遇到的错误，这时命令一定还没有发送给服务器。
+func isDialError(err error) bool {
+	e, ok := err.(*net.OpError)
+	return ok && e.Op == "dial"
+}
+
+const (
+	minRetryBackoff = 8 * time.Millisecond
+	maxRetryBackoff = 512 * time.Millisecond
+)
+
+func retryBackoff(attempt int) time.Duration {
+	backoff := minRetryBackoff << uint(attempt-1)
 
-	// 临时对外暴露这个接口，为了方便自定义命令。
-	Process(cmder redis.Cmder) error
+	if backoff > maxRetryBackoff || backoff <= 0 {
+		backoff = maxRetryBackoff
+	}
 
-	// TODO: 还需要定义各种维护用的接口。
+	return backoff
 }
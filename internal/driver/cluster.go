@@ -0,0 +1,634 @@
+package driver
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// HashSlots 是 Redis cluster 中 slot 的数量。
+	HashSlots = 16384
+
+	defaultMaxRedirects = 8
+
+	// clusterReloadInterval 是两次自动重新加载集群拓扑之间的最小间隔。
+	clusterReloadInterval = 100 * time.Millisecond
+)
+
+var (
+	// ErrNoClusterNodes 代表当前没有任何可用的集群结点。
+	ErrNoClusterNodes = errors.New("go-redis/driver: no cluster nodes available")
+
+	// ErrCrossSlot 代表 Watch 的 keys 不在同一个 slot 上。
+	ErrCrossSlot = errors.New("go-redis/driver: keys must be in the same slot")
+)
+
+// ClusterOptions 代表连接 Redis cluster 的配置。
+type ClusterOptions struct {
+	Addrs    []string // Addrs 是用来发现集群拓扑的种子结点地址。
+	Password string   // Password 是连接每个结点的密码。
+
+	// MaxRedirects 是遇到 MOVED、ASK 等错误时最多重定向的次数，默认是 8。
+	MaxRedirects int
+
+	// OnConnect 在每个结点的新连接建立之后调用。
+	OnConnect func(cn *Conn) error
+
+	// Protocol 是使用的 RESP 协议版本，默认是 3，服务器不支持时自动退回 RESP2。
+	Protocol int
+
+	// OnPush 在每个结点的普通连接上收到 RESP3 push 消息时调用。
+	OnPush func(cn *Conn, push Push)
+
+	// OnInvalidate 不为 nil 时为每个结点的连接开启 CLIENT TRACKING，详见 Options.OnInvalidate。
+	OnInvalidate func(keys []string)
+
+	DialTimeout  time.Duration // DialTimeout 是连接超时。
+	ReadTimeout  time.Duration // ReadTimeout 是读超时。
+	WriteTimeout time.Duration // WriteTimeout 是写超时。
+
+	PoolSize           int           // PoolSize 是每个结点的连接池最大连接数。
+	PoolTimeout        time.Duration // PoolTimeout 是连接池满时等待空闲连接的时间。
+	IdleTimeout        time.Duration // IdleTimeout 是空闲连接被关闭前的最长空闲时间。
+	IdleCheckFrequency time.Duration // IdleCheckFrequency 是检查空闲连接的周期。
+}
+
+func (opt *ClusterOptions) init() {
+	if opt.MaxRedirects == 0 {
+		opt.MaxRedirects = defaultMaxRedirects
+	} else if opt.MaxRedirects < 0 {
+		opt.MaxRedirects = 0
+	}
+}
+
+func (opt *ClusterOptions) nodeOptions(addr string) *Options {
+	return &Options{
+		Addr:      addr,
+		Password:  opt.Password,
+		OnConnect: opt.OnConnect,
+		Protocol:  opt.Protocol,
+		OnPush:    opt.OnPush,
+
+		OnInvalidate: opt.OnInvalidate,
+
+		DialTimeout:  opt.DialTimeout,
+		ReadTimeout:  opt.ReadTimeout,
+		WriteTimeout: opt.WriteTimeout,
+
+		PoolSize:           opt.PoolSize,
+		PoolTimeout:        opt.PoolTimeout,
+		IdleTimeout:        opt.IdleTimeout,
+		IdleCheckFrequency: opt.IdleCheckFrequency,
+	}
+}
+
+// clusterState 代表某一时刻的集群拓扑。
+type clusterState struct {
+	masters []string
+	slots   [HashSlots]string
+}
+
+// ClusterClient 代表连接 Redis cluster 的客户端，它是并发安全的。
+//
+// ClusterClient 根据 key 所在的 slot 将命令发送到对应的 master 结点，
+// 遇到 MOVED 和 ASK 时自动重定向，并在后台重新加载集群拓扑。
+type ClusterClient struct {
+	opt *ClusterOptions
+
+	mu     sync.Mutex
+	nodes  map[string]*SingleClient
+	closed bool
+
+	state     atomic.Value // state 保存 *clusterState。
+	reloading int32
+}
+
+var _ Client = new(ClusterClient)
+
+// NewClusterClient 创建一个连接 Redis cluster 的客户端。
+// 集群拓扑会在第一次执行命令时加载。
+func NewClusterClient(opt *ClusterOptions) *ClusterClient {
+	cloned := *opt
+	cloned.init()
+
+	return &ClusterClient{
+		opt:   &cloned,
+		nodes: map[string]*SingleClient{},
+	}
+}
+
+// Process 将命令发送到 key 所在的 master 结点执行。
+func (c *ClusterClient) Process(cmd Cmder) error {
+	var node *SingleClient
+	var err error
+	asking := false
+
+	for attempt := 0; attempt <= c.opt.MaxRedirects; attempt++ {
+		if attempt > 0 {
+			cmd.SetErr(nil)
+			cmd.SetReply(nil)
+		}
+
+		if node == nil {
+			node, err = c.cmdNode(cmd)
+
+			if err != nil {
+				cmd.SetErr(err)
+				return err
+			}
+		}
+
+		if asking {
+			node.ProcessPipeline([]Cmder{NewCmd("ASKING"), cmd})
+			err = cmd.Err()
+			asking = false
+		} else {
+			err = node.Process(cmd)
+		}
+
+		if err == nil {
+			return nil
+		}
+
+		if moved, ask, addr := parseRedirect(err); moved || ask {
+			if moved {
+				c.lazyReload()
+			}
+
+			asking = ask
+
+			if node, err = c.node(addr); err != nil {
+				cmd.SetErr(err)
+				return err
+			}
+
+			continue
+		}
+
+		if isClusterRetryable(err) {
+			time.Sleep(retryBackoff(attempt + 1))
+			continue
+		}
+
+		if isDialError(err) {
+			// 结点可能已经下线，重新加载拓扑之后再试，连接失败说明命令还没有发送给服务器，可以安全重试。
+			c.lazyReload()
+			node = nil
+			time.Sleep(retryBackoff(attempt + 1))
+			continue
+		}
+
+		// 命令已经发送之后出现网络错误，服务器可能已经执行了命令，
+		// 重试可能导致 INCR 等命令被执行多次，只重新加载拓扑，不重试。
+		if _, ok := err.(Error); !ok && shouldRetry(err) {
+			c.lazyReload()
+		}
+
+		return err
+	}
+
+	return err
+}
+
+// ProcessPipeline 将命令按照结点分组，并发地在每个结点上通过 pipeline 执行。
+func (c *ClusterClient) ProcessPipeline(cmds []Cmder) error {
+	if len(cmds) == 0 {
+		return nil
+	}
+
+	groups, err := c.groupCmds(cmds)
+
+	if err != nil {
+		setCmdsErr(cmds, err)
+		return err
+	}
+
+	var wg sync.WaitGroup
+
+	for node, group := range groups {
+		wg.Add(1)
+		go func(node *SingleClient, group []Cmder) {
+			defer wg.Done()
+			node.ProcessPipeline(group)
+		}(node, group)
+	}
+
+	wg.Wait()
+
+	// 被重定向的命令单独重新执行。
+	for _, cmd := range cmds {
+		if moved, ask, _ := parseRedirect(cmd.Err()); moved || ask {
+			cmd.SetErr(nil)
+			c.Process(cmd)
+		}
+	}
+
+	return cmdsFirstErr(cmds)
+}
+
+// ProcessTxPipeline 将命令按照结点分组，在每个结点上用 MULTI/EXEC 执行。
+// 事务中的所有 key 应该在同一个 slot 上，否则事务只在每个结点内有原子性。
+func (c *ClusterClient) ProcessTxPipeline(cmds []Cmder) error {
+	if len(cmds) == 0 {
+		return nil
+	}
+
+	pending := cmds
+
+	for attempt := 0; len(pending) != 0; attempt++ {
+		groups, err := c.groupCmds(pending)
+
+		if err != nil {
+			setCmdsErr(pending, err)
+			break
+		}
+
+		pending = nil
+
+		for node, group := range groups {
+			node.ProcessTxPipeline(group)
+
+			if attempt >= c.opt.MaxRedirects || !hasRedirect(group) {
+				continue
+			}
+
+			for _, cmd := range group {
+				cmd.SetErr(nil)
+				cmd.SetReply(nil)
+			}
+
+			pending = append(pending, group...)
+		}
+
+		if len(pending) != 0 {
+			c.reload()
+		}
+	}
+
+	return cmdsFirstErr(cmds)
+}
+
+// Watch 在 keys 所在的结点上执行 WATCH 并调用 fn，所有 keys 必须在同一个 slot 上。
+func (c *ClusterClient) Watch(fn func(tx Client) error, keys ...string) error {
+	slot := -1
+
+	for _, key := range keys {
+		s := Slot(key)
+
+		if slot >= 0 && s != slot {
+			return ErrCrossSlot
+		}
+
+		slot = s
+	}
+
+	node, err := c.slotNode(slot)
+
+	if err != nil {
+		return err
+	}
+
+	return node.Watch(fn, keys...)
+}
+
+// PubSub 创建一个新的订阅，订阅连接会建立在任意一个 master 结点上。
+func (c *ClusterClient) PubSub() *PubSub {
+	return newPubSub(func() (*Conn, error) {
+		node, err := c.slotNode(-1)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return node.pool.NewConn()
+	})
+}
+
+// ForEachMaster 依次对每个 master 结点调用 fn。
+func (c *ClusterClient) ForEachMaster(fn func(client Client) error) error {
+	state, err := c.loadedState()
+
+	if err != nil {
+		return err
+	}
+
+	for _, addr := range state.masters {
+		node, err := c.node(addr)
+
+		if err != nil {
+			return err
+		}
+
+		if err := fn(node); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Close 关闭所有结点的连接。
+func (c *ClusterClient) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return ErrClosed
+	}
+
+	c.closed = true
+	var firstErr error
+
+	for addr, node := range c.nodes {
+		if err := node.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+
+		delete(c.nodes, addr)
+	}
+
+	return firstErr
+}
+
+// node 返回 addr 对应的结点客户端，如果不存在则新建一个。
+func (c *ClusterClient) node(addr string) (*SingleClient, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil, ErrClosed
+	}
+
+	if node, ok := c.nodes[addr]; ok {
+		return node, nil
+	}
+
+	node := NewSingleClient(c.opt.nodeOptions(addr))
+	c.nodes[addr] = node
+	return node, nil
+}
+
+func (c *ClusterClient) cmdNode(cmd Cmder) (*SingleClient, error) {
+	return c.slotNode(cmdSlot(cmd))
+}
+
+// slotNode 返回负责 slot 的 master 结点，slot 为 -1 时随机返回一个 master 结点。
+func (c *ClusterClient) slotNode(slot int) (*SingleClient, error) {
+	state, err := c.loadedState()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var addr string
+
+	if slot >= 0 && slot < HashSlots {
+		addr = state.slots[slot]
+	}
+
+	if addr == "" {
+		if len(state.masters) == 0 {
+			return nil, ErrNoClusterNodes
+		}
+
+		addr = state.masters[rand.Intn(len(state.masters))]
+	}
+
+	return c.node(addr)
+}
+
+func (c *ClusterClient) groupCmds(cmds []Cmder) (map[*SingleClient][]Cmder, error) {
+	groups := map[*SingleClient][]Cmder{}
+
+	for _, cmd := range cmds {
+		node, err := c.cmdNode(cmd)
+
+		if err != nil {
+			return nil, err
+		}
+
+		groups[node] = append(groups[node], cmd)
+	}
+
+	return groups, nil
+}
+
+func (c *ClusterClient) loadedState() (*clusterState, error) {
+	if state, ok := c.state.Load().(*clusterState); ok {
+		return state, nil
+	}
+
+	return c.reload()
+}
+
+// lazyReload 在后台重新加载集群拓扑，同一时间只会有一个重新加载的任务。
+func (c *ClusterClient) lazyReload() {
+	if !atomic.CompareAndSwapInt32(&c.reloading, 0, 1) {
+		return
+	}
+
+	go func() {
+		defer atomic.StoreInt32(&c.reloading, 0)
+		c.reload()
+		time.Sleep(clusterReloadInterval)
+	}()
+}
+
+// reload 依次尝试已知结点和种子结点，通过 CLUSTER SLOTS 加载集群拓扑。
+func (c *ClusterClient) reload() (*clusterState, error) {
+	addrs := c.knownAddrs()
+	var lastErr error
+
+	for _, addr := range addrs {
+		node, err := c.node(addr)
+
+		if err != nil {
+			return nil, err
+		}
+
+		cmd := NewCmd("CLUSTER", "SLOTS")
+
+		if err := node.Process(cmd); err != nil {
+			lastErr = err
+			continue
+		}
+
+		state, err := parseClusterSlots(cmd.Reply(), addr)
+
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		c.state.Store(state)
+		c.removeStaleNodes(state)
+		return state, nil
+	}
+
+	if lastErr == nil {
+		lastErr = ErrNoClusterNodes
+	}
+
+	return nil, lastErr
+}
+
+func (c *ClusterClient) knownAddrs() []string {
+	seen := map[string]bool{}
+	var addrs []string
+
+	if state, ok := c.state.Load().(*clusterState); ok {
+		for _, addr := range state.masters {
+			if !seen[addr] {
+				seen[addr] = true
+				addrs = append(addrs, addr)
+			}
+		}
+	}
+
+	for _, addr := range c.opt.Addrs {
+		if !seen[addr] {
+			seen[addr] = true
+			addrs = append(addrs, addr)
+		}
+	}
+
+	return addrs
+}
+
+// removeStaleNodes 关闭已经不在集群中的结点，种子结点会被保留。
+func (c *ClusterClient) removeStaleNodes(state *clusterState) {
+	active := map[string]bool{}
+
+	for _, addr := range state.masters {
+		active[addr] = true
+	}
+
+	for _, addr := range c.opt.Addrs {
+		active[addr] = true
+	}
+
+	c.mu.Lock()
+	var stale []*SingleClient
+
+	for addr, node := range c.nodes {
+		if !active[addr] {
+			stale = append(stale, node)
+			delete(c.nodes, addr)
+		}
+	}
+
+	c.mu.Unlock()
+
+	for _, node := range stale {
+		node.Close()
+	}
+}
+
+// parseClusterSlots 解析 CLUSTER SLOTS 的返回值，格式是：
+//     [[start, end, [host, port, id, ...], [replica host, port, id, ...], ...], ...]
+// 如果 host 为空，说明结点和 origin 在同一台机器上。
+func parseClusterSlots(reply interface{}, origin string) (*clusterState, error) {
+	ranges, ok := reply.([]interface{})
+
+	if !ok {
+		return nil, fmt.Errorf("%v: invalid CLUSTER SLOTS reply", ErrProtocol)
+	}
+
+	originHost, _, _ := net.SplitHostPort(origin)
+	state := &clusterState{}
+	seen := map[string]bool{}
+
+	for _, r := range ranges {
+		fields, ok := r.([]interface{})
+
+		if !ok || len(fields) < 3 {
+			return nil, fmt.Errorf("%v: invalid CLUSTER SLOTS range", ErrProtocol)
+		}
+
+		start, ok1 := fields[0].(int64)
+		end, ok2 := fields[1].(int64)
+		master, ok3 := fields[2].([]interface{})
+
+		if !ok1 || !ok2 || !ok3 || len(master) < 2 {
+			return nil, fmt.Errorf("%v: invalid CLUSTER SLOTS range", ErrProtocol)
+		}
+
+		host := replyString(master[0])
+		port, _ := master[1].(int64)
+
+		if host == "" || host == "?" {
+			host = originHost
+		}
+
+		addr := net.JoinHostPort(host, strconv.FormatInt(port, 10))
+
+		if !seen[addr] {
+			seen[addr] = true
+			state.masters = append(state.masters, addr)
+		}
+
+		for slot := start; slot <= end && slot < HashSlots; slot++ {
+			state.slots[slot] = addr
+		}
+	}
+
+	return state, nil
+}
+
+// parseRedirect 判断 err 是否是 MOVED 或 ASK 错误，并返回重定向的地址。
+func parseRedirect(err error) (moved bool, ask bool, addr string) {
+	e, ok := err.(Error)
+
+	if !ok {
+		return
+	}
+
+	s := string(e)
+
+	switch {
+	case strings.HasPrefix(s, "MOVED "):
+		moved = true
+	case strings.HasPrefix(s, "ASK "):
+		ask = true
+	default:
+		return
+	}
+
+	// 格式是 `MOVED slot host:port`。
+	fields := strings.Fields(s)
+
+	if len(fields) != 3 {
+		return false, false, ""
+	}
+
+	addr = fields[2]
+	return
+}
+
+func hasRedirect(cmds []Cmder) bool {
+	for _, cmd := range cmds {
+		if moved, ask, _ := parseRedirect(cmd.Err()); moved || ask {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isClusterRetryable(err error) bool {
+	e, ok := err.(Error)
+
+	if !ok {
+		return false
+	}
+
+	s := string(e)
+	return strings.HasPrefix(s, "TRYAGAIN ") || strings.HasPrefix(s, "CLUSTERDOWN ") || strings.HasPrefix(s, "LOADING ")
+}
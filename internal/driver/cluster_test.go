@@ -0,0 +1,96 @@
+package driver
+
+import (
+	"bufio"
+	"net"
+	"sync/atomic"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+func TestClusterCmdNode(t *testing.T) {
+	a := assert.New(t)
+	c := NewClusterClient(&ClusterOptions{
+		Addrs: []string{"127.0.0.1:7000"},
+	})
+	defer c.Close()
+
+	// 两个 master 各负责一半的 slot。
+	state := &clusterState{
+		masters: []string{"127.0.0.1:7000", "127.0.0.1:7001"},
+	}
+
+	for slot := 0; slot < HashSlots; slot++ {
+		state.slots[slot] = state.masters[slot*2/HashSlots]
+	}
+
+	c.state.Store(state)
+
+	// CLUSTER GETKEYSINSLOT 和 CLUSTER COUNTKEYSINSLOT 必须发送到负责 slot 的结点。
+	cases := []struct {
+		args []interface{}
+		addr string
+	}{
+		{[]interface{}{"CLUSTER", "GETKEYSINSLOT", 100, 10}, "127.0.0.1:7000"},
+		{[]interface{}{"CLUSTER", "GETKEYSINSLOT", 16000, 10}, "127.0.0.1:7001"},
+		{[]interface{}{"CLUSTER", "COUNTKEYSINSLOT", 100}, "127.0.0.1:7000"},
+		{[]interface{}{"CLUSTER", "COUNTKEYSINSLOT", 16000}, "127.0.0.1:7001"},
+		{[]interface{}{"GET", "foo"}, state.slots[Slot("foo")]},
+	}
+
+	for _, tc := range cases {
+		node, err := c.cmdNode(NewCmd(tc.args...))
+		a.NilError(err)
+		a.Equal(node.Options().Addr, tc.addr)
+	}
+}
+
+func TestClusterNoRetryAfterSent(t *testing.T) {
+	a := assert.New(t)
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	a.NilError(err)
+	defer l.Close()
+
+	// 服务器读到命令之后直接断开连接，模拟命令发送之后结点下线。
+	var incrs int32
+	go func() {
+		for {
+			cn, err := l.Accept()
+
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer cn.Close()
+				args, _ := NewReader(bufio.NewReader(cn)).ReadReply()
+
+				if arr, ok := args.([]interface{}); ok && len(arr) > 0 && string(arr[0].([]byte)) == "INCR" {
+					atomic.AddInt32(&incrs, 1)
+				}
+			}()
+		}
+	}()
+
+	addr := l.Addr().String()
+	c := NewClusterClient(&ClusterOptions{
+		Addrs:    []string{addr},
+		Protocol: 2,
+	})
+	defer c.Close()
+
+	state := &clusterState{
+		masters: []string{addr},
+	}
+
+	for slot := 0; slot < HashSlots; slot++ {
+		state.slots[slot] = addr
+	}
+
+	c.state.Store(state)
+
+	// 命令已经发送给服务器，服务器可能已经执行了命令，不能重试。
+	a.Assert(c.Process(NewCmd("INCR", "counter")) != nil)
+	a.Equal(atomic.LoadInt32(&incrs), int32(1))
+}
@@ -0,0 +1,169 @@
+package driver
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cmder 代表一个可以发送给 Redis 服务器执行的命令。
+type Cmder interface {
+	// Args 返回命令的所有参数，第一个参数是命令名。
+	Args() []interface{}
+
+	// Reply 返回 Redis 服务器的应答，具体类型参考 Reader 的说明。
+	Reply() interface{}
+
+	// Err 返回执行命令时遇到的错误。
+	Err() error
+
+	// SetReply 设置 Redis 服务器的应答。
+	SetReply(reply interface{})
+
+	// SetErr 设置执行命令时遇到的错误。
+	SetErr(err error)
+
+	// ReadTimeout 返回读取这个命令应答时的超时时间，
+	// 如果 ok 为 false，则使用连接默认的读超时时间。
+	ReadTimeout() (timeout time.Duration, ok bool)
+
+	// Interrupt 返回一个中断读取应答的 channel，channel 关闭时正在读取应答的连接会被中断并丢弃，
+	// 返回 nil 代表不会被中断。
+	Interrupt() <-chan struct{}
+
+	// TrackedKeys 返回开启 CLIENT TRACKING 时需要记录的 key，通常是客户端缓存了应答的 key，
+	// 读取这些 key 的连接断开时，这些 key 会被当做已经失效。
+	TrackedKeys() []string
+}
+
+// Cmd 是 Cmder 的默认实现。
+type Cmd struct {
+	args  []interface{}
+	reply interface{}
+	err   error
+
+	readTimeout    time.Duration
+	hasReadTimeout bool
+
+	interrupt   <-chan struct{}
+	trackedKeys []string
+}
+
+var _ Cmder = new(Cmd)
+
+// NewCmd 创建一个命令。
+func NewCmd(args ...interface{}) *Cmd {
+	return &Cmd{
+		args: args,
+	}
+}
+
+// Args 返回命令的所有参数。
+func (cmd *Cmd) Args() []interface{} {
+	return cmd.args
+}
+
+// Reply 返回 Redis 服务器的应答。
+func (cmd *Cmd) Reply() interface{} {
+	return cmd.reply
+}
+
+// Err 返回执行命令时遇到的错误。
+func (cmd *Cmd) Err() error {
+	return cmd.err
+}
+
+// SetReply 设置 Redis 服务器的应答。
+func (cmd *Cmd) SetReply(reply interface{}) {
+	cmd.reply = reply
+}
+
+// SetErr 设置执行命令时遇到的错误。
+func (cmd *Cmd) SetErr(err error) {
+	cmd.err = err
+}
+
+// ReadTimeout 返回读取这个命令应答时的超时时间。
+func (cmd *Cmd) ReadTimeout() (timeout time.Duration, ok bool) {
+	return cmd.readTimeout, cmd.hasReadTimeout
+}
+
+// SetBlockTimeout 告知驱动这个命令会在服务器上阻塞 timeout 时间，
+// 读取应答时会在 timeout 基础上多等待一段时间，timeout 为 0 代表永久阻塞。
+func (cmd *Cmd) SetBlockTimeout(timeout time.Duration) {
+	if timeout > 0 {
+		timeout += blockReadTimeoutDelta
+	}
+
+	cmd.readTimeout = timeout
+	cmd.hasReadTimeout = true
+}
+
+// Interrupt 返回中断读取应答的 channel。
+func (cmd *Cmd) Interrupt() <-chan struct{} {
+	return cmd.interrupt
+}
+
+// SetInterrupt 设置中断读取应答的 channel，通常是 ctx.Done()。
+// 阻塞命令被放弃之后依然会在服务器上执行，比如 BLPOP 会在之后弹出数据并丢弃应答，
+// 因此放弃阻塞命令时必须中断并丢弃连接，服务器会在连接断开时取消阻塞。
+func (cmd *Cmd) SetInterrupt(done <-chan struct{}) {
+	cmd.interrupt = done
+}
+
+// TrackedKeys 返回开启 CLIENT TRACKING 时需要记录的 key。
+func (cmd *Cmd) TrackedKeys() []string {
+	return cmd.trackedKeys
+}
+
+// SetTrackedKeys 设置开启 CLIENT TRACKING 时需要记录的 key。
+func (cmd *Cmd) SetTrackedKeys(keys ...string) {
+	cmd.trackedKeys = keys
+}
+
+const blockReadTimeoutDelta = 10 * time.Second
+
+// cmdName 返回小写的命令名。
+func cmdName(cmd Cmder) string {
+	return strings.ToLower(cmdArg(cmd, 0))
+}
+
+// cmdArg 以字符串形式返回命令的第 i 个参数，如果参数不存在或者不是字符串和整数，返回空字符串。
+func cmdArg(cmd Cmder, i int) string {
+	args := cmd.Args()
+
+	if i < 0 || i >= len(args) {
+		return ""
+	}
+
+	switch v := args[i].(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	}
+
+	return ""
+}
+
+func setCmdsErr(cmds []Cmder, err error) {
+	for _, cmd := range cmds {
+		if cmd.Err() == nil {
+			cmd.SetErr(err)
+		}
+	}
+}
+
+func cmdsFirstErr(cmds []Cmder) error {
+	for _, cmd := range cmds {
+		if err := cmd.Err(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,231 @@
+package driver
+
+import (
+	"bufio"
+	"net"
+	"sync/atomic"
+	"time"
+)
+
+const connBufferSize = 32 * 1024
+
+// Conn 代表一个到 Redis 服务器的连接，Conn 不是并发安全的。
+type Conn struct {
+	netConn net.Conn
+	rd      *Reader
+	wr      *Writer
+
+	readTimeout  time.Duration
+	writeTimeout time.Duration
+
+	protocol int
+	onPush   func(cn *Conn, push Push)
+	readPush bool // readPush 代表 push 消息需要作为应答返回，订阅连接需要这样读取消息。
+
+	trackingGen int64                         // trackingGen 是开启 CLIENT TRACKING 时 tracker 的版本号。
+	onTrack     func(cn *Conn, keys []string) // onTrack 在成功读取 Cmder.TrackedKeys 不为空的命令应答之后调用。
+	onClose     func(cn *Conn)                // onClose 在连接第一次关闭时调用。
+	closed      int32                         // closed 代表连接已经关闭，需要原子操作。
+
+	createdAt time.Time
+	usedAt    int64 // usedAt 是最后一次使用的 unix 时间戳，需要原子操作。
+	broken    int32 // broken 代表连接已经不可用，需要原子操作。
+	pooled    bool
+}
+
+func newConn(netConn net.Conn, opt *Options) *Conn {
+	cn := &Conn{
+		netConn:      netConn,
+		rd:           NewReader(bufio.NewReaderSize(netConn, connBufferSize)),
+		wr:           NewWriter(bufio.NewWriterSize(netConn, connBufferSize)),
+		readTimeout:  opt.ReadTimeout,
+		writeTimeout: opt.WriteTimeout,
+		protocol:     2,
+		onPush:       opt.OnPush,
+		createdAt:    time.Now(),
+	}
+	cn.setUsedAt(cn.createdAt)
+	return cn
+}
+
+// RemoteAddr 返回连接的远端地址。
+func (cn *Conn) RemoteAddr() net.Addr {
+	return cn.netConn.RemoteAddr()
+}
+
+// Protocol 返回连接使用的 RESP 协议版本。
+func (cn *Conn) Protocol() int {
+	return cn.protocol
+}
+
+// Process 在这个连接上执行一个命令。
+func (cn *Conn) Process(cmd Cmder) error {
+	if err := cn.writeCmds(cmd); err != nil {
+		cmd.SetErr(err)
+		return err
+	}
+
+	return cn.readReply(cmd)
+}
+
+// processPipeline 在这个连接上批量执行命令，返回第一个错误。
+func (cn *Conn) processPipeline(cmds []Cmder) error {
+	if err := cn.writeCmds(cmds...); err != nil {
+		setCmdsErr(cmds, err)
+		return err
+	}
+
+	for i, cmd := range cmds {
+		if err := cn.readReply(cmd); err != nil && cn.isBroken() {
+			setCmdsErr(cmds[i+1:], err)
+			break
+		}
+	}
+
+	return cmdsFirstErr(cmds)
+}
+
+// Close 关闭连接。
+func (cn *Conn) Close() error {
+	err := cn.netConn.Close()
+
+	if cn.onClose != nil && atomic.CompareAndSwapInt32(&cn.closed, 0, 1) {
+		cn.onClose(cn)
+	}
+
+	return err
+}
+
+// interruptOn 在 done 关闭时将读超时设置成当前时间，中断正在进行的读取，被中断的连接不能再使用。
+// 读取结束之后必须调用返回的 stop，stop 返回之后不会再修改这个连接。
+func (cn *Conn) interruptOn(done <-chan struct{}) (stop func()) {
+	stopCh := make(chan struct{})
+	exited := make(chan struct{})
+
+	go func() {
+		defer close(exited)
+
+		select {
+		case <-done:
+			cn.setBroken()
+			cn.netConn.SetReadDeadline(time.Now())
+		case <-stopCh:
+		}
+	}()
+
+	return func() {
+		close(stopCh)
+		<-exited
+	}
+}
+
+func (cn *Conn) isBroken() bool {
+	return atomic.LoadInt32(&cn.broken) != 0
+}
+
+func (cn *Conn) setBroken() {
+	atomic.StoreInt32(&cn.broken, 1)
+}
+
+func (cn *Conn) usedAtTime() time.Time {
+	return time.Unix(atomic.LoadInt64(&cn.usedAt), 0)
+}
+
+func (cn *Conn) setUsedAt(t time.Time) {
+	atomic.StoreInt64(&cn.usedAt, t.Unix())
+}
+
+func (cn *Conn) writeCmds(cmds ...Cmder) error {
+	cn.setUsedAt(time.Now())
+
+	if cn.writeTimeout > 0 {
+		cn.netConn.SetWriteDeadline(time.Now().Add(cn.writeTimeout))
+	} else {
+		cn.netConn.SetWriteDeadline(time.Time{})
+	}
+
+	for _, cmd := range cmds {
+		if err := cn.wr.WriteArgs(cmd.Args()); err != nil {
+			// 缓冲区中可能已经有写了一半的命令，这个连接不能再用了。
+			cn.setBroken()
+			return err
+		}
+	}
+
+	if err := cn.wr.Flush(); err != nil {
+		cn.setBroken()
+		return err
+	}
+
+	return nil
+}
+
+// readReply 读取 cmd 的应答并保存在 cmd 中。
+// 如果 Redis 返回错误应答，连接依然可用；其他错误都会导致连接不可用。
+func (cn *Conn) readReply(cmd Cmder) error {
+	reply, err := cn.read(cmd)
+
+	if err != nil {
+		cmd.SetErr(err)
+		return err
+	}
+
+	cmd.SetReply(reply)
+
+	if cn.onTrack != nil {
+		if keys := cmd.TrackedKeys(); len(keys) != 0 {
+			cn.onTrack(cn, keys)
+		}
+	}
+
+	return nil
+}
+
+func (cn *Conn) read(cmd Cmder) (reply interface{}, err error) {
+	timeout := cn.readTimeout
+
+	if cmd != nil {
+		if t, ok := cmd.ReadTimeout(); ok {
+			timeout = t
+		}
+	}
+
+	if timeout > 0 {
+		cn.netConn.SetReadDeadline(time.Now().Add(timeout))
+	} else {
+		cn.netConn.SetReadDeadline(time.Time{})
+	}
+
+	if cmd != nil {
+		if done := cmd.Interrupt(); done != nil {
+			defer cn.interruptOn(done)()
+		}
+	}
+
+	for {
+		reply, err = cn.rd.ReadReply()
+
+		if err != nil {
+			break
+		}
+
+		push, ok := reply.(Push)
+
+		if !ok || cn.readPush {
+			break
+		}
+
+		// 普通连接上的 push 消息不是任何命令的应答，处理完之后继续读取真正的应答。
+		if cn.onPush != nil {
+			cn.onPush(cn, push)
+		}
+	}
+
+	if err != nil {
+		if _, ok := err.(Error); !ok {
+			cn.setBroken()
+		}
+	}
+
+	return
+}
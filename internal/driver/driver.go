@@ -1,6 +1,23 @@
+// Package driver 实现了 Redis 的底层协议驱动，包括 RESP 编解码、连接池、集群路由和哨兵发现。
 package driver
 
-// Driver 代表一个 Redis 底层协议驱动，当前还未实现。
+// Driver 代表 Redis 底层协议驱动，负责将命令发送给 Redis 服务器并读取应答。
 type Driver interface {
-	// TODO:
+	// Process 执行一个命令，应答和错误都会保存在 cmd 中，返回值等于 cmd.Err()。
+	Process(cmd Cmder) error
+
+	// ProcessPipeline 通过 pipeline 批量执行命令，返回第一个出错命令的错误。
+	ProcessPipeline(cmds []Cmder) error
+
+	// ProcessTxPipeline 在 MULTI/EXEC 事务中批量执行命令，返回第一个出错命令的错误。
+	// 如果事务因为 WATCH 的 key 被修改而失败，返回 ErrTxFailed。
+	ProcessTxPipeline(cmds []Cmder) error
+}
+
+// Client 代表一个 Redis 客户端，可以是单机、集群或者哨兵模式。
+type Client interface {
+	Driver
+
+	// Close 关闭客户端和所有连接。
+	Close() error
 }
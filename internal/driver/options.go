@@ -0,0 +1,119 @@
+package driver
+
+import (
+	"net"
+	"runtime"
+	"time"
+)
+
+// Options 代表连接一个 Redis 服务器的配置。
+type Options struct {
+	Addr     string // Addr 是 Redis 服务地址，格式是 host:port。
+	Password string // Password 是连接 Redis 的密码。
+	DB       int    // DB 是连接后默认选择的数据库。
+
+	// Dialer 用来建立到 Redis 服务器的连接，默认使用 net.Dialer 连接 Addr。
+	Dialer func() (net.Conn, error)
+
+	// OnConnect 在新连接建立并完成认证和选择数据库之后调用，返回错误会关闭这个连接。
+	OnConnect func(cn *Conn) error
+
+	// Protocol 是使用的 RESP 协议版本，可以是 2 或 3，默认是 3。
+	// 使用 RESP3 时新连接会先发送 HELLO 3，如果服务器不支持则自动退回 RESP2。
+	Protocol int
+
+	// OnPush 在普通连接上收到 RESP3 push 消息时调用，默认丢弃 push 消息。
+	// OnPush 在读取应答的 goroutine 中调用，不能阻塞，也不能在 cn 上执行命令。
+	OnPush func(cn *Conn, push Push)
+
+	// OnInvalidate 不为 nil 时，连接池里的每个连接都会开启 CLIENT TRACKING，需要 Redis 6 以上版本。
+	// 收到失效消息时调用 OnInvalidate，keys 是失效的 key，keys 为 nil 代表需要清空所有缓存，
+	// 比如服务器执行了 FLUSHALL，或者接收失效消息的连接断开。
+	// 开启 tracking 的连接断开之后，服务器不会再为它读过的 key 发送失效消息，
+	// 这时会用 Cmder.TrackedKeys 记录的、通过这个连接读取的 key 调用 OnInvalidate。
+	OnInvalidate func(keys []string)
+
+	DialTimeout  time.Duration // DialTimeout 是连接超时，默认 5s。
+	ReadTimeout  time.Duration // ReadTimeout 是读超时，默认 3s，-1 代表没有超时。
+	WriteTimeout time.Duration // WriteTimeout 是写超时，默认等于 ReadTimeout，-1 代表没有超时。
+
+	PoolSize           int           // PoolSize 是连接池最大连接数，默认是 runtime.NumCPU 的 10 倍。
+	PoolTimeout        time.Duration // PoolTimeout 是连接池满时等待空闲连接的时间，默认是 ReadTimeout + 1s。
+	IdleTimeout        time.Duration // IdleTimeout 是空闲连接被关闭前的最长空闲时间，默认 5m，-1 代表不关闭空闲连接。
+	IdleCheckFrequency time.Duration // IdleCheckFrequency 是检查空闲连接的周期，默认 1m。
+
+	// MaxRetries 是遇到网络错误时的最大重试次数，默认不重试。
+	MaxRetries int
+}
+
+const (
+	defaultDialTimeout        = 5 * time.Second
+	defaultReadTimeout        = 3 * time.Second
+	defaultIdleTimeout        = 5 * time.Minute
+	defaultIdleCheckFrequency = time.Minute
+)
+
+func (opt *Options) init() {
+	if opt.Protocol != 2 {
+		opt.Protocol = 3
+	}
+
+	if opt.DialTimeout == 0 {
+		opt.DialTimeout = defaultDialTimeout
+	}
+
+	if opt.Dialer == nil {
+		addr := opt.Addr
+		timeout := opt.DialTimeout
+		opt.Dialer = func() (net.Conn, error) {
+			dialer := &net.Dialer{
+				Timeout:   timeout,
+				KeepAlive: 5 * time.Minute,
+			}
+			return dialer.Dial("tcp", addr)
+		}
+	}
+
+	switch opt.ReadTimeout {
+	case -1:
+		opt.ReadTimeout = 0
+	case 0:
+		opt.ReadTimeout = defaultReadTimeout
+	}
+
+	switch opt.WriteTimeout {
+	case -1:
+		opt.WriteTimeout = 0
+	case 0:
+		opt.WriteTimeout = opt.ReadTimeout
+	}
+
+	if opt.PoolSize <= 0 {
+		opt.PoolSize = 10 * runtime.NumCPU()
+	}
+
+	if opt.PoolTimeout == 0 {
+		opt.PoolTimeout = opt.ReadTimeout + time.Second
+	}
+
+	switch opt.IdleTimeout {
+	case -1:
+		opt.IdleTimeout = 0
+	case 0:
+		opt.IdleTimeout = defaultIdleTimeout
+	}
+
+	if opt.IdleCheckFrequency <= 0 {
+		opt.IdleCheckFrequency = defaultIdleCheckFrequency
+	}
+
+	if opt.MaxRetries < 0 {
+		opt.MaxRetries = 0
+	}
+}
+
+// clone 复制一份配置，用于基于同一份配置连接不同的服务器。
+func (opt *Options) clone() *Options {
+	cloned := *opt
+	return &cloned
+}
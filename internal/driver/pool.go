@@ -0,0 +1,442 @@
+package driver
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// ErrClosed 代表连接池已经关闭。
+	ErrClosed = errors.New("go-redis/driver: client is closed")
+
+	// ErrPoolTimeout 代表在 PoolTimeout 时间内没有拿到可用连接。
+	ErrPoolTimeout = errors.New("go-redis/driver: connection pool timeout")
+)
+
+// PoolStats 代表连接池的统计数据。
+type PoolStats struct {
+	Hits     uint32 // Hits 是从空闲连接中拿到连接的次数。
+	Misses   uint32 // Misses 是需要新建连接的次数。
+	Timeouts uint32 // Timeouts 是等待连接超时的次数。
+
+	TotalConns uint32 // TotalConns 是连接池中所有连接的数量。
+	IdleConns  uint32 // IdleConns 是连接池中空闲连接的数量。
+	StaleConns uint32 // StaleConns 是因为空闲太久而被关闭的连接数量。
+}
+
+// Pool 是一个 Redis 连接池。
+type Pool struct {
+	opt *Options
+
+	// queue 用来限制同时使用的连接数量，每个正在使用的连接占用一个位置。
+	queue chan struct{}
+
+	mu        sync.Mutex
+	conns     map[*Conn]struct{}
+	idleConns []*Conn
+	stats     PoolStats
+	closed    bool
+	closedCh  chan struct{}
+
+	// noHello 代表服务器不支持 HELLO，新连接直接使用 RESP2，需要原子操作。
+	noHello int32
+
+	// tracker 在设置了 OnInvalidate 时为每个连接开启 CLIENT TRACKING。
+	tracker *tracker
+}
+
+// NewPool 创建一个连接池。
+func NewPool(opt *Options) *Pool {
+	opt.init()
+
+	p := &Pool{
+		opt:      opt,
+		queue:    make(chan struct{}, opt.PoolSize),
+		conns:    map[*Conn]struct{}{},
+		closedCh: make(chan struct{}),
+	}
+
+	if opt.OnInvalidate != nil {
+		p.tracker = newTracker(p, opt.OnInvalidate)
+	}
+
+	if opt.IdleTimeout > 0 {
+		go p.reaper(opt.IdleCheckFrequency)
+	}
+
+	return p
+}
+
+// Get 从连接池中拿到一个连接，用完之后必须调用 Put 或 Remove 归还。
+func (p *Pool) Get() (*Conn, error) {
+	if p.isClosed() {
+		return nil, ErrClosed
+	}
+
+	if err := p.waitTurn(); err != nil {
+		return nil, err
+	}
+
+	for {
+		p.mu.Lock()
+		cn := p.popIdle()
+		p.mu.Unlock()
+
+		if cn == nil {
+			break
+		}
+
+		if p.isStale(cn) {
+			p.closeConn(cn)
+			continue
+		}
+
+		if !p.isTracked(cn) {
+			p.mu.Lock()
+			delete(p.conns, cn)
+			p.mu.Unlock()
+			cn.Close()
+			continue
+		}
+
+		p.mu.Lock()
+		p.stats.Hits++
+		p.mu.Unlock()
+		return cn, nil
+	}
+
+	p.mu.Lock()
+	p.stats.Misses++
+	p.mu.Unlock()
+
+	cn, err := p.NewConn()
+
+	if err != nil {
+		p.freeTurn()
+		return nil, err
+	}
+
+	if p.tracker != nil {
+		if err := p.tracker.track(cn); err != nil {
+			cn.Close()
+			p.freeTurn()
+			return nil, err
+		}
+	}
+
+	p.mu.Lock()
+
+	if p.closed {
+		p.mu.Unlock()
+		cn.Close()
+		p.freeTurn()
+		return nil, ErrClosed
+	}
+
+	cn.pooled = true
+	p.conns[cn] = struct{}{}
+	p.mu.Unlock()
+	return cn, nil
+}
+
+// Put 将一个连接归还到连接池，如果连接已经不可用，连接会被关闭。
+func (p *Pool) Put(cn *Conn) {
+	if cn.isBroken() || !p.isTracked(cn) {
+		p.Remove(cn)
+		return
+	}
+
+	if !cn.pooled {
+		cn.Close()
+		return
+	}
+
+	p.mu.Lock()
+
+	if p.closed {
+		p.mu.Unlock()
+		p.Remove(cn)
+		return
+	}
+
+	p.idleConns = append(p.idleConns, cn)
+	p.mu.Unlock()
+	p.freeTurn()
+}
+
+// Remove 将一个连接从连接池中删除并关闭。
+func (p *Pool) Remove(cn *Conn) {
+	if cn.pooled {
+		p.mu.Lock()
+		delete(p.conns, cn)
+		p.mu.Unlock()
+		p.freeTurn()
+	}
+
+	cn.Close()
+}
+
+// NewConn 建立一个不属于连接池的新连接，并完成认证和选择数据库，
+// 这个连接用完之后需要调用 Close 关闭。
+func (p *Pool) NewConn() (*Conn, error) {
+	netConn, err := p.opt.Dialer()
+
+	if err != nil {
+		return nil, err
+	}
+
+	cn := newConn(netConn, p.opt)
+
+	if err := p.initConn(cn); err != nil {
+		cn.Close()
+		return nil, err
+	}
+
+	return cn, nil
+}
+
+func (p *Pool) initConn(cn *Conn) error {
+	var cmds []Cmder
+
+	if p.opt.Protocol == 3 && atomic.LoadInt32(&p.noHello) == 0 {
+		if err := p.hello(cn); err != nil {
+			return err
+		}
+	}
+
+	if cn.protocol == 2 && p.opt.Password != "" {
+		cmds = append(cmds, NewCmd("AUTH", p.opt.Password))
+	}
+
+	if p.opt.DB != 0 {
+		cmds = append(cmds, NewCmd("SELECT", p.opt.DB))
+	}
+
+	if len(cmds) != 0 {
+		if err := cn.processPipeline(cmds); err != nil {
+			return err
+		}
+	}
+
+	if p.opt.OnConnect != nil {
+		return p.opt.OnConnect(cn)
+	}
+
+	return nil
+}
+
+// hello 通过 HELLO 3 将连接切换到 RESP3，同时完成认证。
+// 如果服务器不支持 RESP3，连接保持使用 RESP2，之后的新连接也不再尝试 HELLO。
+func (p *Pool) hello(cn *Conn) error {
+	args := []interface{}{"HELLO", 3}
+
+	if p.opt.Password != "" {
+		args = append(args, "AUTH", "default", p.opt.Password)
+	}
+
+	err := cn.Process(NewCmd(args...))
+
+	if err == nil {
+		cn.protocol = 3
+		return nil
+	}
+
+	if e, ok := err.(Error); ok && isHelloUnsupported(e) {
+		atomic.StoreInt32(&p.noHello, 1)
+		return nil
+	}
+
+	return err
+}
+
+// isHelloUnsupported 判断 HELLO 的错误是否代表服务器不支持 RESP3。
+// Redis 6 之前的版本没有 HELLO 命令，不支持指定协议版本时会返回 NOPROTO。
+func isHelloUnsupported(err Error) bool {
+	msg := string(err)
+	return strings.HasPrefix(msg, "NOPROTO") || strings.HasPrefix(strings.ToLower(msg), "err unknown command")
+}
+
+// Filter 关闭所有满足 fn 条件的空闲连接，正在使用的连接会在归还时关闭。
+func (p *Pool) Filter(fn func(cn *Conn) bool) {
+	p.mu.Lock()
+	idle := p.idleConns[:0]
+	var removed []*Conn
+
+	for _, cn := range p.idleConns {
+		if fn(cn) {
+			removed = append(removed, cn)
+			delete(p.conns, cn)
+		} else {
+			idle = append(idle, cn)
+		}
+	}
+
+	p.idleConns = idle
+
+	for cn := range p.conns {
+		if fn(cn) {
+			cn.setBroken()
+		}
+	}
+
+	p.mu.Unlock()
+
+	for _, cn := range removed {
+		cn.Close()
+	}
+
+	if p.tracker != nil {
+		p.tracker.filter(fn)
+	}
+}
+
+// Stats 返回连接池的统计数据。
+func (p *Pool) Stats() *PoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := p.stats
+	stats.TotalConns = uint32(len(p.conns))
+	stats.IdleConns = uint32(len(p.idleConns))
+	return &stats
+}
+
+// Close 关闭连接池和所有的连接。
+func (p *Pool) Close() error {
+	p.mu.Lock()
+
+	if p.closed {
+		p.mu.Unlock()
+		return ErrClosed
+	}
+
+	p.closed = true
+	close(p.closedCh)
+	conns := p.conns
+	p.conns = map[*Conn]struct{}{}
+	p.idleConns = nil
+	p.mu.Unlock()
+
+	if p.tracker != nil {
+		p.tracker.close()
+	}
+
+	var firstErr error
+
+	for cn := range conns {
+		if err := cn.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+func (p *Pool) isClosed() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.closed
+}
+
+func (p *Pool) waitTurn() error {
+	select {
+	case p.queue <- struct{}{}:
+		return nil
+	default:
+	}
+
+	timer := time.NewTimer(p.opt.PoolTimeout)
+	defer timer.Stop()
+
+	select {
+	case p.queue <- struct{}{}:
+		return nil
+	case <-p.closedCh:
+		return ErrClosed
+	case <-timer.C:
+		p.mu.Lock()
+		p.stats.Timeouts++
+		p.mu.Unlock()
+		return ErrPoolTimeout
+	}
+}
+
+func (p *Pool) freeTurn() {
+	<-p.queue
+}
+
+// popIdle 取出最近归还的空闲连接，调用者需要持有锁。
+func (p *Pool) popIdle() *Conn {
+	if len(p.idleConns) == 0 {
+		return nil
+	}
+
+	idx := len(p.idleConns) - 1
+	cn := p.idleConns[idx]
+	p.idleConns = p.idleConns[:idx]
+	return cn
+}
+
+func (p *Pool) isStale(cn *Conn) bool {
+	if p.opt.IdleTimeout <= 0 {
+		return false
+	}
+
+	return time.Since(cn.usedAtTime()) >= p.opt.IdleTimeout
+}
+
+// isTracked 判断 cn 开启的 CLIENT TRACKING 是否依然有效，没有开启 tracking 的连接池总是返回 true。
+func (p *Pool) isTracked(cn *Conn) bool {
+	return p.tracker == nil || p.tracker.valid(cn)
+}
+
+// closeConn 关闭一个已经从空闲列表取出的连接。
+func (p *Pool) closeConn(cn *Conn) {
+	p.mu.Lock()
+	delete(p.conns, cn)
+	p.stats.StaleConns++
+	p.mu.Unlock()
+	cn.Close()
+}
+
+// reaper 定期关闭空闲太久的连接。
+func (p *Pool) reaper(frequency time.Duration) {
+	ticker := time.NewTicker(frequency)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.reapStaleConns()
+		case <-p.closedCh:
+			return
+		}
+	}
+}
+
+func (p *Pool) reapStaleConns() {
+	var stale []*Conn
+
+	p.mu.Lock()
+	idle := p.idleConns[:0]
+
+	for _, cn := range p.idleConns {
+		if p.isStale(cn) {
+			stale = append(stale, cn)
+			delete(p.conns, cn)
+			p.stats.StaleConns++
+		} else {
+			idle = append(idle, cn)
+		}
+	}
+
+	p.idleConns = idle
+	p.mu.Unlock()
+
+	for _, cn := range stale {
+		cn.Close()
+	}
+}
@@ -0,0 +1,430 @@
+package driver
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"time"
+)
+
+// RESP2 协议中各种应答的类型前缀。
+const (
+	replyStatus  = '+'
+	replyError   = '-'
+	replyInteger = ':'
+	replyBulk    = '$'
+	replyArray   = '*'
+)
+
+// RESP3 协议新增的应答类型前缀。
+const (
+	replyNull      = '_'
+	replyDouble    = ','
+	replyBool      = '#'
+	replyBlobError = '!'
+	replyVerbatim  = '='
+	replyBigNumber = '('
+	replyMap       = '%'
+	replySet       = '~'
+	replyAttribute = '|'
+	replyPush      = '>'
+)
+
+var (
+	// ErrProtocol 代表 Redis 服务器返回了无法解析的数据。
+	ErrProtocol = errors.New("go-redis/driver: protocol error")
+)
+
+// Error 代表 Redis 服务器返回的错误应答，比如 `ERR unknown command`。
+type Error string
+
+func (e Error) Error() string {
+	return string(e)
+}
+
+// Status 代表 Redis 服务器返回的状态应答，比如 `OK`。
+type Status string
+
+// Map 代表 RESP3 的 map 应答，按照服务器返回的顺序保存成 [key1, value1, key2, value2, ...]，
+// 与 RESP2 中 HGETALL 等命令的应答格式一致。
+type Map []interface{}
+
+// Push 代表 RESP3 的 push 消息，比如订阅消息和 client-side caching 的失效通知。
+// Push 消息不是任何命令的应答，第一个元素是消息类型。
+type Push []interface{}
+
+// Writer 将命令编码成 RESP2 协议格式。
+type Writer struct {
+	w      *bufio.Writer
+	buf    []byte // buf 用于格式化数字参数。
+	lenBuf []byte // lenBuf 用于格式化长度，不能与 buf 共用，否则会覆盖正在写入的参数。
+}
+
+// NewWriter 创建一个 Writer。
+func NewWriter(w *bufio.Writer) *Writer {
+	return &Writer{
+		w:      w,
+		buf:    make([]byte, 0, 64),
+		lenBuf: make([]byte, 0, 24),
+	}
+}
+
+// WriteArgs 将一个命令的所有参数编码成 bulk string 数组。
+func (w *Writer) WriteArgs(args []interface{}) error {
+	w.w.WriteByte(replyArray)
+	w.writeLen(len(args))
+
+	for _, arg := range args {
+		if err := w.writeArg(arg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Flush 将缓冲区中的数据写入连接。
+func (w *Writer) Flush() error {
+	return w.w.Flush()
+}
+
+func (w *Writer) writeLen(n int) {
+	w.lenBuf = strconv.AppendInt(w.lenBuf[:0], int64(n), 10)
+	w.lenBuf = append(w.lenBuf, '\r', '\n')
+	w.w.Write(w.lenBuf)
+}
+
+func (w *Writer) writeBytes(b []byte) {
+	w.w.WriteByte(replyBulk)
+	w.writeLen(len(b))
+	w.w.Write(b)
+	w.w.WriteString("\r\n")
+}
+
+func (w *Writer) writeString(s string) {
+	w.w.WriteByte(replyBulk)
+	w.writeLen(len(s))
+	w.w.WriteString(s)
+	w.w.WriteString("\r\n")
+}
+
+func (w *Writer) writeArg(arg interface{}) error {
+	switch v := arg.(type) {
+	case string:
+		w.writeString(v)
+	case []byte:
+		w.writeBytes(v)
+	case int:
+		w.writeBytes(strconv.AppendInt(w.buf[:0], int64(v), 10))
+	case int8:
+		w.writeBytes(strconv.AppendInt(w.buf[:0], int64(v), 10))
+	case int16:
+		w.writeBytes(strconv.AppendInt(w.buf[:0], int64(v), 10))
+	case int32:
+		w.writeBytes(strconv.AppendInt(w.buf[:0], int64(v), 10))
+	case int64:
+		w.writeBytes(strconv.AppendInt(w.buf[:0], v, 10))
+	case uint:
+		w.writeBytes(strconv.AppendUint(w.buf[:0], uint64(v), 10))
+	case uint8:
+		w.writeBytes(strconv.AppendUint(w.buf[:0], uint64(v), 10))
+	case uint16:
+		w.writeBytes(strconv.AppendUint(w.buf[:0], uint64(v), 10))
+	case uint32:
+		w.writeBytes(strconv.AppendUint(w.buf[:0], uint64(v), 10))
+	case uint64:
+		w.writeBytes(strconv.AppendUint(w.buf[:0], v, 10))
+	case float32:
+		w.writeBytes(strconv.AppendFloat(w.buf[:0], float64(v), 'f', -1, 32))
+	case float64:
+		w.writeBytes(strconv.AppendFloat(w.buf[:0], v, 'f', -1, 64))
+	case bool:
+		if v {
+			w.writeString("1")
+		} else {
+			w.writeString("0")
+		}
+	case time.Duration:
+		w.writeBytes(strconv.AppendInt(w.buf[:0], int64(v), 10))
+	case nil:
+		w.writeString("")
+	case fmt.Stringer:
+		w.writeString(v.String())
+	default:
+		return fmt.Errorf("go-redis/driver: can't marshal %T", arg)
+	}
+
+	return nil
+}
+
+// Reader 从连接中读取 RESP2 或 RESP3 协议格式的应答。
+//
+// 应答会被解析成以下 Go 类型：
+//     - 状态应答：Status
+//     - 错误应答和 RESP3 blob error：Error，最外层的错误应答会以 error 形式返回
+//     - 整数应答：int64
+//     - bulk string：[]byte
+//     - 数组和 RESP3 set：[]interface{}
+//     - 空 bulk string、空数组和 RESP3 null：nil
+//     - RESP3 double：float64
+//     - RESP3 boolean：bool
+//     - RESP3 verbatim string：[]byte，去掉了表示格式的前缀
+//     - RESP3 big number：*big.Int
+//     - RESP3 map：Map
+//     - RESP3 push：Push
+//
+// RESP3 的 attribute 会被忽略。
+type Reader struct {
+	r *bufio.Reader
+}
+
+// NewReader 创建一个 Reader。
+func NewReader(r *bufio.Reader) *Reader {
+	return &Reader{
+		r: r,
+	}
+}
+
+// ReadReply 读取一个完整的应答。
+// 如果应答是错误应答，返回的 err 是 Error 类型。
+func (r *Reader) ReadReply() (reply interface{}, err error) {
+	reply, err = r.readReply()
+
+	if err != nil {
+		return
+	}
+
+	if e, ok := reply.(Error); ok {
+		return nil, e
+	}
+
+	return
+}
+
+func (r *Reader) readReply() (reply interface{}, err error) {
+	line, err := r.readLine()
+
+	if err != nil {
+		return
+	}
+
+	switch line[0] {
+	case replyStatus:
+		return Status(line[1:]), nil
+
+	case replyError:
+		return Error(line[1:]), nil
+
+	case replyInteger:
+		return parseInt(line[1:])
+
+	case replyBulk:
+		n, err := parseLen(line[1:])
+
+		if err != nil || n < 0 {
+			return nil, err
+		}
+
+		return r.readBulk(n)
+
+	case replyArray, replySet:
+		n, err := parseLen(line[1:])
+
+		if err != nil || n < 0 {
+			return nil, err
+		}
+
+		return r.readArray(n)
+
+	case replyNull:
+		return nil, nil
+
+	case replyDouble:
+		return parseDouble(line[1:])
+
+	case replyBool:
+		return parseBool(line[1:])
+
+	case replyBlobError:
+		n, err := parseLen(line[1:])
+
+		if err != nil {
+			return nil, err
+		}
+
+		if n < 0 {
+			return nil, fmt.Errorf("%v: invalid blob error length %d", ErrProtocol, n)
+		}
+
+		b, err := r.readBulk(n)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return Error(b), nil
+
+	case replyVerbatim:
+		n, err := parseLen(line[1:])
+
+		if err != nil {
+			return nil, err
+		}
+
+		if n < 0 {
+			return nil, fmt.Errorf("%v: invalid verbatim string length %d", ErrProtocol, n)
+		}
+
+		b, err := r.readBulk(n)
+
+		if err != nil {
+			return nil, err
+		}
+
+		// verbatim string 的格式是 `txt:内容`，前 4 个字节是格式说明。
+		if len(b) < 4 || b[3] != ':' {
+			return nil, fmt.Errorf("%v: invalid verbatim string %q", ErrProtocol, b)
+		}
+
+		return b[4:], nil
+
+	case replyBigNumber:
+		n, ok := new(big.Int).SetString(string(line[1:]), 10)
+
+		if !ok {
+			return nil, fmt.Errorf("%v: invalid big number %q", ErrProtocol, line[1:])
+		}
+
+		return n, nil
+
+	case replyMap:
+		n, err := parseLen(line[1:])
+
+		if err != nil || n < 0 {
+			return nil, err
+		}
+
+		arr, err := r.readArray(2 * n)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return Map(arr), nil
+
+	case replyPush:
+		n, err := parseLen(line[1:])
+
+		if err != nil || n < 0 {
+			return nil, err
+		}
+
+		arr, err := r.readArray(n)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return Push(arr), nil
+
+	case replyAttribute:
+		n, err := parseLen(line[1:])
+
+		if err != nil {
+			return nil, err
+		}
+
+		// attribute 只是应答的附加信息，读出来丢弃之后继续读真正的应答。
+		if _, err := r.readArray(2 * n); err != nil {
+			return nil, err
+		}
+
+		return r.readReply()
+	}
+
+	return nil, fmt.Errorf("%v: unknown reply type %q", ErrProtocol, line[0])
+}
+
+func (r *Reader) readBulk(n int) ([]byte, error) {
+	b := make([]byte, n+2)
+
+	if _, err := io.ReadFull(r.r, b); err != nil {
+		return nil, err
+	}
+
+	return b[:n], nil
+}
+
+func (r *Reader) readArray(n int) ([]interface{}, error) {
+	arr := make([]interface{}, 0, n)
+
+	for i := 0; i < n; i++ {
+		v, err := r.readReply()
+
+		if err != nil {
+			return nil, err
+		}
+
+		arr = append(arr, v)
+	}
+
+	return arr, nil
+}
+
+func (r *Reader) readLine() ([]byte, error) {
+	line, isPrefix, err := r.r.ReadLine()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if isPrefix {
+		return nil, fmt.Errorf("%v: reply line is too long", ErrProtocol)
+	}
+
+	if len(line) == 0 {
+		return nil, fmt.Errorf("%v: empty reply line", ErrProtocol)
+	}
+
+	return line, nil
+}
+
+func parseInt(b []byte) (int64, error) {
+	n, err := strconv.ParseInt(string(b), 10, 64)
+
+	if err != nil {
+		return 0, fmt.Errorf("%v: invalid integer %q", ErrProtocol, b)
+	}
+
+	return n, nil
+}
+
+func parseDouble(b []byte) (float64, error) {
+	// strconv.ParseFloat 可以直接解析 RESP3 中的 inf、-inf 和 nan。
+	f, err := strconv.ParseFloat(string(b), 64)
+
+	if err != nil {
+		return 0, fmt.Errorf("%v: invalid double %q", ErrProtocol, b)
+	}
+
+	return f, nil
+}
+
+func parseBool(b []byte) (bool, error) {
+	switch string(b) {
+	case "t":
+		return true, nil
+	case "f":
+		return false, nil
+	}
+
+	return false, fmt.Errorf("%v: invalid boolean %q", ErrProtocol, b)
+}
+
+func parseLen(b []byte) (int, error) {
+	n, err := parseInt(b)
+	return int(n), err
+}
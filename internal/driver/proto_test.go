@@ -0,0 +1,173 @@
+package driver
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"math"
+	"math/big"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/huandu/go-assert"
+)
+
+func TestWriteArgs(t *testing.T) {
+	a := assert.New(t)
+	buf := &bytes.Buffer{}
+	w := NewWriter(bufio.NewWriter(buf))
+
+	a.NilError(w.WriteArgs([]interface{}{"SET", []byte("key"), 123, int64(-4), 1.5, true, time.Duration(10), nil}))
+	a.NilError(w.Flush())
+	a.Equal(buf.String(), "*8\r\n"+
+		"$3\r\nSET\r\n"+
+		"$3\r\nkey\r\n"+
+		"$3\r\n123\r\n"+
+		"$2\r\n-4\r\n"+
+		"$3\r\n1.5\r\n"+
+		"$1\r\n1\r\n"+
+		"$2\r\n10\r\n"+
+		"$0\r\n\r\n")
+
+	a.Assert(w.WriteArgs([]interface{}{"SET", struct{}{}}) != nil)
+}
+
+func TestReadReply(t *testing.T) {
+	a := assert.New(t)
+	r := NewReader(bufio.NewReader(strings.NewReader("" +
+		"+OK\r\n" +
+		":42\r\n" +
+		"$5\r\nhello\r\n" +
+		"$-1\r\n" +
+		"*3\r\n$1\r\na\r\n*-1\r\n-ERR nested\r\n" +
+		"-WRONGTYPE Operation against a key holding the wrong kind of value\r\n" +
+		"?\r\n")))
+
+	reply, err := r.ReadReply()
+	a.NilError(err)
+	a.Equal(reply, Status("OK"))
+
+	reply, err = r.ReadReply()
+	a.NilError(err)
+	a.Equal(reply, int64(42))
+
+	reply, err = r.ReadReply()
+	a.NilError(err)
+	a.Equal(reply, []byte("hello"))
+
+	reply, err = r.ReadReply()
+	a.NilError(err)
+	a.Equal(reply, nil)
+
+	reply, err = r.ReadReply()
+	a.NilError(err)
+	a.Equal(reply, []interface{}{[]byte("a"), nil, Error("ERR nested")})
+
+	_, err = r.ReadReply()
+	a.Equal(err, Error("WRONGTYPE Operation against a key holding the wrong kind of value"))
+
+	_, err = r.ReadReply()
+	a.Assert(err != nil)
+	a.Assert(strings.Contains(err.Error(), ErrProtocol.Error()))
+}
+
+func TestReadReplyRESP3(t *testing.T) {
+	a := assert.New(t)
+	r := NewReader(bufio.NewReader(strings.NewReader("" +
+		"_\r\n" +
+		",1.5\r\n" +
+		",inf\r\n" +
+		"#t\r\n" +
+		"(3492890328409238509324850943850943825024385\r\n" +
+		"=15\r\ntxt:Some string\r\n" +
+		"%2\r\n+first\r\n:1\r\n$6\r\nsecond\r\n#f\r\n" +
+		"~2\r\n:1\r\n:2\r\n" +
+		"|1\r\n+ttl\r\n:3600\r\n:42\r\n" +
+		">3\r\n$7\r\nmessage\r\n$2\r\nch\r\n$5\r\nhello\r\n" +
+		"!21\r\nSYNTAX invalid syntax\r\n")))
+
+	reply, err := r.ReadReply()
+	a.NilError(err)
+	a.Equal(reply, nil)
+
+	reply, err = r.ReadReply()
+	a.NilError(err)
+	a.Equal(reply, 1.5)
+
+	reply, err = r.ReadReply()
+	a.NilError(err)
+	a.Equal(reply, math.Inf(1))
+
+	reply, err = r.ReadReply()
+	a.NilError(err)
+	a.Equal(reply, true)
+
+	reply, err = r.ReadReply()
+	a.NilError(err)
+	n, _ := new(big.Int).SetString("3492890328409238509324850943850943825024385", 10)
+	a.Equal(reply, n)
+
+	reply, err = r.ReadReply()
+	a.NilError(err)
+	a.Equal(reply, []byte("Some string"))
+
+	reply, err = r.ReadReply()
+	a.NilError(err)
+	a.Equal(reply, Map{Status("first"), int64(1), []byte("second"), false})
+
+	reply, err = r.ReadReply()
+	a.NilError(err)
+	a.Equal(reply, []interface{}{int64(1), int64(2)})
+
+	// attribute 会被跳过，直接返回后面的应答。
+	reply, err = r.ReadReply()
+	a.NilError(err)
+	a.Equal(reply, int64(42))
+
+	reply, err = r.ReadReply()
+	a.NilError(err)
+	a.Equal(reply, Push{[]byte("message"), []byte("ch"), []byte("hello")})
+	a.Equal(parseMessage(reply), &Message{Channel: "ch", Payload: "hello"})
+
+	_, err = r.ReadReply()
+	a.Equal(err, Error("SYNTAX invalid syntax"))
+}
+
+func TestReadTruncatedReply(t *testing.T) {
+	a := assert.New(t)
+
+	for _, s := range []string{"%2\r\n+first\r\n:1\r\n$6\r\nsec", ">2\r\n$10\r\ninvali"} {
+		reply, err := NewReader(bufio.NewReader(strings.NewReader(s))).ReadReply()
+		a.Assert(err != nil)
+		a.Equal(reply, nil)
+	}
+
+	// blob error 和 verbatim string 的长度不能是负数。
+	for _, s := range []string{"!-5\r\n", "=-5\r\n"} {
+		reply, err := NewReader(bufio.NewReader(strings.NewReader(s))).ReadReply()
+		a.Assert(err != nil)
+		a.Equal(reply, nil)
+	}
+
+	// 读取 push 消息失败时，连接不能把不完整的消息交给 OnPush，也不能继续读取。
+	client, server := net.Pipe()
+	pushed := false
+	cn := newConn(client, &Options{
+		OnPush: func(cn *Conn, push Push) {
+			pushed = true
+		},
+	})
+
+	go func() {
+		io.ReadFull(server, make([]byte, len("*1\r\n$4\r\nPING\r\n")))
+		server.Write([]byte(">2\r\n$10\r\ninvali"))
+		server.Close()
+	}()
+
+	err := cn.Process(NewCmd("PING"))
+	a.Assert(err != nil)
+	a.Assert(!pushed)
+	a.Assert(cn.isBroken())
+}
@@ -0,0 +1,346 @@
+package driver
+
+import (
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	// pubSubPingInterval 是订阅连接空闲时发送 PING 检查连接状态的周期。
+	pubSubPingInterval = 30 * time.Second
+
+	// pubSubReconnectBackoff 是订阅连接重连失败后的等待时间。
+	pubSubReconnectBackoff = 100 * time.Millisecond
+)
+
+// Message 代表一条通过 pub/sub 收到的消息。
+type Message struct {
+	Channel string // Channel 是消息发布的 channel。
+	Pattern string // Pattern 是匹配上 Channel 的模式，只有通过 PSUBSCRIBE 收到的消息才会设置。
+	Payload string // Payload 是消息内容。
+}
+
+// PubSub 代表一个 pub/sub 订阅，独占一个连接。
+// 连接断开之后会自动重连，并重新订阅所有 channel 和 pattern。
+type PubSub struct {
+	newConn func() (*Conn, error)
+
+	mu       sync.Mutex
+	cn       *Conn
+	channels map[string]struct{}
+	patterns map[string]struct{}
+	closed   bool
+	exit     chan struct{}
+
+	once  sync.Once
+	msgCh chan *Message
+}
+
+func newPubSub(newConn func() (*Conn, error)) *PubSub {
+	return &PubSub{
+		newConn:  newConn,
+		channels: map[string]struct{}{},
+		patterns: map[string]struct{}{},
+		exit:     make(chan struct{}),
+	}
+}
+
+// Subscribe 订阅 channels。
+func (ps *PubSub) Subscribe(channels ...string) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	for _, ch := range channels {
+		ps.channels[ch] = struct{}{}
+	}
+
+	return ps.write("SUBSCRIBE", channels)
+}
+
+// PSubscribe 订阅 patterns。
+func (ps *PubSub) PSubscribe(patterns ...string) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	for _, p := range patterns {
+		ps.patterns[p] = struct{}{}
+	}
+
+	return ps.write("PSUBSCRIBE", patterns)
+}
+
+// Unsubscribe 取消订阅 channels，如果 channels 为空则取消订阅所有 channel。
+func (ps *PubSub) Unsubscribe(channels ...string) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	if len(channels) == 0 {
+		ps.channels = map[string]struct{}{}
+	}
+
+	for _, ch := range channels {
+		delete(ps.channels, ch)
+	}
+
+	return ps.write("UNSUBSCRIBE", channels)
+}
+
+// PUnsubscribe 取消订阅 patterns，如果 patterns 为空则取消订阅所有 pattern。
+func (ps *PubSub) PUnsubscribe(patterns ...string) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	if len(patterns) == 0 {
+		ps.patterns = map[string]struct{}{}
+	}
+
+	for _, p := range patterns {
+		delete(ps.patterns, p)
+	}
+
+	return ps.write("PUNSUBSCRIBE", patterns)
+}
+
+// Channel 返回接收消息的 channel，size 是 channel 的缓冲区大小。
+// 第一次调用时会启动接收消息的 goroutine，之后的调用返回同一个 channel。
+// PubSub 关闭之后这个 channel 也会被关闭。
+func (ps *PubSub) Channel(size int) <-chan *Message {
+	ps.once.Do(func() {
+		ps.msgCh = make(chan *Message, size)
+		go ps.receive()
+	})
+	return ps.msgCh
+}
+
+// Close 关闭订阅和连接，重复调用是安全的。
+func (ps *PubSub) Close() error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	if ps.closed {
+		return nil
+	}
+
+	ps.closed = true
+	close(ps.exit)
+
+	if ps.cn != nil {
+		err := ps.cn.Close()
+		ps.cn = nil
+		return err
+	}
+
+	return nil
+}
+
+// write 在订阅连接上发送一个命令，应答会在 receive 中读取，调用者需要持有锁。
+func (ps *PubSub) write(name string, names []string) error {
+	if ps.closed {
+		return ErrClosed
+	}
+
+	// 连接刚建立时会重新订阅所有 channel 和 pattern，不需要再发一次。
+	cn, fresh, err := ps.conn()
+
+	if err != nil || fresh {
+		return err
+	}
+
+	args := make([]interface{}, 0, len(names)+1)
+	args = append(args, name)
+
+	for _, n := range names {
+		args = append(args, n)
+	}
+
+	if err := cn.writeCmds(NewCmd(args...)); err != nil {
+		ps.releaseConn(cn)
+		return err
+	}
+
+	return nil
+}
+
+// conn 返回当前的订阅连接，如果连接不存在则新建连接并重新订阅，调用者需要持有锁。
+func (ps *PubSub) conn() (cn *Conn, fresh bool, err error) {
+	if ps.cn != nil {
+		return ps.cn, false, nil
+	}
+
+	cn, err = ps.newConn()
+
+	if err != nil {
+		return
+	}
+
+	// 使用 RESP3 时订阅消息是 push 消息，需要作为应答交给 receive 解析。
+	cn.readPush = true
+
+	var cmds []Cmder
+
+	if len(ps.channels) != 0 {
+		cmds = append(cmds, NewCmd(subscribeArgs("SUBSCRIBE", ps.channels)...))
+	}
+
+	if len(ps.patterns) != 0 {
+		cmds = append(cmds, NewCmd(subscribeArgs("PSUBSCRIBE", ps.patterns)...))
+	}
+
+	if len(cmds) != 0 {
+		if err = cn.writeCmds(cmds...); err != nil {
+			cn.Close()
+			return
+		}
+	}
+
+	ps.cn = cn
+	fresh = true
+	return
+}
+
+// releaseConn 关闭一个出错的连接，下次使用时会重连，调用者需要持有锁。
+func (ps *PubSub) releaseConn(cn *Conn) {
+	if ps.cn == cn {
+		ps.cn = nil
+	}
+
+	cn.Close()
+}
+
+func (ps *PubSub) receive() {
+	defer close(ps.msgCh)
+
+	readCmd := &Cmd{
+		readTimeout:    pubSubPingInterval,
+		hasReadTimeout: true,
+	}
+	pinged := false
+
+	for {
+		ps.mu.Lock()
+
+		if ps.closed {
+			ps.mu.Unlock()
+			return
+		}
+
+		cn, _, err := ps.conn()
+		ps.mu.Unlock()
+
+		if err != nil {
+			select {
+			case <-ps.exit:
+				return
+			case <-time.After(pubSubReconnectBackoff):
+			}
+
+			continue
+		}
+
+		reply, err := cn.read(readCmd)
+
+		if err != nil {
+			if e, ok := err.(net.Error); ok && e.Timeout() && !pinged {
+				// 连接长时间没有消息，通过 PING 检查连接是否还可用。
+				ps.mu.Lock()
+				err = cn.writeCmds(NewCmd("PING"))
+				ps.mu.Unlock()
+
+				if err == nil {
+					pinged = true
+					continue
+				}
+			}
+
+			if _, ok := err.(Error); ok {
+				continue
+			}
+
+			ps.mu.Lock()
+			ps.releaseConn(cn)
+			ps.mu.Unlock()
+			pinged = false
+			continue
+		}
+
+		pinged = false
+		msg := parseMessage(reply)
+
+		if msg == nil {
+			continue
+		}
+
+		select {
+		case ps.msgCh <- msg:
+		case <-ps.exit:
+			return
+		}
+	}
+}
+
+// parseMessage 解析订阅连接上收到的应答，如果不是消息则返回 nil。
+func parseMessage(reply interface{}) *Message {
+	var arr []interface{}
+
+	switch v := reply.(type) {
+	case []interface{}:
+		arr = v
+	case Push:
+		arr = v
+	}
+
+	if len(arr) == 0 {
+		return nil
+	}
+
+	kind, _ := arr[0].([]byte)
+
+	switch string(kind) {
+	case "message":
+		if len(arr) != 3 {
+			return nil
+		}
+
+		return &Message{
+			Channel: replyString(arr[1]),
+			Payload: replyString(arr[2]),
+		}
+
+	case "pmessage":
+		if len(arr) != 4 {
+			return nil
+		}
+
+		return &Message{
+			Pattern: replyString(arr[1]),
+			Channel: replyString(arr[2]),
+			Payload: replyString(arr[3]),
+		}
+	}
+
+	return nil
+}
+
+func subscribeArgs(name string, names map[string]struct{}) []interface{} {
+	args := make([]interface{}, 0, len(names)+1)
+	args = append(args, name)
+
+	for n := range names {
+		args = append(args, n)
+	}
+
+	return args
+}
+
+// replyString 将 bulk string 或状态应答转成字符串。
+func replyString(reply interface{}) string {
+	switch v := reply.(type) {
+	case []byte:
+		return string(v)
+	case Status:
+		return string(v)
+	}
+
+	return ""
+}
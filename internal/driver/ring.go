@@ -0,0 +1,476 @@
+package driver
+
+import (
+	"crypto/md5"
+	"encoding/binary"
+	"errors"
+	"math/rand"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// ringReplicas 是权重为 1 的 shard 在哈希环上的虚拟结点数量。
+	ringReplicas = 160
+
+	// ringFailureThreshold 是 shard 被认为下线之前连续心跳失败的次数。
+	ringFailureThreshold = 3
+
+	defaultHeartbeatFrequency = 500 * time.Millisecond
+)
+
+var (
+	// ErrNoRingShards 代表当前没有任何可用的 shard。
+	ErrNoRingShards = errors.New("go-redis/driver: no ring shards available")
+
+	// ErrCrossShard 代表 Watch 的 keys 不在同一个 shard 上。
+	ErrCrossShard = errors.New("go-redis/driver: keys must be in the same shard")
+)
+
+// RingOptions 代表通过一致性哈希连接一组独立 Redis 服务器的配置。
+type RingOptions struct {
+	// Addrs 是每个 shard 的名字和地址，一致性哈希使用的是 shard 的名字，
+	// 因此修改某个 shard 的地址不会影响 key 的分布。
+	Addrs map[string]string
+
+	// Weights 是每个 shard 的权重，默认是 1，权重越大分到的 key 越多。
+	Weights map[string]int
+
+	Password string // Password 是连接每个 shard 的密码。
+	DB       int    // DB 是连接后默认选择的数据库。
+
+	// OnConnect 在每个 shard 的新连接建立之后调用。
+	OnConnect func(cn *Conn) error
+
+	// Protocol 是使用的 RESP 协议版本，默认是 3，服务器不支持时自动退回 RESP2。
+	Protocol int
+
+	// OnPush 在每个 shard 的普通连接上收到 RESP3 push 消息时调用。
+	OnPush func(cn *Conn, push Push)
+
+	// OnInvalidate 不为 nil 时为每个 shard 的连接开启 CLIENT TRACKING，详见 Options.OnInvalidate。
+	OnInvalidate func(keys []string)
+
+	// HeartbeatFrequency 是检查 shard 是否可用的周期，默认是 500ms。
+	// 连续 3 次检查失败的 shard 会暂时从哈希环上摘除，直到检查成功后再加回来。
+	HeartbeatFrequency time.Duration
+
+	DialTimeout  time.Duration // DialTimeout 是连接超时。
+	ReadTimeout  time.Duration // ReadTimeout 是读超时。
+	WriteTimeout time.Duration // WriteTimeout 是写超时。
+
+	PoolSize           int           // PoolSize 是每个 shard 的连接池最大连接数。
+	PoolTimeout        time.Duration // PoolTimeout 是连接池满时等待空闲连接的时间。
+	IdleTimeout        time.Duration // IdleTimeout 是空闲连接被关闭前的最长空闲时间。
+	IdleCheckFrequency time.Duration // IdleCheckFrequency 是检查空闲连接的周期。
+}
+
+func (opt *RingOptions) init() {
+	if opt.HeartbeatFrequency <= 0 {
+		opt.HeartbeatFrequency = defaultHeartbeatFrequency
+	}
+}
+
+func (opt *RingOptions) shardOptions(addr string) *Options {
+	return &Options{
+		Addr:      addr,
+		Password:  opt.Password,
+		DB:        opt.DB,
+		OnConnect: opt.OnConnect,
+		Protocol:  opt.Protocol,
+		OnPush:    opt.OnPush,
+
+		OnInvalidate: opt.OnInvalidate,
+
+		DialTimeout:  opt.DialTimeout,
+		ReadTimeout:  opt.ReadTimeout,
+		WriteTimeout: opt.WriteTimeout,
+
+		PoolSize:           opt.PoolSize,
+		PoolTimeout:        opt.PoolTimeout,
+		IdleTimeout:        opt.IdleTimeout,
+		IdleCheckFrequency: opt.IdleCheckFrequency,
+	}
+}
+
+// ringShard 代表哈希环上的一个 shard。
+type ringShard struct {
+	name     string
+	weight   int
+	client   *SingleClient
+	failures int32 // failures 是连续心跳失败的次数，需要原子操作。
+}
+
+func (shard *ringShard) isUp() bool {
+	return atomic.LoadInt32(&shard.failures) < ringFailureThreshold
+}
+
+// hashRing 是由可用 shard 组成的一致性哈希环。
+type hashRing struct {
+	hashes []uint32     // hashes 是所有虚拟结点的哈希值，从小到大排列。
+	shards []*ringShard // shards 是每个虚拟结点对应的 shard。
+	live   []*ringShard // live 是所有可用的 shard，按名字排列。
+}
+
+func newHashRing(shards []*ringShard) *hashRing {
+	ring := &hashRing{}
+
+	for _, shard := range shards {
+		if !shard.isUp() {
+			continue
+		}
+
+		ring.live = append(ring.live, shard)
+
+		for i := 0; i < shard.weight*ringReplicas; i++ {
+			ring.hashes = append(ring.hashes, ringHash(shard.name+"-"+strconv.Itoa(i)))
+			ring.shards = append(ring.shards, shard)
+		}
+	}
+
+	sort.Sort(ring)
+	return ring
+}
+
+func (ring *hashRing) Len() int           { return len(ring.hashes) }
+func (ring *hashRing) Less(i, j int) bool { return ring.hashes[i] < ring.hashes[j] }
+func (ring *hashRing) Swap(i, j int) {
+	ring.hashes[i], ring.hashes[j] = ring.hashes[j], ring.hashes[i]
+	ring.shards[i], ring.shards[j] = ring.shards[j], ring.shards[i]
+}
+
+// get 返回负责 key 的 shard，如果 key 中包含非空的 {hashtag}，只用 hashtag 计算哈希值。
+func (ring *hashRing) get(key string) *ringShard {
+	if len(ring.hashes) == 0 {
+		return nil
+	}
+
+	h := ringHash(HashTag(key))
+	idx := sort.Search(len(ring.hashes), func(i int) bool {
+		return ring.hashes[i] >= h
+	})
+
+	if idx == len(ring.hashes) {
+		idx = 0
+	}
+
+	return ring.shards[idx]
+}
+
+func ringHash(key string) uint32 {
+	sum := md5.Sum([]byte(key))
+	return binary.LittleEndian.Uint32(sum[:4])
+}
+
+// RingClient 代表通过一致性哈希连接一组独立 Redis 服务器的客户端，它是并发安全的。
+//
+// RingClient 根据 key 的哈希值将命令发送到对应的 shard，多个 key 的命令只会按照第一个 key 选择 shard，
+// 没有 key 的命令会发送到任意一个可用的 shard。
+// PUBLISH 和订阅总是使用名字最小的可用 shard，保证发布和订阅在同一个服务器上。
+//
+// RingClient 在后台定期检查每个 shard 是否可用，不可用的 shard 会暂时从哈希环上摘除，
+// 它负责的 key 会被分配到其他 shard 上，因此 RingClient 只适合用于缓存等可以容忍数据丢失的场景。
+type RingClient struct {
+	opt    *RingOptions
+	shards []*ringShard // shards 是所有 shard，按名字排列。
+
+	ring     atomic.Value // ring 保存当前的 *hashRing。
+	mu       sync.Mutex
+	closed   bool
+	closedCh chan struct{}
+}
+
+var _ Client = new(RingClient)
+
+// NewRingClient 创建一个通过一致性哈希连接多个 Redis 服务器的客户端。
+func NewRingClient(opt *RingOptions) *RingClient {
+	cloned := *opt
+	cloned.init()
+
+	c := &RingClient{
+		opt:      &cloned,
+		closedCh: make(chan struct{}),
+	}
+
+	for name, addr := range cloned.Addrs {
+		weight := cloned.Weights[name]
+
+		if weight <= 0 {
+			weight = 1
+		}
+
+		c.shards = append(c.shards, &ringShard{
+			name:   name,
+			weight: weight,
+			client: NewSingleClient(cloned.shardOptions(addr)),
+		})
+	}
+
+	sort.Slice(c.shards, func(i, j int) bool {
+		return c.shards[i].name < c.shards[j].name
+	})
+
+	c.ring.Store(newHashRing(c.shards))
+	go c.heartbeat()
+	return c
+}
+
+// Process 将命令发送到 key 所在的 shard 执行。
+func (c *RingClient) Process(cmd Cmder) error {
+	shard, err := c.cmdShard(cmd)
+
+	if err != nil {
+		cmd.SetErr(err)
+		return err
+	}
+
+	return shard.client.Process(cmd)
+}
+
+// ProcessPipeline 将命令按照 shard 分组，并发地在每个 shard 上通过 pipeline 执行。
+func (c *RingClient) ProcessPipeline(cmds []Cmder) error {
+	if len(cmds) == 0 {
+		return nil
+	}
+
+	groups, err := c.groupCmds(cmds)
+
+	if err != nil {
+		setCmdsErr(cmds, err)
+		return err
+	}
+
+	var wg sync.WaitGroup
+
+	for shard, group := range groups {
+		wg.Add(1)
+		go func(shard *ringShard, group []Cmder) {
+			defer wg.Done()
+			shard.client.ProcessPipeline(group)
+		}(shard, group)
+	}
+
+	wg.Wait()
+	return cmdsFirstErr(cmds)
+}
+
+// ProcessTxPipeline 将命令按照 shard 分组，在每个 shard 上用 MULTI/EXEC 执行。
+// 事务中的所有 key 应该在同一个 shard 上，否则事务只在每个 shard 内有原子性。
+func (c *RingClient) ProcessTxPipeline(cmds []Cmder) error {
+	if len(cmds) == 0 {
+		return nil
+	}
+
+	groups, err := c.groupCmds(cmds)
+
+	if err != nil {
+		setCmdsErr(cmds, err)
+		return err
+	}
+
+	var firstErr error
+
+	for shard, group := range groups {
+		if err := shard.client.ProcessTxPipeline(group); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+// Watch 在 keys 所在的 shard 上执行 WATCH 并调用 fn，所有 keys 必须在同一个 shard 上。
+func (c *RingClient) Watch(fn func(tx Client) error, keys ...string) error {
+	var shard *ringShard
+
+	for _, key := range keys {
+		s, err := c.keyShard(key)
+
+		if err != nil {
+			return err
+		}
+
+		if shard != nil && s != shard {
+			return ErrCrossShard
+		}
+
+		shard = s
+	}
+
+	if shard == nil {
+		s, err := c.randomShard()
+
+		if err != nil {
+			return err
+		}
+
+		shard = s
+	}
+
+	return shard.client.Watch(fn, keys...)
+}
+
+// PubSub 创建一个新的订阅，订阅连接总是建立在名字最小的可用 shard 上。
+func (c *RingClient) PubSub() *PubSub {
+	return newPubSub(func() (*Conn, error) {
+		shard, err := c.pubSubShard()
+
+		if err != nil {
+			return nil, err
+		}
+
+		return shard.client.pool.NewConn()
+	})
+}
+
+// ForEachMaster 依次对每个可用的 shard 调用 fn。
+func (c *RingClient) ForEachMaster(fn func(client Client) error) error {
+	for _, shard := range c.hashRing().live {
+		if err := fn(shard.client); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Close 关闭所有 shard 的连接。
+func (c *RingClient) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return ErrClosed
+	}
+
+	c.closed = true
+	close(c.closedCh)
+	var firstErr error
+
+	for _, shard := range c.shards {
+		if err := shard.client.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+func (c *RingClient) hashRing() *hashRing {
+	return c.ring.Load().(*hashRing)
+}
+
+func (c *RingClient) cmdShard(cmd Cmder) (*ringShard, error) {
+	switch cmdName(cmd) {
+	case "publish", "pubsub":
+		return c.pubSubShard()
+	}
+
+	if key, ok := cmdFirstKey(cmd); ok {
+		return c.keyShard(key)
+	}
+
+	return c.randomShard()
+}
+
+func (c *RingClient) keyShard(key string) (*ringShard, error) {
+	shard := c.hashRing().get(key)
+
+	if shard == nil {
+		return nil, ErrNoRingShards
+	}
+
+	return shard, nil
+}
+
+func (c *RingClient) randomShard() (*ringShard, error) {
+	live := c.hashRing().live
+
+	if len(live) == 0 {
+		return nil, ErrNoRingShards
+	}
+
+	return live[rand.Intn(len(live))], nil
+}
+
+// pubSubShard 返回名字最小的可用 shard，用于 PUBLISH 和订阅。
+func (c *RingClient) pubSubShard() (*ringShard, error) {
+	live := c.hashRing().live
+
+	if len(live) == 0 {
+		return nil, ErrNoRingShards
+	}
+
+	return live[0], nil
+}
+
+func (c *RingClient) groupCmds(cmds []Cmder) (map[*ringShard][]Cmder, error) {
+	groups := map[*ringShard][]Cmder{}
+
+	for _, cmd := range cmds {
+		shard, err := c.cmdShard(cmd)
+
+		if err != nil {
+			return nil, err
+		}
+
+		groups[shard] = append(groups[shard], cmd)
+	}
+
+	return groups, nil
+}
+
+// heartbeat 定期 PING 每个 shard，shard 的可用状态变化时重建哈希环。
+func (c *RingClient) heartbeat() {
+	ticker := time.NewTicker(c.opt.HeartbeatFrequency)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			var wg sync.WaitGroup
+			var changed int32
+
+			// 并发检查所有 shard，避免无法连接的 shard 拖慢其他 shard 的检查。
+			for _, shard := range c.shards {
+				wg.Add(1)
+				go func(shard *ringShard) {
+					defer wg.Done()
+
+					if c.check(shard) {
+						atomic.StoreInt32(&changed, 1)
+					}
+				}(shard)
+			}
+
+			wg.Wait()
+
+			if changed != 0 {
+				c.ring.Store(newHashRing(c.shards))
+			}
+
+		case <-c.closedCh:
+			return
+		}
+	}
+}
+
+// check PING 一次 shard，返回 shard 的可用状态是否发生了变化。
+func (c *RingClient) check(shard *ringShard) (changed bool) {
+	up := shard.isUp()
+
+	if err := shard.client.Process(NewCmd("PING")); err != nil {
+		if !up {
+			return false
+		}
+
+		atomic.AddInt32(&shard.failures, 1)
+	} else {
+		atomic.StoreInt32(&shard.failures, 0)
+	}
+
+	return up != shard.isUp()
+}
@@ -0,0 +1,45 @@
+package driver
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+func TestHashRing(t *testing.T) {
+	a := assert.New(t)
+	shards := []*ringShard{
+		{name: "a", weight: 1},
+		{name: "b", weight: 1},
+		{name: "c", weight: 2},
+	}
+	ring := newHashRing(shards)
+	counts := map[string]int{}
+
+	for i := 0; i < 10000; i++ {
+		counts[ring.get(fmt.Sprintf("key:%v", i)).name]++
+	}
+
+	// 权重为 2 的 shard 大约分到一半的 key。
+	a.Assert(counts["a"] > 2000 && counts["a"] < 3000)
+	a.Assert(counts["b"] > 2000 && counts["b"] < 3000)
+	a.Assert(counts["c"] > 4500 && counts["c"] < 5500)
+	a.Equal(ring.get("{user1000}.following"), ring.get("{user1000}.followers"))
+	a.Equal(ring.get("{user1000}.following"), ring.get("user1000"))
+
+	// 摘除一个 shard 之后，只有这个 shard 上的 key 会被重新分配。
+	shards[2].failures = ringFailureThreshold
+	removed := newHashRing(shards)
+	a.Equal(len(removed.live), 2)
+
+	for i := 0; i < 10000; i++ {
+		key := fmt.Sprintf("key:%v", i)
+
+		if shard := ring.get(key); shard.name != "c" {
+			a.Equal(removed.get(key), shard)
+		}
+	}
+
+	a.Assert(newHashRing(nil).get("key") == nil)
+}
@@ -0,0 +1,251 @@
+package driver
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNoMaster 代表所有哨兵都无法提供 master 结点地址。
+	ErrNoMaster = errors.New("go-redis/driver: all sentinels are unreachable or master is unknown")
+)
+
+// FailoverOptions 代表通过哨兵连接 Redis master 的配置。
+type FailoverOptions struct {
+	MasterName    string   // MasterName 是哨兵中配置的 master 名字。
+	SentinelAddrs []string // SentinelAddrs 是哨兵地址。
+
+	Password string // Password 是连接 master 的密码。
+	DB       int    // DB 是连接后默认选择的数据库。
+
+	// OnConnect 在 master 的新连接建立之后调用。
+	OnConnect func(cn *Conn) error
+
+	// Protocol 是连接 master 使用的 RESP 协议版本，默认是 3，服务器不支持时自动退回 RESP2。
+	Protocol int
+
+	// OnPush 在 master 的普通连接上收到 RESP3 push 消息时调用。
+	OnPush func(cn *Conn, push Push)
+
+	// OnInvalidate 不为 nil 时为 master 的连接开启 CLIENT TRACKING，详见 Options.OnInvalidate。
+	OnInvalidate func(keys []string)
+
+	DialTimeout  time.Duration // DialTimeout 是连接超时。
+	ReadTimeout  time.Duration // ReadTimeout 是读超时。
+	WriteTimeout time.Duration // WriteTimeout 是写超时。
+
+	PoolSize           int           // PoolSize 是连接池最大连接数。
+	PoolTimeout        time.Duration // PoolTimeout 是连接池满时等待空闲连接的时间。
+	IdleTimeout        time.Duration // IdleTimeout 是空闲连接被关闭前的最长空闲时间。
+	IdleCheckFrequency time.Duration // IdleCheckFrequency 是检查空闲连接的周期。
+}
+
+// NewFailoverClient 创建一个通过哨兵发现 master 的客户端。
+// 客户端会订阅哨兵的 +switch-master 消息，master 切换后会关闭所有旧连接。
+func NewFailoverClient(opt *FailoverOptions) *SingleClient {
+	s := &sentinel{
+		masterName: opt.MasterName,
+		addrs:      append([]string(nil), opt.SentinelAddrs...),
+		opt: &Options{
+			DialTimeout:  opt.DialTimeout,
+			ReadTimeout:  opt.ReadTimeout,
+			WriteTimeout: opt.WriteTimeout,
+			PoolSize:     1,
+		},
+	}
+
+	nodeOpt := &Options{
+		Addr:      "sentinel:" + opt.MasterName,
+		Password:  opt.Password,
+		DB:        opt.DB,
+		OnConnect: opt.OnConnect,
+		Protocol:  opt.Protocol,
+		OnPush:    opt.OnPush,
+
+		OnInvalidate: opt.OnInvalidate,
+
+		DialTimeout:  opt.DialTimeout,
+		ReadTimeout:  opt.ReadTimeout,
+		WriteTimeout: opt.WriteTimeout,
+
+		PoolSize:           opt.PoolSize,
+		PoolTimeout:        opt.PoolTimeout,
+		IdleTimeout:        opt.IdleTimeout,
+		IdleCheckFrequency: opt.IdleCheckFrequency,
+	}
+	dialTimeout := opt.DialTimeout
+
+	if dialTimeout == 0 {
+		dialTimeout = defaultDialTimeout
+	}
+
+	nodeOpt.Dialer = func() (net.Conn, error) {
+		addr, err := s.masterAddr()
+
+		if err != nil {
+			return nil, err
+		}
+
+		conn, err := net.DialTimeout("tcp", addr, dialTimeout)
+
+		if err != nil {
+			// master 可能已经下线，下次重新向哨兵询问地址。
+			s.reset(addr)
+			return nil, err
+		}
+
+		return conn, nil
+	}
+
+	c := NewSingleClient(nodeOpt)
+	s.onSwitch = func(addr string) {
+		c.pool.Filter(func(cn *Conn) bool {
+			return cn.RemoteAddr().String() != addr
+		})
+	}
+	c.onClose = s.close
+	return c
+}
+
+// sentinel 负责通过哨兵查询 master 地址，并监听 master 切换。
+type sentinel struct {
+	masterName string
+	opt        *Options
+	onSwitch   func(addr string)
+
+	mu     sync.Mutex
+	addrs  []string
+	master string
+	client *SingleClient
+	pubsub *PubSub
+	closed bool
+}
+
+// masterAddr 返回当前 master 地址，如果还不知道则依次询问每个哨兵。
+func (s *sentinel) masterAddr() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return "", ErrClosed
+	}
+
+	if s.master != "" {
+		return s.master, nil
+	}
+
+	if s.client != nil {
+		if addr, err := s.queryMaster(s.client); err == nil {
+			s.master = addr
+			return addr, nil
+		}
+
+		s.closeSentinel()
+	}
+
+	for i, sentinelAddr := range s.addrs {
+		opt := s.opt.clone()
+		opt.Addr = sentinelAddr
+		client := NewSingleClient(opt)
+		addr, err := s.queryMaster(client)
+
+		if err != nil {
+			client.Close()
+			continue
+		}
+
+		// 把可用的哨兵放在最前面，下次优先使用。
+		s.addrs[0], s.addrs[i] = s.addrs[i], s.addrs[0]
+		s.master = addr
+		s.client = client
+		s.watch(client)
+		return addr, nil
+	}
+
+	return "", ErrNoMaster
+}
+
+func (s *sentinel) queryMaster(client *SingleClient) (string, error) {
+	cmd := NewCmd("SENTINEL", "get-master-addr-by-name", s.masterName)
+
+	if err := client.Process(cmd); err != nil {
+		return "", err
+	}
+
+	fields, ok := cmd.Reply().([]interface{})
+
+	if !ok || len(fields) != 2 {
+		return "", ErrNoMaster
+	}
+
+	return net.JoinHostPort(replyString(fields[0]), replyString(fields[1])), nil
+}
+
+// watch 订阅哨兵的 +switch-master 消息，调用者需要持有锁。
+func (s *sentinel) watch(client *SingleClient) {
+	ps := client.PubSub()
+
+	if err := ps.Subscribe("+switch-master"); err != nil {
+		ps.Close()
+		return
+	}
+
+	s.pubsub = ps
+	ch := ps.Channel(10)
+
+	go func() {
+		for msg := range ch {
+			// 消息格式是 `name old-ip old-port new-ip new-port`。
+			fields := strings.Fields(msg.Payload)
+
+			if len(fields) != 5 || fields[0] != s.masterName {
+				continue
+			}
+
+			addr := net.JoinHostPort(fields[3], fields[4])
+
+			s.mu.Lock()
+			s.master = addr
+			s.mu.Unlock()
+
+			if s.onSwitch != nil {
+				s.onSwitch(addr)
+			}
+		}
+	}()
+}
+
+// reset 在 addr 无法连接时清除缓存的 master 地址。
+func (s *sentinel) reset(addr string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.master == addr {
+		s.master = ""
+	}
+}
+
+// closeSentinel 关闭当前的哨兵连接，调用者需要持有锁。
+func (s *sentinel) closeSentinel() {
+	if s.pubsub != nil {
+		s.pubsub.Close()
+		s.pubsub = nil
+	}
+
+	if s.client != nil {
+		s.client.Close()
+		s.client = nil
+	}
+}
+
+func (s *sentinel) close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	s.closeSentinel()
+	return nil
+}
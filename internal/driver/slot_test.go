@@ -0,0 +1,54 @@
+package driver
+
+import (
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+func TestSlot(t *testing.T) {
+	a := assert.New(t)
+
+	// 以下结果与 Redis 的 CLUSTER KEYSLOT 一致。
+	a.Equal(Slot("foo"), 12182)
+	a.Equal(Slot("bar"), 5061)
+	a.Equal(Slot("{user1000}.following"), Slot("{user1000}.followers"))
+	a.Equal(Slot("{user1000}.following"), Slot("user1000"))
+	a.Equal(HashTag("foo{}{bar}"), "foo{}{bar}")
+	a.Equal(HashTag("foo{{bar}}zap"), "{bar")
+	a.Equal(HashTag("foo{bar}{zap}"), "bar")
+}
+
+func TestCmdFirstKey(t *testing.T) {
+	a := assert.New(t)
+	cases := []struct {
+		args []interface{}
+		key  string
+		ok   bool
+	}{
+		{[]interface{}{"GET", "k"}, "k", true},
+		{[]interface{}{"PING"}, "", false},
+		{[]interface{}{"EVAL", "return 1", 0}, "", false},
+		{[]interface{}{"EVAL", "return 1", 1, "k"}, "k", true},
+		{[]interface{}{"OBJECT", "ENCODING", "k"}, "k", true},
+		{[]interface{}{"MEMORY", "USAGE", "k"}, "k", true},
+		{[]interface{}{"XREAD", "COUNT", 1, "STREAMS", "k", "0"}, "k", true},
+		{[]interface{}{"MIGRATE", "127.0.0.1", 6379, "", 0, 1000, "KEYS", "k"}, "k", true},
+	}
+
+	for _, c := range cases {
+		key, ok := cmdFirstKey(NewCmd(c.args...))
+		a.Equal(key, c.key)
+		a.Equal(ok, c.ok)
+	}
+}
+
+func TestCmdSlot(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal(cmdSlot(NewCmd("GET", "foo")), Slot("foo"))
+	a.Equal(cmdSlot(NewCmd("PING")), -1)
+	a.Equal(cmdSlot(NewCmd("CLUSTER", "INFO")), -1)
+	a.Equal(cmdSlot(NewCmd("CLUSTER", "GETKEYSINSLOT", 12182, 10)), 12182)
+	a.Equal(cmdSlot(NewCmd("cluster", "countkeysinslot", int64(5061))), 5061)
+}
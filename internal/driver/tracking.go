@@ -0,0 +1,315 @@
+package driver
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+)
+
+// trackingChannel 是 RESP2 连接接收 CLIENT TRACKING 失效消息时需要订阅的 channel。
+const trackingChannel = "__redis__:invalidate"
+
+// tracker 负责为连接池中的连接开启 CLIENT TRACKING，并通过一个独立的连接接收失效消息。
+//
+// 连接池里的连接可能长时间空闲，如果直接在这些连接上接收 RESP3 push 消息，
+// 失效消息要等到连接下次被使用时才能读到，因此不论使用哪个协议版本，
+// 所有连接都会通过 REDIRECT 将失效消息转发到独立的连接上：
+// 使用 RESP3 时这个连接直接收到 invalidate push 消息，使用 RESP2 时需要订阅 __redis__:invalidate。
+//
+// 接收失效消息的连接断开之后，之前开启 tracking 的连接都不再可靠，
+// tracker 会清空所有缓存，并让这些连接在下次从连接池取出或者归还时被关闭。
+//
+// 服务器是按照读取 key 的连接记录 tracking 信息的，开启 tracking 的连接断开之后，
+// 服务器不会再为这个连接读过的 key 发送失效消息，因此 tracker 会记录每个连接读取过的 TrackedKeys，
+// 连接断开时只让这些 key 失效，空闲连接被正常回收时不需要清空所有缓存。
+type tracker struct {
+	pool         *Pool
+	onInvalidate func(keys []string)
+
+	// gen 是接收失效消息的连接的版本号，每次连接断开都会加一，需要原子操作。
+	// 连接开启 tracking 时会记下当时的版本号，版本号不一致的连接不再可靠。
+	gen int64
+
+	mu     sync.Mutex
+	cn     *Conn
+	id     int64
+	closed bool
+
+	keysMu   sync.Mutex
+	connKeys map[*Conn]map[string]struct{} // connKeys 记录每个连接读取过并且还没有失效的 key。
+}
+
+func newTracker(pool *Pool, onInvalidate func(keys []string)) *tracker {
+	return &tracker{
+		pool:         pool,
+		onInvalidate: onInvalidate,
+		connKeys:     map[*Conn]map[string]struct{}{},
+	}
+}
+
+// track 为 cn 开启 CLIENT TRACKING，失效消息会转发到接收失效消息的连接上。
+func (t *tracker) track(cn *Conn) error {
+	id, gen, err := t.clientID()
+
+	if err != nil {
+		return err
+	}
+
+	if err := cn.Process(NewCmd("CLIENT", "TRACKING", "ON", "REDIRECT", id)); err != nil {
+		return err
+	}
+
+	cn.trackingGen = gen
+	cn.onTrack = t.record
+	cn.onClose = t.connClosed
+	return nil
+}
+
+// valid 判断 cn 开启的 tracking 是否依然有效。
+func (t *tracker) valid(cn *Conn) bool {
+	return cn.trackingGen == atomic.LoadInt64(&t.gen)
+}
+
+// record 记录 cn 读取过的 keys。
+func (t *tracker) record(cn *Conn, keys []string) {
+	t.keysMu.Lock()
+
+	// 连接已经关闭的话，connClosed 可能已经调用过，直接让 keys 失效。
+	if atomic.LoadInt32(&cn.closed) != 0 {
+		t.keysMu.Unlock()
+
+		if t.valid(cn) {
+			t.onInvalidate(keys)
+		}
+
+		return
+	}
+
+	m := t.connKeys[cn]
+
+	if m == nil {
+		m = map[string]struct{}{}
+		t.connKeys[cn] = m
+	}
+
+	for _, key := range keys {
+		m[key] = struct{}{}
+	}
+
+	t.keysMu.Unlock()
+}
+
+// forget 在收到失效消息之后删除所有连接记录的 keys，服务器同样不会再跟踪这些 key，keys 为 nil 时删除所有记录。
+func (t *tracker) forget(keys []string) {
+	t.keysMu.Lock()
+	defer t.keysMu.Unlock()
+
+	if keys == nil {
+		t.connKeys = map[*Conn]map[string]struct{}{}
+		return
+	}
+
+	for _, m := range t.connKeys {
+		for _, key := range keys {
+			delete(m, key)
+		}
+	}
+}
+
+// connClosed 在开启 tracking 的连接关闭时调用。
+// 服务器不会再为这个连接读过的 key 发送失效消息，只能让这些 key 失效。
+func (t *tracker) connClosed(cn *Conn) {
+	t.keysMu.Lock()
+	m := t.connKeys[cn]
+	delete(t.connKeys, cn)
+	t.keysMu.Unlock()
+
+	if len(m) == 0 || !t.valid(cn) {
+		return
+	}
+
+	keys := make([]string, 0, len(m))
+
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	t.onInvalidate(keys)
+}
+
+// clientID 返回接收失效消息的连接的 client ID 和版本号，如果还没有连接则新建一个。
+func (t *tracker) clientID() (id, gen int64, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed {
+		err = ErrClosed
+		return
+	}
+
+	if t.cn == nil {
+		if err = t.connect(); err != nil {
+			return
+		}
+	}
+
+	id = t.id
+	gen = atomic.LoadInt64(&t.gen)
+	return
+}
+
+// connect 新建接收失效消息的连接，调用者需要持有锁。
+func (t *tracker) connect() error {
+	cn, err := t.pool.NewConn()
+
+	if err != nil {
+		return err
+	}
+
+	cmd := NewCmd("CLIENT", "ID")
+
+	if err := cn.Process(cmd); err != nil {
+		cn.Close()
+		return err
+	}
+
+	id, ok := cmd.Reply().(int64)
+
+	if !ok {
+		cn.Close()
+		return ErrProtocol
+	}
+
+	if cn.protocol == 2 {
+		if err := cn.Process(NewCmd("SUBSCRIBE", trackingChannel)); err != nil {
+			cn.Close()
+			return err
+		}
+	}
+
+	cn.readPush = true
+	t.cn = cn
+	t.id = id
+	go t.receive(cn)
+	return nil
+}
+
+func (t *tracker) receive(cn *Conn) {
+	readCmd := &Cmd{
+		readTimeout:    pubSubPingInterval,
+		hasReadTimeout: true,
+	}
+	pinged := false
+
+	for {
+		reply, err := cn.read(readCmd)
+
+		if err != nil {
+			if e, ok := err.(net.Error); ok && e.Timeout() && !pinged {
+				// 连接长时间没有消息，通过 PING 检查连接是否还可用。
+				if err = cn.writeCmds(NewCmd("PING")); err == nil {
+					pinged = true
+					continue
+				}
+			}
+
+			if _, ok := err.(Error); ok {
+				continue
+			}
+
+			t.drop(cn)
+			return
+		}
+
+		pinged = false
+
+		if keys, ok := parseInvalidation(reply); ok {
+			t.forget(keys)
+			t.onInvalidate(keys)
+		}
+	}
+}
+
+// drop 关闭接收失效消息的连接 cn，之后新开启 tracking 的连接会转发到一个新的连接上。
+func (t *tracker) drop(cn *Conn) {
+	t.mu.Lock()
+	current := t.cn == cn && !t.closed
+
+	if current {
+		t.cn = nil
+		atomic.AddInt64(&t.gen, 1)
+	}
+
+	t.mu.Unlock()
+	cn.Close()
+
+	// 连接断开期间的失效消息已经丢失，只能清空所有缓存。
+	if current {
+		t.forget(nil)
+		t.onInvalidate(nil)
+	}
+}
+
+// filter 在接收失效消息的连接满足 fn 条件时关闭这个连接。
+func (t *tracker) filter(fn func(cn *Conn) bool) {
+	t.mu.Lock()
+	cn := t.cn
+	t.mu.Unlock()
+
+	if cn != nil && fn(cn) {
+		t.drop(cn)
+	}
+}
+
+func (t *tracker) close() {
+	t.mu.Lock()
+	t.closed = true
+	cn := t.cn
+	t.cn = nil
+	t.mu.Unlock()
+
+	if cn != nil {
+		cn.Close()
+	}
+}
+
+// parseInvalidation 解析失效消息，keys 为 nil 代表需要清空所有缓存，比如服务器执行了 FLUSHALL。
+// RESP3 的失效消息格式是 push 消息 [invalidate, keys]，
+// RESP2 的失效消息格式是 [message, __redis__:invalidate, keys]。
+func parseInvalidation(reply interface{}) (keys []string, ok bool) {
+	var data interface{}
+
+	switch v := reply.(type) {
+	case Push:
+		if len(v) != 2 || replyString(v[0]) != "invalidate" {
+			return
+		}
+
+		data = v[1]
+
+	case []interface{}:
+		if len(v) != 3 || replyString(v[0]) != "message" || replyString(v[1]) != trackingChannel {
+			return
+		}
+
+		data = v[2]
+
+	default:
+		return
+	}
+
+	ok = true
+	items, _ := data.([]interface{})
+
+	if items == nil {
+		return
+	}
+
+	keys = make([]string, 0, len(items))
+
+	for _, item := range items {
+		keys = append(keys, replyString(item))
+	}
+
+	return
+}
@@ -0,0 +1,30 @@
+package driver
+
+import (
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+func TestParseInvalidation(t *testing.T) {
+	a := assert.New(t)
+	cases := []struct {
+		reply interface{}
+		keys  []string
+		ok    bool
+	}{
+		{Push{[]byte("invalidate"), []interface{}{[]byte("k1"), []byte("k2")}}, []string{"k1", "k2"}, true},
+		{Push{[]byte("invalidate"), nil}, nil, true},
+		{[]interface{}{[]byte("message"), []byte(trackingChannel), []interface{}{[]byte("k")}}, []string{"k"}, true},
+		{[]interface{}{[]byte("message"), []byte(trackingChannel), nil}, nil, true},
+		{[]interface{}{[]byte("message"), []byte("other"), []byte("k")}, nil, false},
+		{Push{[]byte("tracking-redir-broken"), int64(1)}, nil, false},
+		{Status("PONG"), nil, false},
+	}
+
+	for _, c := range cases {
+		keys, ok := parseInvalidation(c.reply)
+		a.Equal(keys, c.keys)
+		a.Equal(ok, c.ok)
+	}
+}
@@ -1,15 +1,16 @@
 package redis
 
 import (
+	"time"
+
 	"github.com/altstory/go-redis/internal/driver"
 )
 
 // Lists 代表 Redis 跟 list 相关的接口，详见 https://redis.io/commands#list。
 type Lists interface {
-	// TODO: BLPop
-	// TODO: BRPop
-	// TODO: BRPopLPush
-
+	BLPop(timeout time.Duration, keys ...string) (kv KeyAndValue, popped bool, err error)   // BLPOP key [key ...] timeout，timeout 为 0 代表一直阻塞
+	BRPop(timeout time.Duration, keys ...string) (kv KeyAndValue, popped bool, err error)   // BRPOP key [key ...] timeout，timeout 为 0 代表一直阻塞
+	BRPopLPush(src string, dst string, timeout time.Duration) (value BulkString, err error) // 如果超时，value.IsNull() 为 true
 	LIndex(key string, index int) (value BulkString, err error)
 	LInsertBefore(key string, pivot string, value string) (l int, err error)
 	LInsertAfter(key string, pivot string, value string) (l int, err error)
@@ -27,9 +28,79 @@ type Lists interface {
 	RPushX(key string, value string) (l int, err error)
 }
 
+func (r *redisImpl) BLPop(timeout time.Duration, keys ...string) (kv KeyAndValue, popped bool, err error) {
+	if len(keys) == 0 {
+		return
+	}
+
+	timeout = r.blockTimeoutSeconds(timeout)
+	args := appendArgs([]interface{}{"BLPOP"}, keys)
+	args = append(args, int64(timeout/time.Second))
+	err = r.do("BLPOP", func(client driver.Client) error {
+		kv, popped, err = r.blockingPop(client, newBlockingCmd(timeout, args...))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) BRPop(timeout time.Duration, keys ...string) (kv KeyAndValue, popped bool, err error) {
+	if len(keys) == 0 {
+		return
+	}
+
+	timeout = r.blockTimeoutSeconds(timeout)
+	args := appendArgs([]interface{}{"BRPOP"}, keys)
+	args = append(args, int64(timeout/time.Second))
+	err = r.do("BRPOP", func(client driver.Client) error {
+		kv, popped, err = r.blockingPop(client, newBlockingCmd(timeout, args...))
+		return err
+	})
+	return
+}
+
+// blockingPop 执行 BLPOP 或 BRPOP，并将返回的 [key, value] 转化成 KeyAndValue。
+// 命令会根据 timeout 自动延长读超时，所以阻塞时间超过 ReadTimeout 也不会出错。
+func (r *redisImpl) blockingPop(client driver.Client, cmd *command) (kv KeyAndValue, popped bool, err error) {
+	cmder, err := r.processBlocking(client, cmd)
+
+	if err != nil {
+		return
+	}
+
+	strs, err := mustBeStrings(client, cmder)
+
+	if err != nil {
+		return
+	}
+
+	// 超时的时候 Redis 返回 nil，strs 为空。
+	if len(strs) != 2 {
+		return
+	}
+
+	kv = MakeKeyAndValue(strs[0], strs[1])
+	popped = true
+	return
+}
+
+func (r *redisImpl) BRPopLPush(src string, dst string, timeout time.Duration) (value BulkString, err error) {
+	timeout = r.blockTimeoutSeconds(timeout)
+	err = r.do("BRPOPLPUSH", func(client driver.Client) error {
+		cmder, err := r.processBlocking(client, newBlockingCmd(timeout, "BRPOPLPUSH", src, dst, int64(timeout/time.Second)))
+
+		if err != nil {
+			return err
+		}
+
+		value, err = mustBeBulkString(client, cmder)
+		return err
+	})
+	return
+}
+
 func (r *redisImpl) LIndex(key string, index int) (value BulkString, err error) {
 	err = r.do("LINDEX", func(client driver.Client) error {
-		value, err = mustBeBulkString(client, client.LIndex(key, int64(index)))
+		value, err = mustBeBulkString(client, process(client, newCmd("LINDEX", key, index)))
 		return err
 	})
 	return
@@ -37,7 +108,7 @@ func (r *redisImpl) LIndex(key string, index int) (value BulkString, err error)
 
 func (r *redisImpl) LInsertBefore(key string, pivot string, value string) (l int, err error) {
 	err = r.do("LINSERTBEFORE", func(client driver.Client) error {
-		l, err = mustBeInt(client, client.LInsertBefore(key, pivot, value))
+		l, err = mustBeInt(client, process(client, newCmd("LINSERT", key, "BEFORE", pivot, value)))
 		return err
 	})
 	return
@@ -45,7 +116,7 @@ func (r *redisImpl) LInsertBefore(key string, pivot string, value string) (l int
 
 func (r *redisImpl) LInsertAfter(key string, pivot string, value string) (l int, err error) {
 	err = r.do("LINSERTAFTER", func(client driver.Client) error {
-		l, err = mustBeInt(client, client.LInsertAfter(key, pivot, value))
+		l, err = mustBeInt(client, process(client, newCmd("LINSERT", key, "AFTER", pivot, value)))
 		return err
 	})
 	return
@@ -53,7 +124,7 @@ func (r *redisImpl) LInsertAfter(key string, pivot string, value string) (l int,
 
 func (r *redisImpl) LLen(key string) (l int, err error) {
 	err = r.do("LLEN", func(client driver.Client) error {
-		l, err = mustBeInt(client, client.HLen(key))
+		l, err = mustBeInt(client, process(client, newCmd("LLEN", key)))
 		return err
 	})
 	return
@@ -61,7 +132,7 @@ func (r *redisImpl) LLen(key string) (l int, err error) {
 
 func (r *redisImpl) LPop(key string) (value BulkString, err error) {
 	err = r.do("LPOP", func(client driver.Client) error {
-		value, err = mustBeBulkString(client, client.LPop(key))
+		value, err = mustBeBulkString(client, process(client, newCmd("LPOP", key)))
 		return err
 	})
 	return
@@ -72,22 +143,16 @@ func (r *redisImpl) LPush(key string, values ...string) (l int, err error) {
 		return
 	}
 
-	data := make([]interface{}, 0, len(values))
-
-	for _, v := range values {
-		data = append(data, v)
-	}
-
 	err = r.do("LPUSH", func(client driver.Client) error {
-		l, err = mustBeInt(client, client.LPush(key, data...))
+		l, err = mustBeInt(client, process(client, newCmd(appendArgs([]interface{}{"LPUSH", key}, values)...)))
 		return err
 	})
 	return
 }
 
 func (r *redisImpl) LPushX(key string, value string) (l int, err error) {
-	err = r.do("LPUSH", func(client driver.Client) error {
-		l, err = mustBeInt(client, client.LPush(key, value))
+	err = r.do("LPUSHX", func(client driver.Client) error {
+		l, err = mustBeInt(client, process(client, newCmd("LPUSHX", key, value)))
 		return err
 	})
 	return
@@ -95,7 +160,7 @@ func (r *redisImpl) LPushX(key string, value string) (l int, err error) {
 
 func (r *redisImpl) LRange(key string, start int, stop int) (values []BulkString, err error) {
 	err = r.do("LRANGE", func(client driver.Client) error {
-		values, err = mustBeBulkStrings(client, client.LRange(key, int64(start), int64(stop)))
+		values, err = mustBeBulkStrings(client, process(client, newCmd("LRANGE", key, start, stop)))
 		return err
 	})
 	return
@@ -103,7 +168,7 @@ func (r *redisImpl) LRange(key string, start int, stop int) (values []BulkString
 
 func (r *redisImpl) LRem(key string, count int, value string) (removed int, err error) {
 	err = r.do("LREM", func(client driver.Client) error {
-		removed, err = mustBeInt(client, client.LRem(key, int64(count), value))
+		removed, err = mustBeInt(client, process(client, newCmd("LREM", key, count, value)))
 		return err
 	})
 	return
@@ -111,7 +176,7 @@ func (r *redisImpl) LRem(key string, count int, value string) (removed int, err
 
 func (r *redisImpl) LSet(key string, index int, value string) (err error) {
 	err = r.do("LSET", func(client driver.Client) error {
-		_, err = mustBeStatus(client, client.LSet(key, int64(index), value))
+		_, err = mustBeStatus(client, process(client, newCmd("LSET", key, index, value)))
 		return err
 	})
 	return
@@ -119,7 +184,7 @@ func (r *redisImpl) LSet(key string, index int, value string) (err error) {
 
 func (r *redisImpl) LTrim(key string, start int, stop int) (err error) {
 	err = r.do("LTRIM", func(client driver.Client) error {
-		_, err = mustBeStatus(client, client.LTrim(key, int64(start), int64(stop)))
+		_, err = mustBeStatus(client, process(client, newCmd("LTRIM", key, start, stop)))
 		return err
 	})
 	return
@@ -127,7 +192,7 @@ func (r *redisImpl) LTrim(key string, start int, stop int) (err error) {
 
 func (r *redisImpl) RPop(key string) (value BulkString, err error) {
 	err = r.do("RPOP", func(client driver.Client) error {
-		value, err = mustBeBulkString(client, client.RPop(key))
+		value, err = mustBeBulkString(client, process(client, newCmd("RPOP", key)))
 		return err
 	})
 	return
@@ -135,7 +200,7 @@ func (r *redisImpl) RPop(key string) (value BulkString, err error) {
 
 func (r *redisImpl) RPopLPush(src string, dst string) (value BulkString, err error) {
 	err = r.do("RPOPLPUSH", func(client driver.Client) error {
-		value, err = mustBeBulkString(client, client.RPopLPush(src, dst))
+		value, err = mustBeBulkString(client, process(client, newCmd("RPOPLPUSH", src, dst)))
 		return err
 	})
 	return
@@ -146,14 +211,8 @@ func (r *redisImpl) RPush(key string, values ...string) (l int, err error) {
 		return
 	}
 
-	data := make([]interface{}, 0, len(values))
-
-	for _, v := range values {
-		data = append(data, v)
-	}
-
 	err = r.do("RPUSH", func(client driver.Client) error {
-		l, err = mustBeInt(client, client.RPush(key, data...))
+		l, err = mustBeInt(client, process(client, newCmd(appendArgs([]interface{}{"RPUSH", key}, values)...)))
 		return err
 	})
 	return
@@ -161,7 +220,7 @@ func (r *redisImpl) RPush(key string, values ...string) (l int, err error) {
 
 func (r *redisImpl) RPushX(key string, value string) (l int, err error) {
 	err = r.do("RPUSHX", func(client driver.Client) error {
-		l, err = mustBeInt(client, client.RPushX(key, value))
+		l, err = mustBeInt(client, process(client, newCmd("RPUSHX", key, value)))
 		return err
 	})
 	return
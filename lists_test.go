@@ -0,0 +1,134 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/huandu/go-assert"
+)
+
+func TestBlockingListMethods(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	f := factory(t)
+	r := f.New(ctx)
+	resetRedis(t, r)
+
+	_, err := r.RPush("blist-1", "a", "b")
+	a.NilError(err)
+
+	kv, popped, err := r.BLPop(time.Second, "blist-0", "blist-1")
+	a.NilError(err)
+	a.Assert(popped)
+	a.Equal(kv, MakeKeyAndValue("blist-1", "a"))
+
+	kv, popped, err = r.BRPop(time.Second, "blist-1")
+	a.NilError(err)
+	a.Assert(popped)
+	a.Equal(kv, MakeKeyAndValue("blist-1", "b"))
+
+	// 不足 1s 的超时会被向上取整到 1s，而不是一直阻塞。
+	start := time.Now()
+	_, popped, err = r.BLPop(100*time.Millisecond, "blist-1")
+	a.NilError(err)
+	a.Assert(!popped)
+	a.Assert(time.Since(start) < 3*time.Second)
+
+	value, err := r.BRPopLPush("blist-1", "blist-2", time.Second)
+	a.NilError(err)
+	a.Assert(value.IsNull())
+
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		f.New(ctx).RPush("blist-1", "c")
+	}()
+
+	value, err = r.BRPopLPush("blist-1", "blist-2", 0)
+	a.NilError(err)
+	a.Equal(value.String(), "c")
+
+	values, err := r.LRange("blist-2", 0, -1)
+	a.NilError(err)
+	a.Equal(len(values), 1)
+	a.Equal(values[0].String(), "c")
+
+	// ctx 超时之后立即返回，不用等到 Redis 超时。
+	timeoutCtx, cancel := context.WithTimeout(ctx, 200*time.Millisecond)
+	defer cancel()
+	start = time.Now()
+	_, _, err = f.New(timeoutCtx).BLPop(0, "blist-1")
+	a.Equal(err, context.DeadlineExceeded)
+	a.Assert(time.Since(start) < time.Second)
+
+	cancelCtx, cancel := context.WithCancel(ctx)
+	time.AfterFunc(200*time.Millisecond, cancel)
+	start = time.Now()
+	_, _, err = f.New(cancelCtx).BRPop(5*time.Second, "blist-1")
+	a.Equal(err, context.Canceled)
+	a.Assert(time.Since(start) < time.Second)
+}
+
+func TestBlockingListCancel(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	f := factory(t)
+	r := f.New(ctx)
+	resetRedis(t, r)
+
+	// 被取消的 BLPOP 不能在之后弹出数据，否则数据会丢失。
+	cancelCtx, cancel := context.WithCancel(ctx)
+	time.AfterFunc(100*time.Millisecond, cancel)
+	_, _, err := f.New(cancelCtx).BLPop(0, "blist-cancel")
+	a.Equal(err, context.Canceled)
+
+	// 超时时间向下取整到 deadline 之前，服务器会在 ctx 到期前超时返回。
+	timeoutCtx, cancel := context.WithTimeout(ctx, 1500*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	value, err := f.New(timeoutCtx).BRPopLPush("blist-cancel", "blist-cancel-dst", 5*time.Second)
+	a.NilError(err)
+	a.Assert(value.IsNull())
+	a.Assert(time.Since(start) < 1500*time.Millisecond)
+
+	timeoutCtx, cancel = context.WithTimeout(ctx, 300*time.Millisecond)
+	defer cancel()
+	_, _, err = f.New(timeoutCtx).BRPop(time.Second, "blist-cancel")
+	a.Equal(err, context.DeadlineExceeded)
+
+	_, err = r.RPush("blist-cancel", "a")
+	a.NilError(err)
+	time.Sleep(100 * time.Millisecond)
+
+	l, err := r.LLen("blist-cancel")
+	a.NilError(err)
+	a.Equal(l, 1)
+	l, err = r.LLen("blist-cancel-dst")
+	a.NilError(err)
+	a.Equal(l, 0)
+}
+
+func TestBlockingListReadTimeout(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	f := NewFactory(&Config{
+		Client: &ClientConfig{
+			Addr:        testAddr,
+			ReadTimeout: 200 * time.Millisecond,
+		},
+	})
+
+	if err := f.Conn(ctx); err != nil {
+		t.Skipf("fail to connect Redis server. [err:%v]", err)
+	}
+
+	r := f.New(ctx)
+	resetRedis(t, r)
+
+	// 阻塞时间超过 ReadTimeout 时不能出现读超时。
+	start := time.Now()
+	_, popped, err := r.BLPop(time.Second, "blist-timeout")
+	a.NilError(err)
+	a.Assert(!popped)
+	a.Assert(time.Since(start) >= time.Second)
+}
@@ -0,0 +1,92 @@
+package redis
+
+import (
+	"strconv"
+	"strings"
+)
+
+// MemoryStats 代表 MEMORY STATS 返回的内存使用情况，所有内存大小的单位都是字节。
+type MemoryStats struct {
+	PeakAllocated      int64                 // PeakAllocated 是 Redis 历史上分配内存的峰值。
+	TotalAllocated     int64                 // TotalAllocated 是当前分配的内存总量。
+	StartupAllocated   int64                 // StartupAllocated 是 Redis 启动时分配的内存。
+	ReplicationBacklog int64                 // ReplicationBacklog 是复制积压缓冲区的大小。
+	ClientsReplicas    int64                 // ClientsReplicas 是所有 replica 连接的缓冲区大小。
+	ClientsNormal      int64                 // ClientsNormal 是所有普通客户端连接的缓冲区大小。
+	AOFBuffer          int64                 // AOFBuffer 是 AOF 缓冲区的大小。
+	LuaCaches          int64                 // LuaCaches 是 Lua 脚本缓存的大小。
+	OverheadTotal      int64                 // OverheadTotal 是除数据以外的所有额外内存开销。
+	KeysCount          int64                 // KeysCount 是所有数据库中 key 的总数。
+	KeysBytesPerKey    int64                 // KeysBytesPerKey 是平均每个 key 占用的内存。
+	DatasetBytes       int64                 // DatasetBytes 是数据本身占用的内存。
+	DatasetPercentage  float64               // DatasetPercentage 是数据占净内存的百分比。
+	PeakPercentage     float64               // PeakPercentage 是当前内存占峰值的百分比。
+	Fragmentation      float64               // Fragmentation 是内存碎片率。
+	FragmentationBytes int64                 // FragmentationBytes 是内存碎片的大小。
+	DB                 map[int]MemoryStatsDB // DB 是每个数据库的额外内存开销，key 是数据库编号。
+
+	Raw map[string]string // Raw 保存所有非数据库相关的原始字段，用于读取上面没有列出的字段。
+}
+
+// MemoryStatsDB 代表 MEMORY STATS 中一个数据库的额外内存开销。
+type MemoryStatsDB struct {
+	OverheadHashtableMain    int64 // OverheadHashtableMain 是主字典的额外开销。
+	OverheadHashtableExpires int64 // OverheadHashtableExpires 是过期字典的额外开销。
+}
+
+// parseMemoryStats 解析 MEMORY STATS 的返回值，格式是：
+//     [name1, value1, name2, value2, ..., db.0, [name1, value1, ...], ...]
+func parseMemoryStats(mvs []MultiValue) (stats MemoryStats) {
+	stats.Raw = map[string]string{}
+
+	for name, mv := range parseMultiValueMap(mvs) {
+		if strings.HasPrefix(name, "db.") {
+			values, ok := mv.MultiValues()
+
+			if !ok {
+				continue
+			}
+
+			db, err := strconv.Atoi(name[len("db."):])
+
+			if err != nil {
+				continue
+			}
+
+			if stats.DB == nil {
+				stats.DB = map[int]MemoryStatsDB{}
+			}
+
+			var msdb MemoryStatsDB
+			m := parseMultiValueMap(values)
+			msdb.OverheadHashtableMain, _ = m["overhead.hashtable.main"].Int64()
+			msdb.OverheadHashtableExpires, _ = m["overhead.hashtable.expires"].Int64()
+			stats.DB[db] = msdb
+			continue
+		}
+
+		if n, ok := mv.Int64(); ok {
+			stats.Raw[name] = strconv.FormatInt(n, 10)
+		} else {
+			stats.Raw[name] = multiValueString(mv)
+		}
+	}
+
+	stats.PeakAllocated = parseInfoInt64(stats.Raw, "peak.allocated")
+	stats.TotalAllocated = parseInfoInt64(stats.Raw, "total.allocated")
+	stats.StartupAllocated = parseInfoInt64(stats.Raw, "startup.allocated")
+	stats.ReplicationBacklog = parseInfoInt64(stats.Raw, "replication.backlog")
+	stats.ClientsReplicas = parseInfoInt64(stats.Raw, "clients.slaves")
+	stats.ClientsNormal = parseInfoInt64(stats.Raw, "clients.normal")
+	stats.AOFBuffer = parseInfoInt64(stats.Raw, "aof.buffer")
+	stats.LuaCaches = parseInfoInt64(stats.Raw, "lua.caches")
+	stats.OverheadTotal = parseInfoInt64(stats.Raw, "overhead.total")
+	stats.KeysCount = parseInfoInt64(stats.Raw, "keys.count")
+	stats.KeysBytesPerKey = parseInfoInt64(stats.Raw, "keys.bytes-per-key")
+	stats.DatasetBytes = parseInfoInt64(stats.Raw, "dataset.bytes")
+	stats.DatasetPercentage, _ = strconv.ParseFloat(stats.Raw["dataset.percentage"], 64)
+	stats.PeakPercentage, _ = strconv.ParseFloat(stats.Raw["peak.percentage"], 64)
+	stats.Fragmentation, _ = strconv.ParseFloat(stats.Raw["fragmentation"], 64)
+	stats.FragmentationBytes = parseInfoInt64(stats.Raw, "fragmentation.bytes")
+	return
+}
@@ -2,8 +2,6 @@ package redis
 
 import (
 	"time"
-
-	"github.com/go-redis/redis"
 )
 
 // SetOption 代表设置一个 key 时候用到的各种选项。
@@ -133,18 +131,6 @@ func (so *StoreOption) Args() []interface{} {
 	return nil
 }
 
-func (so *StoreOption) fillZStore(zstore *redis.ZStore) {
-	switch so.t {
-	case storeOptionWeights:
-		weights := so.opt.([]float64)
-		zstore.Weights = weights
-
-	case storeOptionAggregate:
-		aggregate := so.opt.(string)
-		zstore.Aggregate = aggregate
-	}
-}
-
 type storeOptionType int
 
 const (
@@ -172,11 +158,6 @@ func (ro *RangeOption) Args() []interface{} {
 	return []interface{}{"LIMIT", ro.offset, ro.count}
 }
 
-func (ro *RangeOption) fillZRangeBy(zrangeby *redis.ZRangeBy) {
-	zrangeby.Offset = int64(ro.offset)
-	zrangeby.Count = int64(ro.count)
-}
-
 // ScanOption 代表一个扫描选项。
 type ScanOption struct {
 	t   scanOptionType
@@ -199,6 +180,25 @@ func Count(count int) ScanOption {
 	}
 }
 
+// ScanType 返回一个扫描选项，用于在 Scan 中实现 TYPE type，只返回指定类型的 key。
+// 这个选项只对 Scan 有效，需要 Redis 6.0 及以上版本。
+func ScanType(keyType KeyType) ScanOption {
+	return ScanOption{
+		t:   scanOptionKeyType,
+		opt: string(keyType),
+	}
+}
+
+// NoDedup 返回一个扫描选项，让各种 Iter 方法返回的迭代器不去重。
+// 默认情况下迭代器需要在内存中记录所有返回过的元素，遍历大量元素时内存占用会一直增长，
+// 使用这个选项之后迭代器不再记录元素，同一个元素可能会被返回多次，调用者需要自行处理。
+// 这个选项不会发送给 Redis，对 Scan、SScan 等非迭代器的方法没有作用。
+func NoDedup() ScanOption {
+	return ScanOption{
+		t: scanOptionNoDedup,
+	}
+}
+
 // Args 返回用于拼接 Redis 命令的参数。
 func (so *ScanOption) Args() []interface{} {
 	switch so.t {
@@ -206,6 +206,8 @@ func (so *ScanOption) Args() []interface{} {
 		return []interface{}{"MATCH", so.opt}
 	case scanOptionCount:
 		return []interface{}{"COUNT", so.opt}
+	case scanOptionKeyType:
+		return []interface{}{"TYPE", so.opt}
 	}
 
 	return nil
@@ -217,6 +219,8 @@ const (
 	scanOptionTypeInvalid scanOptionType = iota
 	scanOptionMatch
 	scanOptionCount
+	scanOptionKeyType
+	scanOptionNoDedup
 )
 
 // FlushOption 代表 FLUSHDB 和 FLUSHALL 的选项。
@@ -231,3 +235,634 @@ const (
 func Async() FlushOption {
 	return flushOptionAsync
 }
+
+// CopyOption 代表 COPY 的选项。
+type CopyOption struct {
+	t   copyOptionType
+	opt interface{}
+}
+
+// CopyDB 返回一个 COPY 选项，用于将 key 复制到编号为 db 的数据库。
+// 详见 https://redis.io/commands/copy。
+func CopyDB(db int) CopyOption {
+	return CopyOption{
+		t:   copyOptionDB,
+		opt: db,
+	}
+}
+
+// CopyReplace 返回一个 COPY 选项，用于在目标 key 存在时覆盖它。
+// 详见 https://redis.io/commands/copy。
+func CopyReplace() CopyOption {
+	return CopyOption{
+		t: copyOptionReplace,
+	}
+}
+
+// Args 返回用于拼接 Redis 命令的参数。
+func (co *CopyOption) Args() []interface{} {
+	switch co.t {
+	case copyOptionDB:
+		return []interface{}{"DB", co.opt}
+	case copyOptionReplace:
+		return []interface{}{"REPLACE"}
+	}
+
+	return nil
+}
+
+type copyOptionType int
+
+const (
+	copyOptionInvalid copyOptionType = iota
+	copyOptionDB
+	copyOptionReplace
+)
+
+// MigrateOption 代表 MIGRATE 的选项。
+type MigrateOption struct {
+	t   migrateOptionType
+	opt []interface{}
+}
+
+// MigrateCopy 返回一个 MIGRATE 选项，用于在迁移后保留源实例上的 key。
+// 详见 https://redis.io/commands/migrate。
+func MigrateCopy() MigrateOption {
+	return MigrateOption{
+		t: migrateOptionCopy,
+	}
+}
+
+// MigrateReplace 返回一个 MIGRATE 选项，用于在目标实例上的 key 存在时覆盖它。
+// 详见 https://redis.io/commands/migrate。
+func MigrateReplace() MigrateOption {
+	return MigrateOption{
+		t: migrateOptionReplace,
+	}
+}
+
+// MigrateAuth 返回一个 MIGRATE 选项，用于设置目标实例的密码。
+// 详见 https://redis.io/commands/migrate。
+func MigrateAuth(password string) MigrateOption {
+	return MigrateOption{
+		t:   migrateOptionAuth,
+		opt: []interface{}{password},
+	}
+}
+
+// MigrateAuth2 返回一个 MIGRATE 选项，用于设置目标实例的用户名和密码，需要 Redis 6.0 及以上版本。
+// 详见 https://redis.io/commands/migrate。
+func MigrateAuth2(username string, password string) MigrateOption {
+	return MigrateOption{
+		t:   migrateOptionAuth2,
+		opt: []interface{}{username, password},
+	}
+}
+
+// Args 返回用于拼接 Redis 命令的参数。
+func (mo *MigrateOption) Args() []interface{} {
+	switch mo.t {
+	case migrateOptionCopy:
+		return []interface{}{"COPY"}
+	case migrateOptionReplace:
+		return []interface{}{"REPLACE"}
+	case migrateOptionAuth:
+		return append([]interface{}{"AUTH"}, mo.opt...)
+	case migrateOptionAuth2:
+		return append([]interface{}{"AUTH2"}, mo.opt...)
+	}
+
+	return nil
+}
+
+type migrateOptionType int
+
+const (
+	migrateOptionInvalid migrateOptionType = iota
+	migrateOptionCopy
+	migrateOptionReplace
+	migrateOptionAuth
+	migrateOptionAuth2
+)
+
+// RestoreOption 代表 RESTORE 的选项。
+type RestoreOption struct {
+	t   restoreOptionType
+	opt interface{}
+}
+
+// RestoreReplace 返回一个 RESTORE 选项，用于在 key 存在时覆盖它。
+// 详见 https://redis.io/commands/restore。
+func RestoreReplace() RestoreOption {
+	return RestoreOption{
+		t: restoreOptionReplace,
+	}
+}
+
+// RestoreAbsTTL 返回一个 RESTORE 选项，用于让 key 在 t 时刻过期，即 ABSTTL，此时 Restore 的 ttl 参数会被忽略。
+// 详见 https://redis.io/commands/restore。
+func RestoreAbsTTL(t time.Time) RestoreOption {
+	return RestoreOption{
+		t:   restoreOptionAbsTTL,
+		opt: t,
+	}
+}
+
+// RestoreIdleTime 返回一个 RESTORE 选项，用于设置 key 的闲置时间，精度是秒，只在 LRU 淘汰策略下有效。
+// 详见 https://redis.io/commands/restore。
+func RestoreIdleTime(idle time.Duration) RestoreOption {
+	return RestoreOption{
+		t:   restoreOptionIdleTime,
+		opt: idle,
+	}
+}
+
+// RestoreFreq 返回一个 RESTORE 选项，用于设置 key 的访问频率，只在 LFU 淘汰策略下有效。
+// 详见 https://redis.io/commands/restore。
+func RestoreFreq(freq int) RestoreOption {
+	return RestoreOption{
+		t:   restoreOptionFreq,
+		opt: freq,
+	}
+}
+
+// Args 返回用于拼接 Redis 命令的参数。
+func (ro *RestoreOption) Args() []interface{} {
+	switch ro.t {
+	case restoreOptionReplace:
+		return []interface{}{"REPLACE"}
+	case restoreOptionAbsTTL:
+		return []interface{}{"ABSTTL"}
+	case restoreOptionIdleTime:
+		return []interface{}{"IDLETIME", int64(ro.opt.(time.Duration) / time.Second)}
+	case restoreOptionFreq:
+		return []interface{}{"FREQ", ro.opt}
+	}
+
+	return nil
+}
+
+type restoreOptionType int
+
+const (
+	restoreOptionInvalid restoreOptionType = iota
+	restoreOptionReplace
+	restoreOptionAbsTTL
+	restoreOptionIdleTime
+	restoreOptionFreq
+)
+
+// SortOption 代表 SORT 的选项。
+type SortOption struct {
+	t   sortOptionType
+	opt []interface{}
+}
+
+// SortBy 返回一个 SORT 选项，用于按照 pattern 对应的外部 key 排序，pattern 为 nosort 时不排序。
+// 详见 https://redis.io/commands/sort。
+func SortBy(pattern string) SortOption {
+	return SortOption{
+		t:   sortOptionBy,
+		opt: []interface{}{pattern},
+	}
+}
+
+// SortGet 返回一个 SORT 选项，用于返回 pattern 对应的外部 key 的值而不是元素本身，# 代表元素本身。
+// 这个选项可以使用多次，每个元素会按顺序返回每个 pattern 对应的值。
+// 详见 https://redis.io/commands/sort。
+func SortGet(pattern string) SortOption {
+	return SortOption{
+		t:   sortOptionGet,
+		opt: []interface{}{pattern},
+	}
+}
+
+// SortLimit 返回一个 SORT 选项，用于跳过排序结果中前 offset 个元素并返回最多 count 个元素。
+// 详见 https://redis.io/commands/sort。
+func SortLimit(offset int, count int) SortOption {
+	return SortOption{
+		t:   sortOptionLimit,
+		opt: []interface{}{offset, count},
+	}
+}
+
+// SortAlpha 返回一个 SORT 选项，用于按照字典序而不是数字大小排序。
+// 详见 https://redis.io/commands/sort。
+func SortAlpha() SortOption {
+	return SortOption{
+		t: sortOptionAlpha,
+	}
+}
+
+// SortDesc 返回一个 SORT 选项，用于按照从大到小的顺序排序。
+// 详见 https://redis.io/commands/sort。
+func SortDesc() SortOption {
+	return SortOption{
+		t: sortOptionDesc,
+	}
+}
+
+// Args 返回用于拼接 Redis 命令的参数。
+func (so *SortOption) Args() []interface{} {
+	switch so.t {
+	case sortOptionBy:
+		return append([]interface{}{"BY"}, so.opt...)
+	case sortOptionGet:
+		return append([]interface{}{"GET"}, so.opt...)
+	case sortOptionLimit:
+		return append([]interface{}{"LIMIT"}, so.opt...)
+	case sortOptionAlpha:
+		return []interface{}{"ALPHA"}
+	case sortOptionDesc:
+		return []interface{}{"DESC"}
+	}
+
+	return nil
+}
+
+type sortOptionType int
+
+const (
+	sortOptionInvalid sortOptionType = iota
+	sortOptionBy
+	sortOptionGet
+	sortOptionLimit
+	sortOptionAlpha
+	sortOptionDesc
+)
+
+// MemoryUsageOption 代表 MEMORY USAGE 的选项。
+type MemoryUsageOption struct {
+	t   memoryUsageOptionType
+	opt interface{}
+}
+
+// Samples 返回一个 MEMORY USAGE 选项，用于设置统计嵌套数据结构时采样的元素数量，0 代表统计所有元素。
+// 详见 https://redis.io/commands/memory-usage。
+func Samples(count int) MemoryUsageOption {
+	return MemoryUsageOption{
+		t:   memoryUsageOptionSamples,
+		opt: count,
+	}
+}
+
+// Args 返回用于拼接 Redis 命令的参数。
+func (muo *MemoryUsageOption) Args() []interface{} {
+	switch muo.t {
+	case memoryUsageOptionSamples:
+		return []interface{}{"SAMPLES", muo.opt}
+	}
+
+	return nil
+}
+
+type memoryUsageOptionType int
+
+const (
+	memoryUsageOptionInvalid memoryUsageOptionType = iota
+	memoryUsageOptionSamples
+)
+
+// WatchOption 代表 Watch 的选项。
+type WatchOption struct {
+	t   watchOptionType
+	opt interface{}
+}
+
+// MaxAttempts 返回一个 Watch 选项，用于设置事务因为 key 被修改而失败时最多尝试执行的次数。
+// 默认值是 DefaultWatchMaxAttempts。
+func MaxAttempts(attempts int) WatchOption {
+	return WatchOption{
+		t:   watchOptionMaxAttempts,
+		opt: attempts,
+	}
+}
+
+// RetryBackoff 返回一个 Watch 选项，用于设置每次重试前需要等待的时间。
+// 默认值是 DefaultWatchRetryBackoff。
+func RetryBackoff(backoff time.Duration) WatchOption {
+	return WatchOption{
+		t:   watchOptionRetryBackoff,
+		opt: backoff,
+	}
+}
+
+type watchOptionType int
+
+const (
+	watchOptionInvalid watchOptionType = iota
+	watchOptionMaxAttempts
+	watchOptionRetryBackoff
+)
+
+// TrimOption 代表 stream 的裁剪选项，用于 XADD 和 XTRIM。
+type TrimOption struct {
+	t   trimOptionType
+	opt interface{}
+}
+
+// MaxLen 返回一个裁剪选项，用于将 stream 精确的裁剪到最多 maxLen 个 entry。
+// 详见 https://redis.io/commands/xtrim。
+func MaxLen(maxLen int) TrimOption {
+	return TrimOption{
+		t:   trimOptionMaxLen,
+		opt: maxLen,
+	}
+}
+
+// MaxLenApprox 返回一个裁剪选项，用于将 stream 大致裁剪到 maxLen 个 entry，即 MAXLEN ~ maxLen。
+// 详见 https://redis.io/commands/xtrim。
+func MaxLenApprox(maxLen int) TrimOption {
+	return TrimOption{
+		t:   trimOptionMaxLenApprox,
+		opt: maxLen,
+	}
+}
+
+// MinID 返回一个裁剪选项，用于删除 stream 中所有 ID 小于 id 的 entry。
+// 详见 https://redis.io/commands/xtrim。
+func MinID(id string) TrimOption {
+	return TrimOption{
+		t:   trimOptionMinID,
+		opt: id,
+	}
+}
+
+// MinIDApprox 返回一个裁剪选项，用于大致删除 stream 中所有 ID 小于 id 的 entry，即 MINID ~ id。
+// 详见 https://redis.io/commands/xtrim。
+func MinIDApprox(id string) TrimOption {
+	return TrimOption{
+		t:   trimOptionMinIDApprox,
+		opt: id,
+	}
+}
+
+// Args 返回用于拼接 Redis 命令的参数。
+func (to *TrimOption) Args() []interface{} {
+	switch to.t {
+	case trimOptionMaxLen:
+		return []interface{}{"MAXLEN", to.opt}
+	case trimOptionMaxLenApprox:
+		return []interface{}{"MAXLEN", "~", to.opt}
+	case trimOptionMinID:
+		return []interface{}{"MINID", to.opt}
+	case trimOptionMinIDApprox:
+		return []interface{}{"MINID", "~", to.opt}
+	}
+
+	return nil
+}
+
+type trimOptionType int
+
+const (
+	trimOptionInvalid trimOptionType = iota
+	trimOptionMaxLen
+	trimOptionMaxLenApprox
+	trimOptionMinID
+	trimOptionMinIDApprox
+)
+
+// StreamReadOption 代表读取 stream 时的选项，用于 XREAD。
+type StreamReadOption struct {
+	t   streamReadOptionType
+	opt interface{}
+}
+
+// ReadCount 返回一个读取 stream 的选项，用于限制每个 stream 最多返回 count 个 entry。
+// 详见 https://redis.io/commands/xread。
+func ReadCount(count int) StreamReadOption {
+	return StreamReadOption{
+		t:   streamReadOptionCount,
+		opt: count,
+	}
+}
+
+// Block 返回一个读取 stream 的选项，用于在没有数据时阻塞等待最多 timeout 时间，timeout 为 0 代表一直等待。
+// 阻塞期间如果 Redis 连接的 context 被取消或者超时，读取会提前返回。
+// 详见 https://redis.io/commands/xread。
+func Block(timeout time.Duration) StreamReadOption {
+	return StreamReadOption{
+		t:   streamReadOptionBlock,
+		opt: timeout,
+	}
+}
+
+// NoAck 返回一个读取 stream 的选项，用于 XREADGROUP 读取的 entry 无需 XACK 确认。
+// 这个选项只对 XREADGROUP 有效。
+// 详见 https://redis.io/commands/xreadgroup。
+func NoAck() StreamReadOption {
+	return StreamReadOption{
+		t: streamReadOptionNoAck,
+	}
+}
+
+type streamReadOptionType int
+
+const (
+	streamReadOptionInvalid streamReadOptionType = iota
+	streamReadOptionCount
+	streamReadOptionBlock
+	streamReadOptionNoAck
+)
+
+// PendingOption 代表 XPENDING 扩展形式的过滤选项。
+type PendingOption struct {
+	t   pendingOptionType
+	opt interface{}
+}
+
+// MinIdle 返回一个 XPENDING 选项，只返回闲置时间至少为 idle 的 entry。
+// 详见 https://redis.io/commands/xpending。
+func MinIdle(idle time.Duration) PendingOption {
+	return PendingOption{
+		t:   pendingOptionMinIdle,
+		opt: idle,
+	}
+}
+
+// ByConsumer 返回一个 XPENDING 选项，只返回属于 consumer 的 entry。
+// 详见 https://redis.io/commands/xpending。
+func ByConsumer(consumer string) PendingOption {
+	return PendingOption{
+		t:   pendingOptionConsumer,
+		opt: consumer,
+	}
+}
+
+type pendingOptionType int
+
+const (
+	pendingOptionInvalid pendingOptionType = iota
+	pendingOptionMinIdle
+	pendingOptionConsumer
+)
+
+// GeoOption 代表 GEORADIUS 和 GEOSEARCH 等 GEO 查询的选项。
+type GeoOption struct {
+	t   geoOptionType
+	opt interface{}
+}
+
+// WithCoord 返回一个 GEO 查询选项，用于在结果中包含 member 的坐标。
+func WithCoord() GeoOption {
+	return GeoOption{
+		t: geoOptionWithCoord,
+	}
+}
+
+// WithDist 返回一个 GEO 查询选项，用于在结果中包含 member 离中心点的距离。
+func WithDist() GeoOption {
+	return GeoOption{
+		t: geoOptionWithDist,
+	}
+}
+
+// WithHash 返回一个 GEO 查询选项，用于在结果中包含 member 坐标的 geohash。
+func WithHash() GeoOption {
+	return GeoOption{
+		t: geoOptionWithHash,
+	}
+}
+
+// GeoCount 返回一个 GEO 查询选项，用于限制最多返回 count 个结果。
+func GeoCount(count int) GeoOption {
+	return GeoOption{
+		t:   geoOptionCount,
+		opt: count,
+	}
+}
+
+// GeoCountAny 返回一个 GEO 查询选项，和 GeoCount 类似，但只要找到 count 个结果就返回，结果不一定是最近的。
+// 这个选项需要 Redis 6.2 及以上版本。
+func GeoCountAny(count int) GeoOption {
+	return GeoOption{
+		t:   geoOptionCountAny,
+		opt: count,
+	}
+}
+
+// GeoAsc 返回一个 GEO 查询选项，结果按照离中心点由近到远排序。
+func GeoAsc() GeoOption {
+	return GeoOption{
+		t: geoOptionAsc,
+	}
+}
+
+// GeoDesc 返回一个 GEO 查询选项，结果按照离中心点由远到近排序。
+func GeoDesc() GeoOption {
+	return GeoOption{
+		t: geoOptionDesc,
+	}
+}
+
+// StoreDist 返回一个 GEO 查询选项，用于在 GeoSearchStore 中保存距离而不是 geohash。
+func StoreDist() GeoOption {
+	return GeoOption{
+		t: geoOptionStoreDist,
+	}
+}
+
+// Args 返回用于拼接 Redis 命令的参数。
+func (gopt *GeoOption) Args() []interface{} {
+	switch gopt.t {
+	case geoOptionWithCoord:
+		return []interface{}{"WITHCOORD"}
+	case geoOptionWithDist:
+		return []interface{}{"WITHDIST"}
+	case geoOptionWithHash:
+		return []interface{}{"WITHHASH"}
+	case geoOptionCount:
+		return []interface{}{"COUNT", gopt.opt}
+	case geoOptionCountAny:
+		return []interface{}{"COUNT", gopt.opt, "ANY"}
+	case geoOptionAsc:
+		return []interface{}{"ASC"}
+	case geoOptionDesc:
+		return []interface{}{"DESC"}
+	case geoOptionStoreDist:
+		return []interface{}{"STOREDIST"}
+	}
+
+	return nil
+}
+
+type geoOptionType int
+
+const (
+	geoOptionInvalid geoOptionType = iota
+	geoOptionWithCoord
+	geoOptionWithDist
+	geoOptionWithHash
+	geoOptionCount
+	geoOptionCountAny
+	geoOptionAsc
+	geoOptionDesc
+	geoOptionStoreDist
+)
+
+// GeoCenter 代表 GEOSEARCH 查询的中心点。
+type GeoCenter struct {
+	member string
+	pos    *GeoPos
+}
+
+// FromMember 返回一个以 member 为中心点的 GEOSEARCH 查询。
+func FromMember(member string) GeoCenter {
+	return GeoCenter{
+		member: member,
+	}
+}
+
+// FromLonLat 返回一个以指定经纬度为中心点的 GEOSEARCH 查询。
+func FromLonLat(longitude, latitude float64) GeoCenter {
+	return GeoCenter{
+		pos: &GeoPos{
+			Longitude: longitude,
+			Latitude:  latitude,
+		},
+	}
+}
+
+// Args 返回用于拼接 Redis 命令的参数。
+func (gc *GeoCenter) Args() []interface{} {
+	if gc.pos != nil {
+		return []interface{}{"FROMLONLAT", gc.pos.Longitude, gc.pos.Latitude}
+	}
+
+	return []interface{}{"FROMMEMBER", gc.member}
+}
+
+// GeoShape 代表 GEOSEARCH 查询的范围。
+type GeoShape struct {
+	radius float64
+	width  float64
+	height float64
+	unit   GeoUnit
+}
+
+// ByRadius 返回一个半径为 radius 的圆形查询范围。
+func ByRadius(radius float64, unit GeoUnit) GeoShape {
+	return GeoShape{
+		radius: radius,
+		unit:   unit,
+	}
+}
+
+// ByBox 返回一个宽为 width、高为 height 的矩形查询范围。
+func ByBox(width, height float64, unit GeoUnit) GeoShape {
+	return GeoShape{
+		width:  width,
+		height: height,
+		unit:   unit,
+	}
+}
+
+// Args 返回用于拼接 Redis 命令的参数。
+func (gs *GeoShape) Args() []interface{} {
+	if gs.width != 0 || gs.height != 0 {
+		return []interface{}{"BYBOX", gs.width, gs.height, string(gs.unit)}
+	}
+
+	return []interface{}{"BYRADIUS", gs.radius, string(gs.unit)}
+}
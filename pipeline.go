@@ -0,0 +1,63 @@
+package redis
+
+import (
+	"github.com/altstory/go-redis/internal/driver"
+)
+
+// Pipelining 代表 Redis pipeline 相关的接口，详见 https://redis.io/topics/pipelining。
+type Pipelining interface {
+	// Pipeline 将 fn 里面调用的所有命令一次性发给 Redis，减少网络往返次数。
+	//
+	// 与 Multi 类似，fn 里面的 p 上调用的所有命令都只会被缓存起来，返回的 err 是一个 *FutureMultiValue，
+	// 可以在 Pipeline 返回后用 MakeMultiValue 拿到结果，也可以直接使用按顺序保存了每个命令结果的 mvs。
+	// 与 Multi 不同的是，pipeline 里的命令并不保证原子性。
+	//
+	// 如果 fn 返回了 error，所有缓存的命令都不会被执行，Pipeline 返回这个错误。
+	Pipeline(fn func(p Redis) error) (mvs []MultiValue, err error)
+}
+
+func (r *redisImpl) Pipeline(fn func(p Redis) error) (mvs []MultiValue, err error) {
+	if isPipelined(r.client) {
+		err = ErrNestedTransaction
+		return
+	}
+
+	err = r.do("PIPELINE", func(client driver.Client) error {
+		pipe := &pipeliner{}
+
+		if e := fn(newRedis(r.ctx, pipe)); e != nil && !isFutureMultiValue(e) {
+			return e
+		}
+
+		mvs, err = parsePipelinedReply(pipe.cmds, client.ProcessPipeline(pipe.cmds))
+		return err
+	})
+	return
+}
+
+// pipeliner 是在 transaction 或 pipeline 的回调里使用的 driver.Client，
+// 它只会把命令缓存起来，等回调返回之后再由 Multi 或 Pipeline 一次性执行。
+type pipeliner struct {
+	cmds []driver.Cmder
+}
+
+var _ driver.Client = new(pipeliner)
+
+func (p *pipeliner) Process(cmd driver.Cmder) error {
+	p.cmds = append(p.cmds, cmd)
+	return nil
+}
+
+func (p *pipeliner) ProcessPipeline(cmds []driver.Cmder) error {
+	p.cmds = append(p.cmds, cmds...)
+	return nil
+}
+
+func (p *pipeliner) ProcessTxPipeline(cmds []driver.Cmder) error {
+	p.cmds = append(p.cmds, cmds...)
+	return nil
+}
+
+func (p *pipeliner) Close() error {
+	return nil
+}
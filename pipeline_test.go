@@ -0,0 +1,53 @@
+package redis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+func TestPipeline(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	f := factory(t)
+	r := f.New(ctx)
+	resetRedis(t, r)
+
+	hashKey := "pipeline-hash"
+	zsetKey := "pipeline-zset"
+
+	a.NilError(r.HMSet(hashKey, MakeKeyAndValue("f1", "v1"), MakeKeyAndValue("f2", "v2")))
+	_, err := r.ZAdd(zsetKey, MakeMemberAndScore("m1", 1.5))
+	a.NilError(err)
+
+	var futureScore error
+	mvs, err := r.Pipeline(func(p Redis) error {
+		p.HGet(hashKey, "f1")
+		p.HGet(hashKey, "f3")
+		_, _, futureScore = p.ZScore(zsetKey, "m1")
+		return nil
+	})
+	a.NilError(err)
+	a.Equal(len(mvs), 3)
+
+	v, ok := mvs[0].BulkString()
+	a.Assert(ok)
+	a.Equal(v.String(), "v1")
+
+	v, ok = mvs[1].BulkString()
+	a.Assert(ok)
+	a.Assert(v.IsNull())
+
+	score, ok := MakeMultiValue(futureScore).Float64()
+	a.Assert(ok)
+	a.Equal(score, 1.5)
+
+	_, err = r.Pipeline(func(p Redis) error {
+		_, err := p.Pipeline(func(Redis) error {
+			return nil
+		})
+		return err
+	})
+	a.Equal(err, ErrNestedTransaction)
+}
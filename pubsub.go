@@ -1,6 +1,238 @@
 package redis
 
+import (
+	"errors"
+	"sync"
+
+	"github.com/altstory/go-redis/internal/driver"
+)
+
+const (
+	// DefaultSubscriptionBufferSize 是 Subscription 消息 channel 的缓冲区大小。
+	DefaultSubscriptionBufferSize = 100
+)
+
+var (
+	// ErrSubscribeNotSupported 表示在 transaction 或 pipeline 的回调里调用了 Subscribe 或 PSubscribe。
+	ErrSubscribeNotSupported = errors.New("go-redis: subscribe is not supported in transaction or pipeline")
+
+	// ErrSubscriptionClosed 表示 Subscription 已经被关闭。
+	ErrSubscriptionClosed = errors.New("go-redis: subscription is closed")
+)
+
 // PubSub 代表 Redis 跟 pub/sub 相关的接口，详见 https://redis.io/commands#pubsub。
 type PubSub interface {
-	// TODO:
+	PSubscribe(patterns ...string) (sub *Subscription, err error)
+	Publish(channel string, message interface{}) (received int, err error)
+	PubSubChannels(pattern string) (channels []string, err error) // PUBSUB CHANNELS pattern
+	PubSubNumPat() (n int, err error)                             // PUBSUB NUMPAT
+	PubSubNumSub(channels ...string) (css ChanAndSubs, err error) // PUBSUB NUMSUB channel [channel ...]
+	Subscribe(channels ...string) (sub *Subscription, err error)
+}
+
+// subscriber 是支持 SUBSCRIBE 的 driver.Client，在 transaction 和 pipeline 里的 client 不支持 SUBSCRIBE。
+type subscriber interface {
+	PubSub() *driver.PubSub
+}
+
+func (r *redisImpl) PSubscribe(patterns ...string) (sub *Subscription, err error) {
+	s, ok := r.client.(subscriber)
+
+	if !ok {
+		err = ErrSubscribeNotSupported
+		return
+	}
+
+	err = r.do("PSUBSCRIBE", func(client driver.Client) error {
+		ps := s.PubSub()
+
+		if len(patterns) != 0 {
+			if err := ps.PSubscribe(patterns...); err != nil {
+				ps.Close()
+				return err
+			}
+		}
+
+		sub = newSubscription(ps)
+		return nil
+	})
+	return
+}
+
+func (r *redisImpl) Publish(channel string, message interface{}) (received int, err error) {
+	err = r.do("PUBLISH", func(client driver.Client) error {
+		received, err = mustBeInt(client, process(client, newCmd("PUBLISH", channel, message)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) PubSubChannels(pattern string) (channels []string, err error) {
+	err = r.do("PUBSUB CHANNELS", func(client driver.Client) error {
+		args := []interface{}{"PUBSUB", "CHANNELS"}
+
+		if pattern != "" {
+			args = append(args, pattern)
+		}
+
+		channels, err = mustBeStrings(client, process(client, newCmd(args...)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) PubSubNumPat() (n int, err error) {
+	err = r.do("PUBSUB NUMPAT", func(client driver.Client) error {
+		n, err = mustBeInt(client, process(client, newCmd("PUBSUB", "NUMPAT")))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) PubSubNumSub(channels ...string) (css ChanAndSubs, err error) {
+	err = r.do("PUBSUB NUMSUB", func(client driver.Client) error {
+		args := appendArgs([]interface{}{"PUBSUB", "NUMSUB"}, channels)
+		css, err = mustBeChanAndSubs(client, process(client, newCmdWith(parseChanAndSubs, args...)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) Subscribe(channels ...string) (sub *Subscription, err error) {
+	s, ok := r.client.(subscriber)
+
+	if !ok {
+		err = ErrSubscribeNotSupported
+		return
+	}
+
+	err = r.do("SUBSCRIBE", func(client driver.Client) error {
+		ps := s.PubSub()
+
+		if len(channels) != 0 {
+			if err := ps.Subscribe(channels...); err != nil {
+				ps.Close()
+				return err
+			}
+		}
+
+		sub = newSubscription(ps)
+		return nil
+	})
+	return
+}
+
+// Message 代表一条通过 pub/sub 收到的消息。
+type Message struct {
+	Channel string // Channel 是消息发布的 channel。
+	Pattern string // Pattern 是匹配上 Channel 的模式，只有通过 PSubscribe 收到的消息才会设置。
+	Payload string // Payload 是消息内容。
+}
+
+// Subscription 代表一个 pub/sub 订阅，收到的消息会通过 Messages 返回的 channel 发出。
+//
+// Subscription 独占一个 Redis 连接，断线之后会自动重连并重新订阅所有 channel 和 pattern。
+// 使用完毕后必须调用 Close 释放连接。
+type Subscription struct {
+	ps       *driver.PubSub
+	messages chan Message
+
+	mu     sync.Mutex
+	closed bool
+	done   chan struct{}
+}
+
+func newSubscription(ps *driver.PubSub) *Subscription {
+	sub := &Subscription{
+		ps:       ps,
+		messages: make(chan Message, DefaultSubscriptionBufferSize),
+		done:     make(chan struct{}),
+	}
+	go sub.forward(ps.Channel(DefaultSubscriptionBufferSize))
+	return sub
+}
+
+func (sub *Subscription) forward(ch <-chan *driver.Message) {
+	defer close(sub.messages)
+
+	for msg := range ch {
+		select {
+		case sub.messages <- Message{
+			Channel: msg.Channel,
+			Pattern: msg.Pattern,
+			Payload: msg.Payload,
+		}:
+		case <-sub.done:
+			return
+		}
+	}
+}
+
+// Messages 返回接收消息的 channel，Subscription 关闭之后这个 channel 也会被关闭。
+func (sub *Subscription) Messages() <-chan Message {
+	return sub.messages
+}
+
+// Subscribe 增加订阅的 channel，channels 为空时什么都不做。
+func (sub *Subscription) Subscribe(channels ...string) error {
+	if sub.isClosed() {
+		return ErrSubscriptionClosed
+	}
+
+	if len(channels) == 0 {
+		return nil
+	}
+
+	return sub.ps.Subscribe(channels...)
+}
+
+// PSubscribe 增加订阅的 pattern，patterns 为空时什么都不做。
+func (sub *Subscription) PSubscribe(patterns ...string) error {
+	if sub.isClosed() {
+		return ErrSubscriptionClosed
+	}
+
+	if len(patterns) == 0 {
+		return nil
+	}
+
+	return sub.ps.PSubscribe(patterns...)
+}
+
+// Unsubscribe 取消订阅 channel，如果 channels 为空则取消订阅所有 channel。
+func (sub *Subscription) Unsubscribe(channels ...string) error {
+	if sub.isClosed() {
+		return ErrSubscriptionClosed
+	}
+
+	return sub.ps.Unsubscribe(channels...)
+}
+
+// PUnsubscribe 取消订阅 pattern，如果 patterns 为空则取消订阅所有 pattern。
+func (sub *Subscription) PUnsubscribe(patterns ...string) error {
+	if sub.isClosed() {
+		return ErrSubscriptionClosed
+	}
+
+	return sub.ps.PUnsubscribe(patterns...)
+}
+
+// Close 关闭订阅并释放连接，重复调用是安全的。
+func (sub *Subscription) Close() error {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+
+	if sub.closed {
+		return nil
+	}
+
+	sub.closed = true
+	close(sub.done)
+	return sub.ps.Close()
+}
+
+func (sub *Subscription) isClosed() bool {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	return sub.closed
 }
@@ -0,0 +1,91 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/huandu/go-assert"
+)
+
+func TestPubSub(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	f := factory(t)
+	r := f.New(ctx)
+	resetRedis(t, r)
+
+	sub, err := r.Subscribe("pubsub-a")
+	a.NilError(err)
+	defer sub.Close()
+	a.NilError(sub.PSubscribe("pubsub-p*"))
+
+	// 没有参数时不会发送 SUBSCRIBE 或 PSUBSCRIBE。
+	a.NilError(sub.Subscribe())
+	a.NilError(sub.PSubscribe())
+
+	receive := func() Message {
+		select {
+		case msg := <-sub.Messages():
+			return msg
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no message is received")
+		}
+
+		return Message{}
+	}
+
+	// 订阅是异步生效的，用 PUBSUB NUMSUB 等待订阅完成。
+	for i := 0; i < 100; i++ {
+		css, err := r.PubSubNumSub("pubsub-a", "pubsub-none")
+		a.NilError(err)
+		a.Equal(len(css), 2)
+		a.Equal(css[1], MakeChanAndSub("pubsub-none", 0))
+
+		if css[0].Sub == 1 {
+			break
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	n, err := r.PubSubNumPat()
+	a.NilError(err)
+	a.Equal(n, 1)
+
+	channels, err := r.PubSubChannels("pubsub-*")
+	a.NilError(err)
+	a.Equal(channels, []string{"pubsub-a"})
+
+	received, err := r.Publish("pubsub-a", "hello")
+	a.NilError(err)
+	a.Equal(received, 1)
+	a.Equal(receive(), Message{Channel: "pubsub-a", Payload: "hello"})
+
+	_, err = r.Publish("pubsub-p1", 123)
+	a.NilError(err)
+	a.Equal(receive(), Message{Channel: "pubsub-p1", Pattern: "pubsub-p*", Payload: "123"})
+
+	a.NilError(sub.Subscribe("pubsub-b"))
+	a.NilError(sub.Unsubscribe("pubsub-a"))
+	a.NilError(sub.PUnsubscribe())
+	_, err = r.Publish("pubsub-a", "ignored")
+	a.NilError(err)
+	_, err = r.Publish("pubsub-b", "world")
+	a.NilError(err)
+	a.Equal(receive(), Message{Channel: "pubsub-b", Payload: "world"})
+
+	_, err = r.Pipeline(func(p Redis) error {
+		_, err := p.Subscribe("pubsub-a")
+		a.Equal(err, ErrSubscribeNotSupported)
+		return nil
+	})
+	a.NilError(err)
+
+	a.NilError(sub.Close())
+	a.NilError(sub.Close())
+	a.Equal(sub.Subscribe("pubsub-a"), ErrSubscriptionClosed)
+
+	_, ok := <-sub.Messages()
+	a.Assert(!ok)
+}
@@ -23,6 +23,7 @@ type Redis interface {
 	Hashes
 	HyperLogLog
 	Lists
+	Pipelining
 	PubSub
 	Scan
 	Scripting
@@ -40,6 +41,7 @@ type redisImpl struct {
 
 	ctx    context.Context
 	client driver.Client
+	cache  *clientCache // cache 是客户端缓存，在 transaction 和 pipeline 里总是 nil。
 }
 
 var _ Redis = new(redisImpl)
@@ -84,6 +86,11 @@ func (r *redisImpl) do(cmd string, fn func(client driver.Client) error) (err err
 			err = fmt.Errorf("go-redis: caught a panic in `%v`", cmd)
 		}
 
+		// 在 transaction 或 pipeline 里的命令只是被缓存起来，日志和统计由 Multi 等方法统一处理。
+		if isFutureMultiValue(err) {
+			return
+		}
+
 		if err == nil {
 			log.Tracef(ctx, "cmd=%v||proctime=%.6f||go-redis: success", cmd, proctime)
 		} else {
@@ -96,3 +103,65 @@ func (r *redisImpl) do(cmd string, fn func(client driver.Client) error) (err err
 	err = fn(r.client)
 	return
 }
+
+// blockTimeout 根据 r.ctx 的 deadline 调整阻塞命令的超时时间，保证阻塞不会超过 deadline。
+// timeout 为 0 代表一直阻塞。
+func (r *redisImpl) blockTimeout(timeout time.Duration) time.Duration {
+	if deadline, ok := r.ctx.Deadline(); ok {
+		if remaining := time.Until(deadline); timeout == 0 || timeout > remaining {
+			timeout = remaining
+		}
+	}
+
+	// Redis 阻塞命令的超时精度是毫秒，不足 1ms 的超时会被当做 0，也就是一直阻塞。
+	if timeout != 0 && timeout < time.Millisecond {
+		timeout = time.Millisecond
+	}
+
+	return timeout
+}
+
+// blockTimeoutSeconds 调整 BLPOP 等超时精度是秒的阻塞命令的超时时间。
+// 不足 1s 的 timeout 会向上取整到秒，避免被当做 0 从而一直阻塞；
+// 但调整后的超时不会超过 r.ctx 的 deadline，超过时向下取整，如果不足 1s 则返回 0，
+// 这时命令会一直阻塞，直到 r.ctx 到期时被 processBlocking 中断。
+func (r *redisImpl) blockTimeoutSeconds(timeout time.Duration) time.Duration {
+	if rem := timeout % time.Second; rem != 0 {
+		timeout += time.Second - rem
+	}
+
+	if deadline, ok := r.ctx.Deadline(); ok {
+		if remaining := time.Until(deadline); timeout == 0 || timeout > remaining {
+			timeout = remaining - remaining%time.Second
+		}
+
+		if timeout < 0 {
+			timeout = 0
+		}
+	}
+
+	return timeout
+}
+
+// processBlocking 执行阻塞命令 cmd，如果 r.ctx 在命令返回前被取消，命令会被中断并返回 r.ctx.Err()。
+// 被中断的连接会从连接池中删除，服务器会在连接断开时取消阻塞，不会再弹出任何数据。
+func (r *redisImpl) processBlocking(client driver.Client, cmd *command) (cmder driver.Cmder, err error) {
+	if isPipelined(client) {
+		cmder = process(client, cmd)
+		return
+	}
+
+	if err = r.ctx.Err(); err != nil {
+		return
+	}
+
+	cmd.SetInterrupt(r.ctx.Done())
+	cmder = process(client, cmd)
+
+	// 应答已经读到的话，即使 r.ctx 已经被取消也要返回结果，否则弹出的数据会丢失。
+	if cmder.Err() != nil && r.ctx.Err() != nil {
+		err = r.ctx.Err()
+	}
+
+	return
+}
@@ -0,0 +1,110 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/huandu/go-assert"
+)
+
+func TestRingMethods(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	resetRedis(t, factory(t).New(ctx))
+	f := NewFactory(&Config{
+		Ring: &RingConfig{
+			Addrs: map[string]string{
+				"shard1": testAddr,
+				"shard2": testAddr,
+			},
+			Weights: map[string]int{
+				"shard2": 2,
+			},
+		},
+	})
+
+	if err := f.Conn(ctx); err != nil {
+		t.Skipf("fail to connect Redis server. [err:%v]", err)
+	}
+
+	defer f.Close()
+	r := f.New(ctx)
+
+	for i := 0; i < 10; i++ {
+		key := fmt.Sprintf("ring-%v", i)
+		_, err := r.Set(key, "v", Expire(time.Minute))
+		a.NilError(err)
+		v, err := r.Get(key)
+		a.NilError(err)
+		a.Equal(v.String(), "v")
+	}
+
+	mvs, err := r.Multi(func(tx Redis) error {
+		tx.Incr("{ring}-counter")
+		_, err := tx.Incr("{ring}-counter")
+		return err
+	})
+	a.NilError(err)
+	a.Equal(len(mvs), 2)
+
+	var keys []string
+	it := r.ScanIter(Match("ring-*"))
+
+	for it.Next() {
+		keys = append(keys, it.Key())
+	}
+
+	a.NilError(it.Err())
+
+	// 两个 shard 连接的是同一个服务器，重复的 key 只会返回一次。
+	a.Equal(len(keys), 10)
+}
+
+func TestRingHealthCheck(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	f := NewFactory(&Config{
+		Ring: &RingConfig{
+			Addrs: map[string]string{
+				"alive": testAddr,
+				"dead":  "127.0.0.1:1",
+			},
+			HeartbeatFrequency: 10 * time.Millisecond,
+			DialTimeout:        10 * time.Millisecond,
+		},
+	})
+	defer f.Close()
+
+	if err := factory(t).Conn(ctx); err != nil {
+		t.Skipf("fail to connect Redis server. [err:%v]", err)
+	}
+
+	// 连接失败的 shard 被摘除之后，所有的 key 都会分配到可用的 shard 上。
+	deadline := time.Now().Add(2 * time.Second)
+
+	for {
+		if err := f.Conn(ctx); err == nil {
+			r := f.New(ctx)
+			failed := false
+
+			for i := 0; i < 10; i++ {
+				if _, err := r.Set(fmt.Sprintf("ring-health-%v", i), "v", Expire(time.Minute)); err != nil {
+					failed = true
+				}
+			}
+
+			if !failed {
+				break
+			}
+		}
+
+		if time.Now().After(deadline) {
+			a.Assert(false)
+			return
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -1,10 +1,111 @@
 package redis
 
+import (
+	"strconv"
+
+	"github.com/altstory/go-redis/internal/driver"
+)
+
 // Scan 代表所有跟扫描键值相关的接口，详见 https://redis.io/commands/scan。
+//
+// 第一次扫描时 cursor 应该设置为 0，之后使用上一次返回的 nextCursor 继续扫描，
+// 直到 nextCursor 为 0 代表扫描结束。同一个元素可能会被返回多次，调用者需要自行去重。
+//
+// 各种 Iter 方法返回的迭代器会自动处理 cursor 并且去重，在 cluster 模式下 ScanIter 会遍历所有 master 结点。
+// 去重需要在内存中记录所有返回过的元素，遍历大量元素时可以使用 NoDedup 选项关闭去重。
 type Scan interface {
-	// TODO:
-	// Scan(cursor int64, options ...ScanOption) (nextCursor int64, keys []string, err error)
-	// SScan(key string, cursor int64, options ...ScanOption) (nextCursor int64, values []string, err error)
-	// HScan(key string, cursor int64, options ...ScanOption) (nextCursor int64, fieldAndValues KeyAndValues, err error)
-	// ZScan(key string, cursor int64, options ...ScanOption) (nextCursor int64, mss MemberAndScores, err error)
+	Scan(cursor int64, options ...ScanOption) (nextCursor int64, keys []string, err error)
+	ScanIter(options ...ScanOption) *KeyIterator
+	SScan(key string, cursor int64, options ...ScanOption) (nextCursor int64, values []string, err error)
+	SScanIter(key string, options ...ScanOption) *MemberIterator
+	HScan(key string, cursor int64, options ...ScanOption) (nextCursor int64, fieldAndValues KeyAndValues, err error)
+	HScanIter(key string, options ...ScanOption) *KeyAndValueIterator
+	ZScan(key string, cursor int64, options ...ScanOption) (nextCursor int64, mss MemberAndScores, err error)
+	ZScanIter(key string, options ...ScanOption) *MemberAndScoreIterator
+}
+
+func (r *redisImpl) Scan(cursor int64, options ...ScanOption) (nextCursor int64, keys []string, err error) {
+	err = r.do("SCAN", func(client driver.Client) error {
+		nextCursor, keys, err = r.scan(client, makeScanArgs(cursor, options, "SCAN"))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) SScan(key string, cursor int64, options ...ScanOption) (nextCursor int64, values []string, err error) {
+	err = r.do("SSCAN", func(client driver.Client) error {
+		nextCursor, values, err = r.scan(client, makeScanArgs(cursor, options, "SSCAN", key))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) HScan(key string, cursor int64, options ...ScanOption) (nextCursor int64, fieldAndValues KeyAndValues, err error) {
+	err = r.do("HSCAN", func(client driver.Client) error {
+		var values []string
+		nextCursor, values, err = r.scan(client, makeScanArgs(cursor, options, "HSCAN", key))
+
+		if err != nil {
+			return err
+		}
+
+		if len(values)%2 != 0 {
+			panic(ErrUnexpectedResponseType)
+		}
+
+		fieldAndValues = make(KeyAndValues, 0, len(values)/2)
+
+		for i := 0; i < len(values); i += 2 {
+			fieldAndValues = append(fieldAndValues, MakeKeyAndValue(values[i], values[i+1]))
+		}
+
+		return nil
+	})
+	return
+}
+
+func (r *redisImpl) ZScan(key string, cursor int64, options ...ScanOption) (nextCursor int64, mss MemberAndScores, err error) {
+	err = r.do("ZSCAN", func(client driver.Client) error {
+		var values []string
+		nextCursor, values, err = r.scan(client, makeScanArgs(cursor, options, "ZSCAN", key))
+
+		if err != nil {
+			return err
+		}
+
+		if len(values)%2 != 0 {
+			panic(ErrUnexpectedResponseType)
+		}
+
+		mss = make(MemberAndScores, 0, len(values)/2)
+
+		for i := 0; i < len(values); i += 2 {
+			score, e := strconv.ParseFloat(values[i+1], 64)
+
+			if e != nil {
+				panic(ErrUnexpectedResponseType)
+			}
+
+			mss = append(mss, MakeMemberAndScore(values[i], score))
+		}
+
+		return nil
+	})
+	return
+}
+
+func (r *redisImpl) scan(client driver.Client, args []interface{}) (cursor int64, values []string, err error) {
+	return mustBeScanned(client, process(client, newCmdWith(parseScanned, args...)))
+}
+
+func makeScanArgs(cursor int64, options []ScanOption, cmd ...interface{}) []interface{} {
+	args := make([]interface{}, 0, len(cmd)+1+2*len(options))
+	args = append(args, cmd...)
+	args = append(args, cursor)
+
+	for _, opt := range options {
+		args = append(args, opt.Args()...)
+	}
+
+	return args
 }
@@ -0,0 +1,213 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+func TestScanMethods(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	f := factory(t)
+	r := f.New(ctx)
+	resetRedis(t, r)
+
+	var keys []string
+
+	for i := 0; i < 20; i++ {
+		key := fmt.Sprintf("scan-key-%v", i)
+		keys = append(keys, key)
+		a.NilError(r.Set(key, "v"))
+	}
+
+	_, err := r.SAdd("scan-set", "a", "b", "c")
+	a.NilError(err)
+
+	scanAll := func(options ...ScanOption) []string {
+		var all []string
+		cursor := int64(0)
+		seen := map[string]bool{}
+
+		for {
+			next, keys, err := r.Scan(cursor, options...)
+			a.NilError(err)
+
+			for _, k := range keys {
+				if !seen[k] {
+					seen[k] = true
+					all = append(all, k)
+				}
+			}
+
+			if cursor = next; cursor == 0 {
+				break
+			}
+		}
+
+		sort.Strings(all)
+		return all
+	}
+
+	sort.Strings(keys)
+	a.Equal(scanAll(Match("scan-key-*"), Count(5)), keys)
+	a.Equal(scanAll(ScanType(TypeSet)), []string{"scan-set"})
+
+	next, values, err := r.SScan("scan-set", 0)
+	a.NilError(err)
+	a.Equal(next, int64(0))
+	sort.Strings(values)
+	a.Equal(values, []string{"a", "b", "c"})
+
+	_, err = r.HSet("scan-hash", "f1", "v1")
+	a.NilError(err)
+	next, fieldAndValues, err := r.HScan("scan-hash", 0, Match("f*"))
+	a.NilError(err)
+	a.Equal(next, int64(0))
+	a.Equal(fieldAndValues, KeyAndValues{MakeKeyAndValue("f1", "v1")})
+
+	_, err = r.ZAdd("scan-zset", MakeMemberAndScore("m1", 1.5))
+	a.NilError(err)
+	next, mss, err := r.ZScan("scan-zset", 0)
+	a.NilError(err)
+	a.Equal(next, int64(0))
+	a.Equal(mss, MemberAndScores{MakeMemberAndScore("m1", 1.5)})
+
+	var future error
+	_, err = r.Pipeline(func(p Redis) error {
+		_, _, future = p.SScan("scan-set", 0, Count(10))
+		return nil
+	})
+	a.NilError(err)
+	mvs, ok := MakeMultiValue(future).MultiValues()
+	a.Assert(ok)
+	a.Equal(len(mvs), 2)
+	cursor, ok := mvs[0].Int64()
+	a.Assert(ok)
+	a.Equal(cursor, int64(0))
+}
+
+func TestScanIterators(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	f := factory(t)
+	r := f.New(ctx)
+	resetRedis(t, r)
+
+	var keys []string
+	var members []string
+
+	for i := 0; i < 50; i++ {
+		key := fmt.Sprintf("iter-key-%v", i)
+		keys = append(keys, key)
+		a.NilError(r.Set(key, "v"))
+		members = append(members, fmt.Sprint(i))
+	}
+
+	_, err := r.SAdd("iter-set", members...)
+	a.NilError(err)
+
+	var scanned []string
+	it := r.ScanIter(Match("iter-key-*"), Count(7))
+
+	for it.Next() {
+		scanned = append(scanned, it.Key())
+	}
+
+	a.NilError(it.Err())
+	sort.Strings(keys)
+	sort.Strings(scanned)
+	a.Equal(scanned, keys)
+
+	// NoDedup 不会发送给 Redis，数据没有变化时同样能遍历所有 key。
+	scanned = nil
+	it = r.ScanIter(Match("iter-key-*"), NoDedup())
+
+	for it.Next() {
+		scanned = append(scanned, it.Key())
+	}
+
+	a.NilError(it.Err())
+	sort.Strings(scanned)
+	a.Equal(scanned, keys)
+
+	scanned = nil
+	mi := r.SScanIter("iter-set", Count(7))
+
+	for mi.Next() {
+		scanned = append(scanned, mi.Member())
+	}
+
+	a.NilError(mi.Err())
+	sort.Strings(members)
+	sort.Strings(scanned)
+	a.Equal(scanned, members)
+
+	_, err = r.HSet("iter-hash", "f", "v")
+	a.NilError(err)
+	kvi := r.HScanIter("iter-hash")
+	a.Assert(kvi.Next())
+	a.Equal(kvi.KeyAndValue(), MakeKeyAndValue("f", "v"))
+	a.Assert(!kvi.Next())
+	a.NilError(kvi.Err())
+
+	_, err = r.ZAdd("iter-zset", MakeMemberAndScore("m", 2))
+	a.NilError(err)
+	msi := r.ZScanIter("iter-zset")
+	a.Assert(msi.Next())
+	a.Equal(msi.MemberAndScore(), MakeMemberAndScore("m", 2))
+	a.Assert(!msi.Next())
+	a.NilError(msi.Err())
+
+	// 遍历到了错误类型的 key 需要返回错误。
+	kvi = r.HScanIter("iter-set")
+	a.Assert(!kvi.Next())
+	a.Assert(kvi.Err() != nil)
+
+	_, err = r.Pipeline(func(p Redis) error {
+		it := p.ScanIter()
+		a.Assert(!it.Next())
+		a.Equal(it.Err(), ErrNestedTransaction)
+		return nil
+	})
+	a.NilError(err)
+}
+
+func TestScanIteratorDedup(t *testing.T) {
+	a := assert.New(t)
+	pages := [][]interface{}{
+		{"a", "b"},
+		{"b", "c"},
+		{"a", "d"},
+	}
+	iterate := func(options ...ScanOption) []string {
+		it := newScanIterator(options, func(cursor int64) (next int64, elems []interface{}, err error) {
+			elems = pages[cursor]
+
+			if next = cursor + 1; int(next) == len(pages) {
+				next = 0
+			}
+
+			return
+		}, func(cursor int64) (next int64, elems []interface{}, err error) {
+			return 0, []interface{}{"c", "e"}, nil
+		})
+
+		var elems []string
+
+		for it.next() {
+			elems = append(elems, it.elem.(string))
+		}
+
+		a.NilError(it.err)
+		return elems
+	}
+
+	a.Equal(iterate(), []string{"a", "b", "c", "d", "e"})
+
+	// 关闭去重之后，所有元素都会按原样返回。
+	a.Equal(iterate(NoDedup(), Count(7)), []string{"a", "b", "b", "c", "a", "d", "c", "e"})
+}
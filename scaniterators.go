@@ -0,0 +1,283 @@
+package redis
+
+import (
+	"github.com/altstory/go-redis/internal/driver"
+)
+
+// scanPage 扫描一页数据，返回下一个 cursor 和扫描到的元素。
+type scanPage func(cursor int64) (next int64, elems []interface{}, err error)
+
+// scanIterator 是所有扫描迭代器的公共实现。
+//
+// 每个 scanPage 代表一个需要扫描的 Redis 结点，迭代器会依次将每个结点从 cursor 0 扫描到 cursor 0，
+// 并且会去掉重复的元素，所以迭代器需要在内存中记录所有已经返回过的元素，
+// 内存占用会随着遍历的元素数量一直增长。使用 NoDedup 选项时迭代器不去重，seen 为 nil。
+type scanIterator struct {
+	pages   []scanPage
+	cursor  int64
+	started bool
+
+	elems []interface{}
+	elem  interface{}
+	seen  map[string]struct{}
+	err   error
+}
+
+func newScanIterator(options []ScanOption, pages ...scanPage) scanIterator {
+	it := scanIterator{
+		pages: pages,
+		seen:  map[string]struct{}{},
+	}
+
+	for _, opt := range options {
+		if opt.t == scanOptionNoDedup {
+			it.seen = nil
+		}
+	}
+
+	return it
+}
+
+func (it *scanIterator) next() bool {
+	for it.err == nil {
+		if len(it.elems) != 0 {
+			it.elem = it.elems[0]
+			it.elems = it.elems[1:]
+
+			if it.seen == nil {
+				return true
+			}
+
+			key := scanElemKey(it.elem)
+
+			if _, ok := it.seen[key]; ok {
+				continue
+			}
+
+			it.seen[key] = struct{}{}
+			return true
+		}
+
+		if len(it.pages) == 0 {
+			break
+		}
+
+		// 当前结点已经扫描完毕，开始扫描下一个结点。
+		if it.started && it.cursor == 0 {
+			it.pages = it.pages[1:]
+			it.started = false
+			continue
+		}
+
+		it.cursor, it.elems, it.err = it.pages[0](it.cursor)
+		it.started = true
+	}
+
+	it.elem = nil
+	return false
+}
+
+func scanElemKey(elem interface{}) string {
+	switch e := elem.(type) {
+	case string:
+		return e
+	case KeyAndValue:
+		return e.Key
+	case MemberAndScore:
+		return e.Member
+	}
+
+	panic(ErrUnexpectedResponseType)
+}
+
+// KeyIterator 是用 SCAN 遍历所有 key 的迭代器，由 ScanIter 创建。
+//
+// 在 cluster 模式下，KeyIterator 会依次遍历每一个 master 结点。
+//
+// 为了去掉重复的 key，KeyIterator 会在内存中记录所有返回过的 key，内存占用与遍历的 key 数量成正比。
+// 遍历大量 key 时可以使用 NoDedup 选项关闭去重，其他迭代器也是一样。
+//
+// 使用方法：
+//
+//     it := r.ScanIter(redis.Match("foo:*"))
+//
+//     for it.Next() {
+//         key := it.Key()
+//         // 使用 key……
+//     }
+//
+//     if err := it.Err(); err != nil {
+//         // 处理错误……
+//     }
+type KeyIterator struct {
+	it scanIterator
+}
+
+// Next 移动到下一个 key，如果已经遍历完毕或者出错，返回 false。
+func (ki *KeyIterator) Next() bool {
+	return ki.it.next()
+}
+
+// Key 返回当前的 key。
+func (ki *KeyIterator) Key() string {
+	key, _ := ki.it.elem.(string)
+	return key
+}
+
+// Err 返回遍历过程中遇到的错误。
+func (ki *KeyIterator) Err() error {
+	return ki.it.err
+}
+
+// MemberIterator 是用 SSCAN 遍历 set 所有 member 的迭代器，由 SScanIter 创建。
+type MemberIterator struct {
+	it scanIterator
+}
+
+// Next 移动到下一个 member，如果已经遍历完毕或者出错，返回 false。
+func (mi *MemberIterator) Next() bool {
+	return mi.it.next()
+}
+
+// Member 返回当前的 member。
+func (mi *MemberIterator) Member() string {
+	member, _ := mi.it.elem.(string)
+	return member
+}
+
+// Err 返回遍历过程中遇到的错误。
+func (mi *MemberIterator) Err() error {
+	return mi.it.err
+}
+
+// KeyAndValueIterator 是用 HSCAN 遍历 hash 所有 field 和 value 的迭代器，由 HScanIter 创建。
+type KeyAndValueIterator struct {
+	it scanIterator
+}
+
+// Next 移动到下一个 field，如果已经遍历完毕或者出错，返回 false。
+func (kvi *KeyAndValueIterator) Next() bool {
+	return kvi.it.next()
+}
+
+// KeyAndValue 返回当前的 field 和 value。
+func (kvi *KeyAndValueIterator) KeyAndValue() KeyAndValue {
+	kv, _ := kvi.it.elem.(KeyAndValue)
+	return kv
+}
+
+// Err 返回遍历过程中遇到的错误。
+func (kvi *KeyAndValueIterator) Err() error {
+	return kvi.it.err
+}
+
+// MemberAndScoreIterator 是用 ZSCAN 遍历 sorted set 所有 member 和 score 的迭代器，由 ZScanIter 创建。
+type MemberAndScoreIterator struct {
+	it scanIterator
+}
+
+// Next 移动到下一个 member，如果已经遍历完毕或者出错，返回 false。
+func (msi *MemberAndScoreIterator) Next() bool {
+	return msi.it.next()
+}
+
+// MemberAndScore 返回当前的 member 和 score。
+func (msi *MemberAndScoreIterator) MemberAndScore() MemberAndScore {
+	ms, _ := msi.it.elem.(MemberAndScore)
+	return ms
+}
+
+// Err 返回遍历过程中遇到的错误。
+func (msi *MemberAndScoreIterator) Err() error {
+	return msi.it.err
+}
+
+// masterWalker 是支持遍历所有 master 结点的 driver.Client，一般是 cluster client。
+type masterWalker interface {
+	ForEachMaster(fn func(client driver.Client) error) error
+}
+
+func (r *redisImpl) ScanIter(options ...ScanOption) *KeyIterator {
+	if isPipelined(r.client) {
+		return &KeyIterator{scanIterator{err: ErrNestedTransaction}}
+	}
+
+	nodes := []*redisImpl{r}
+
+	if mw, ok := r.client.(masterWalker); ok {
+		nodes = nil
+		err := mw.ForEachMaster(func(client driver.Client) error {
+			nodes = append(nodes, newRedis(r.ctx, client))
+			return nil
+		})
+
+		if err != nil {
+			return &KeyIterator{scanIterator{err: err}}
+		}
+	}
+
+	pages := make([]scanPage, 0, len(nodes))
+
+	for _, node := range nodes {
+		node := node
+		pages = append(pages, func(cursor int64) (next int64, elems []interface{}, err error) {
+			next, keys, err := node.Scan(cursor, options...)
+
+			for _, key := range keys {
+				elems = append(elems, key)
+			}
+
+			return next, elems, err
+		})
+	}
+
+	return &KeyIterator{newScanIterator(options, pages...)}
+}
+
+func (r *redisImpl) SScanIter(key string, options ...ScanOption) *MemberIterator {
+	if isPipelined(r.client) {
+		return &MemberIterator{scanIterator{err: ErrNestedTransaction}}
+	}
+
+	return &MemberIterator{newScanIterator(options, func(cursor int64) (next int64, elems []interface{}, err error) {
+		next, members, err := r.SScan(key, cursor, options...)
+
+		for _, member := range members {
+			elems = append(elems, member)
+		}
+
+		return next, elems, err
+	})}
+}
+
+func (r *redisImpl) HScanIter(key string, options ...ScanOption) *KeyAndValueIterator {
+	if isPipelined(r.client) {
+		return &KeyAndValueIterator{scanIterator{err: ErrNestedTransaction}}
+	}
+
+	return &KeyAndValueIterator{newScanIterator(options, func(cursor int64) (next int64, elems []interface{}, err error) {
+		next, kvs, err := r.HScan(key, cursor, options...)
+
+		for _, kv := range kvs {
+			elems = append(elems, kv)
+		}
+
+		return next, elems, err
+	})}
+}
+
+func (r *redisImpl) ZScanIter(key string, options ...ScanOption) *MemberAndScoreIterator {
+	if isPipelined(r.client) {
+		return &MemberAndScoreIterator{scanIterator{err: ErrNestedTransaction}}
+	}
+
+	return &MemberAndScoreIterator{newScanIterator(options, func(cursor int64) (next int64, elems []interface{}, err error) {
+		next, mss, err := r.ZScan(key, cursor, options...)
+
+		for _, ms := range mss {
+			elems = append(elems, ms)
+		}
+
+		return next, elems, err
+	})}
+}
@@ -1,6 +1,150 @@
 package redis
 
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"strings"
+
+	"github.com/altstory/go-redis/internal/driver"
+)
+
 // Scripting 代表 Redis 跟 scripting 相关的接口，详见 https://redis.io/commands#scripting。
+//
+// 注意，SCRIPT KILL 和 SCRIPT DEBUG 是运维使用的命令，这里不支持。
 type Scripting interface {
-	// TODO:
+	Eval(script string, keys []string, args ...interface{}) (mv MultiValue, err error)
+	EvalSha(sha1 string, keys []string, args ...interface{}) (mv MultiValue, err error)
+	ScriptExists(sha1s ...string) (exists []bool, err error)
+	ScriptFlush() (err error)
+	ScriptLoad(script string) (sha1 string, err error)
+}
+
+func (r *redisImpl) Eval(script string, keys []string, args ...interface{}) (mv MultiValue, err error) {
+	err = r.do("EVAL", func(client driver.Client) error {
+		mv, err = mustBeMultiValue(client, process(client, newCmd(evalArgs("EVAL", script, keys, args)...)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) EvalSha(sha1 string, keys []string, args ...interface{}) (mv MultiValue, err error) {
+	err = r.do("EVALSHA", func(client driver.Client) error {
+		mv, err = mustBeMultiValue(client, process(client, newCmd(evalArgs("EVALSHA", sha1, keys, args)...)))
+		return err
+	})
+	return
+}
+
+// evalArgs 生成 EVAL 和 EVALSHA 的参数，格式是 `name script numkeys key [key ...] arg [arg ...]`。
+func evalArgs(name string, script string, keys []string, args []interface{}) []interface{} {
+	cmdArgs := make([]interface{}, 0, 3+len(keys)+len(args))
+	cmdArgs = append(cmdArgs, name, script, len(keys))
+	cmdArgs = appendArgs(cmdArgs, keys)
+	return append(cmdArgs, args...)
+}
+
+func (r *redisImpl) ScriptExists(sha1s ...string) (exists []bool, err error) {
+	if len(sha1s) == 0 {
+		return
+	}
+
+	err = r.do("SCRIPT EXISTS", func(client driver.Client) error {
+		exists, err = mustBeBools(client, process(client, newCmd(appendArgs([]interface{}{"SCRIPT", "EXISTS"}, sha1s)...)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) ScriptFlush() (err error) {
+	err = r.do("SCRIPT FLUSH", func(client driver.Client) error {
+		_, err = mustBeStatus(client, process(client, newCmd("SCRIPT", "FLUSH")))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) ScriptLoad(script string) (sha1 string, err error) {
+	err = r.do("SCRIPT LOAD", func(client driver.Client) error {
+		var hash BulkString
+		hash, err = mustBeBulkString(client, process(client, newCmd("SCRIPT", "LOAD", script)))
+		sha1 = hash.String()
+		return err
+	})
+	return
+}
+
+// Script 代表一个 Lua 脚本，可以反复在不同的 Redis 连接上执行。
+//
+// 使用 Run 执行脚本时会优先使用 EVALSHA，避免每次都发送完整的脚本，
+// 如果 Redis 还没有缓存这个脚本，会自动改用 EVAL 执行，并且让 Redis 缓存下来。
+type Script struct {
+	src  string
+	hash string
+}
+
+// NewScript 使用 Lua 源码 src 创建一个 Script。
+func NewScript(src string) *Script {
+	h := sha1.New()
+	h.Write([]byte(src))
+
+	return &Script{
+		src:  src,
+		hash: hex.EncodeToString(h.Sum(nil)),
+	}
+}
+
+// Source 返回脚本的 Lua 源码。
+func (s *Script) Source() string {
+	return s.src
+}
+
+// Hash 返回脚本的 SHA1 值，用于 EVALSHA。
+func (s *Script) Hash() string {
+	return s.hash
+}
+
+// Load 使用 SCRIPT LOAD 将脚本缓存到 Redis 里。
+func (s *Script) Load(r Redis) (err error) {
+	_, err = r.ScriptLoad(s.src)
+	return
+}
+
+// Exists 使用 SCRIPT EXISTS 判断脚本是否已经缓存在 Redis 里。
+func (s *Script) Exists(r Redis) (exists bool, err error) {
+	results, err := r.ScriptExists(s.hash)
+
+	if err != nil {
+		return
+	}
+
+	exists = len(results) != 0 && results[0]
+	return
+}
+
+// Eval 使用 EVAL 执行脚本。
+func (s *Script) Eval(r Redis, keys []string, args ...interface{}) (mv MultiValue, err error) {
+	return r.Eval(s.src, keys, args...)
+}
+
+// EvalSha 使用 EVALSHA 执行脚本。
+func (s *Script) EvalSha(r Redis, keys []string, args ...interface{}) (mv MultiValue, err error) {
+	return r.EvalSha(s.hash, keys, args...)
+}
+
+// Run 执行脚本，优先使用 EVALSHA，当 Redis 返回 NOSCRIPT 错误时自动改用 EVAL。
+//
+// 由于在 transaction 或 pipeline 里无法在执行前得知 EVALSHA 是否成功，
+// 在 Multi 或 Pipeline 的回调里调用 Run 会直接使用 EVAL。
+func (s *Script) Run(r Redis, keys []string, args ...interface{}) (mv MultiValue, err error) {
+	if impl, ok := r.(*redisImpl); ok && isPipelined(impl.client) {
+		return s.Eval(r, keys, args...)
+	}
+
+	mv, err = s.EvalSha(r, keys, args...)
+
+	if err != nil && strings.HasPrefix(err.Error(), "NOSCRIPT") {
+		return s.Eval(r, keys, args...)
+	}
+
+	return
 }
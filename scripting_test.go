@@ -0,0 +1,67 @@
+package redis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+func TestScript(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	f := factory(t)
+	r := f.New(ctx)
+	resetRedis(t, r)
+	a.NilError(r.ScriptFlush())
+
+	key := "script-key"
+	script := NewScript(`return redis.call("INCRBY", KEYS[1], ARGV[1])`)
+
+	exists, err := script.Exists(r)
+	a.NilError(err)
+	a.Assert(!exists)
+
+	// 脚本还没有缓存，Run 会自动改用 EVAL。
+	mv, err := script.Run(r, []string{key}, 2)
+	a.NilError(err)
+	n, ok := mv.Int64()
+	a.Assert(ok)
+	a.Equal(n, int64(2))
+
+	exists, err = script.Exists(r)
+	a.NilError(err)
+	a.Assert(exists)
+
+	mv, err = script.EvalSha(r, []string{key}, 3)
+	a.NilError(err)
+	n, ok = mv.Int64()
+	a.Assert(ok)
+	a.Equal(n, int64(5))
+
+	sha1, err := r.ScriptLoad(script.Source())
+	a.NilError(err)
+	a.Equal(sha1, script.Hash())
+
+	mv, err = r.Eval(`return {KEYS[1], ARGV[1], false}`, []string{key}, "foo")
+	a.NilError(err)
+	mvs, ok := mv.MultiValues()
+	a.Assert(ok)
+	a.Equal(len(mvs), 3)
+
+	bs, ok := mvs[1].BulkString()
+	a.Assert(ok)
+	a.Equal(bs.String(), "foo")
+	a.Assert(mvs[2].IsNil())
+
+	a.NilError(r.ScriptFlush())
+
+	mvs, err = r.Pipeline(func(p Redis) error {
+		_, err := script.Run(p, []string{key}, 1)
+		return err
+	})
+	a.NilError(err)
+	n, ok = mvs[0].Int64()
+	a.Assert(ok)
+	a.Equal(n, int64(6))
+}
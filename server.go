@@ -1,12 +1,150 @@
 package redis
 
-import "github.com/altstory/go-redis/internal/driver"
+import (
+	"time"
+
+	"github.com/altstory/go-redis/internal/driver"
+)
 
 // Server 代表 Redis 跟 server 相关的接口，详见 https://redis.io/commands#server。
 type Server interface {
-	// TODO:
-
+	BgRewriteAOF() (err error)
+	BgSave() (err error)
+	ClientKill(addr string) (err error)
+	ClientKillByFilter(filters ...string) (killed int, err error) // CLIENT KILL [ID id] [ADDR addr] ...
+	ClientList() (infos []ClientInfo, err error)
+	ClientPause(timeout time.Duration) (err error)
+	ConfigGet(parameter string) (kvs KeyAndValues, err error)
+	ConfigResetStat() (err error)
+	ConfigRewrite() (err error)
+	ConfigSet(parameter string, value string) (err error)
+	DBSize() (size int, err error)
 	FlushAll(options ...FlushOption) (err error)
+	FlushDB(options ...FlushOption) (err error)
+	Info(sections ...string) (info ServerInfo, err error)
+	LastSave() (t time.Time, err error)
+	LatencyHistory(event string) (samples []LatencySample, err error)
+	LatencyLatest() (events []LatencyEvent, err error)
+	LatencyReset(events ...string) (reset int, err error)
+	MemoryDoctor() (report string, err error)
+	MemoryPurge() (err error)
+	MemoryStats() (stats MemoryStats, err error)
+	MemoryUsage(key string, options ...MemoryUsageOption) (usage int64, exists bool, err error)
+	SlowLogGet(count int) (entries []SlowLogEntry, err error) // SLOWLOG GET [count]，count 为 0 时使用 Redis 的默认值
+	SlowLogLen() (l int, err error)
+	SlowLogReset() (err error)
+	Time() (t time.Time, err error)
+}
+
+func (r *redisImpl) BgRewriteAOF() (err error) {
+	err = r.do("BGREWRITEAOF", func(client driver.Client) error {
+		_, err = mustBeStatus(client, process(client, newCmd("BGREWRITEAOF")))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) BgSave() (err error) {
+	err = r.do("BGSAVE", func(client driver.Client) error {
+		_, err = mustBeStatus(client, process(client, newCmd("BGSAVE")))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) ClientKill(addr string) (err error) {
+	err = r.do("CLIENT KILL", func(client driver.Client) error {
+		_, err = mustBeStatus(client, process(client, newCmd("CLIENT", "KILL", addr)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) ClientKillByFilter(filters ...string) (killed int, err error) {
+	err = r.do("CLIENT KILL", func(client driver.Client) error {
+		killed, err = mustBeInt(client, process(client, newCmd(appendArgs([]interface{}{"CLIENT", "KILL"}, filters)...)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) ClientList() (infos []ClientInfo, err error) {
+	err = r.do("CLIENT LIST", func(client driver.Client) error {
+		var bs BulkString
+		bs, err = mustBeBulkString(client, process(client, newCmd("CLIENT", "LIST")))
+
+		if err != nil {
+			return err
+		}
+
+		infos = parseClientList(bs.String())
+		return nil
+	})
+	return
+}
+
+func (r *redisImpl) ClientPause(timeout time.Duration) (err error) {
+	err = r.do("CLIENT PAUSE", func(client driver.Client) error {
+		_, err = mustBeBool(client, process(client, newCmdWith(parseBool, "CLIENT", "PAUSE", int64(timeout/time.Millisecond))))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) ConfigGet(parameter string) (kvs KeyAndValues, err error) {
+	err = r.do("CONFIG GET", func(client driver.Client) error {
+		var mvs []MultiValue
+		mvs, err = mustBeMultiValues(client, process(client, newCmd("CONFIG", "GET", parameter)))
+
+		if err != nil {
+			return err
+		}
+
+		if len(mvs)%2 != 0 {
+			panic(ErrUnexpectedResponseType)
+		}
+
+		kvs = make(KeyAndValues, 0, len(mvs)/2)
+
+		for i := 0; i < len(mvs); i += 2 {
+			kvs = append(kvs, MakeKeyAndValue(multiValueString(mvs[i]), multiValueString(mvs[i+1])))
+		}
+
+		return nil
+	})
+	return
+}
+
+func (r *redisImpl) ConfigResetStat() (err error) {
+	err = r.do("CONFIG RESETSTAT", func(client driver.Client) error {
+		_, err = mustBeStatus(client, process(client, newCmd("CONFIG", "RESETSTAT")))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) ConfigRewrite() (err error) {
+	err = r.do("CONFIG REWRITE", func(client driver.Client) error {
+		_, err = mustBeStatus(client, process(client, newCmd("CONFIG", "REWRITE")))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) ConfigSet(parameter string, value string) (err error) {
+	err = r.do("CONFIG SET", func(client driver.Client) error {
+		_, err = mustBeStatus(client, process(client, newCmd("CONFIG", "SET", parameter, value)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) DBSize() (size int, err error) {
+	err = r.do("DBSIZE", func(client driver.Client) error {
+		size, err = mustBeInt(client, process(client, newCmd("DBSIZE")))
+		return err
+	})
+	return
 }
 
 func (r *redisImpl) FlushAll(options ...FlushOption) (err error) {
@@ -21,15 +159,186 @@ func (r *redisImpl) FlushAll(options ...FlushOption) (err error) {
 
 	if async {
 		err = r.do("FLUSHALL ASYNC", func(client driver.Client) error {
-			_, err = mustBeStatus(client, client.FlushAllAsync())
+			_, err = mustBeStatus(client, process(client, newCmd("FLUSHALL", "ASYNC")))
 			return err
 		})
 	} else {
 		err = r.do("FLUSHALL", func(client driver.Client) error {
-			_, err = mustBeStatus(client, client.FlushAll())
+			_, err = mustBeStatus(client, process(client, newCmd("FLUSHALL")))
 			return err
 		})
 	}
 
 	return
 }
+
+func (r *redisImpl) FlushDB(options ...FlushOption) (err error) {
+	async := false
+
+	for _, opt := range options {
+		switch opt {
+		case flushOptionAsync:
+			async = true
+		}
+	}
+
+	if async {
+		err = r.do("FLUSHDB ASYNC", func(client driver.Client) error {
+			_, err = mustBeStatus(client, process(client, newCmd("FLUSHDB", "ASYNC")))
+			return err
+		})
+	} else {
+		err = r.do("FLUSHDB", func(client driver.Client) error {
+			_, err = mustBeStatus(client, process(client, newCmd("FLUSHDB")))
+			return err
+		})
+	}
+
+	return
+}
+
+func (r *redisImpl) Info(sections ...string) (info ServerInfo, err error) {
+	err = r.do("INFO", func(client driver.Client) error {
+		var bs BulkString
+		bs, err = mustBeBulkString(client, process(client, newCmd(appendArgs([]interface{}{"INFO"}, sections)...)))
+
+		if err != nil {
+			return err
+		}
+
+		info = parseServerInfo(bs.String())
+		return nil
+	})
+	return
+}
+
+func (r *redisImpl) LastSave() (t time.Time, err error) {
+	err = r.do("LASTSAVE", func(client driver.Client) error {
+		var ts int64
+		ts, err = mustBeInt64(client, process(client, newCmd("LASTSAVE")))
+
+		if err != nil {
+			return err
+		}
+
+		t = time.Unix(ts, 0)
+		return nil
+	})
+	return
+}
+
+func (r *redisImpl) LatencyHistory(event string) (samples []LatencySample, err error) {
+	err = r.do("LATENCY HISTORY", func(client driver.Client) error {
+		samples, err = mustBeLatencySamples(client, process(client, newCmd("LATENCY", "HISTORY", event)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) LatencyLatest() (events []LatencyEvent, err error) {
+	err = r.do("LATENCY LATEST", func(client driver.Client) error {
+		events, err = mustBeLatencyEvents(client, process(client, newCmd("LATENCY", "LATEST")))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) LatencyReset(events ...string) (reset int, err error) {
+	args := make([]interface{}, 0, 2+len(events))
+	args = append(args, "LATENCY", "RESET")
+
+	for _, e := range events {
+		args = append(args, e)
+	}
+
+	err = r.do("LATENCY RESET", func(client driver.Client) error {
+		reset, err = mustBeInt(client, process(client, newCmd(args...)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) MemoryDoctor() (report string, err error) {
+	err = r.do("MEMORY DOCTOR", func(client driver.Client) error {
+		var bs BulkString
+		bs, err = mustBeBulkString(client, process(client, newCmd("MEMORY", "DOCTOR")))
+		report = bs.String()
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) MemoryPurge() (err error) {
+	err = r.do("MEMORY PURGE", func(client driver.Client) error {
+		_, err = mustBeStatus(client, process(client, newCmd("MEMORY", "PURGE")))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) MemoryStats() (stats MemoryStats, err error) {
+	err = r.do("MEMORY STATS", func(client driver.Client) error {
+		var mvs []MultiValue
+		mvs, err = mustBeMultiValues(client, process(client, newCmd("MEMORY", "STATS")))
+
+		if err != nil {
+			return err
+		}
+
+		stats = parseMemoryStats(mvs)
+		return nil
+	})
+	return
+}
+
+func (r *redisImpl) MemoryUsage(key string, options ...MemoryUsageOption) (usage int64, exists bool, err error) {
+	args := []interface{}{"MEMORY", "USAGE", key}
+
+	for _, opt := range options {
+		args = append(args, opt.Args()...)
+	}
+
+	err = r.do("MEMORY USAGE", func(client driver.Client) error {
+		usage, exists, err = mustBeInt64OrNil(client, process(client, newCmd(args...)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) SlowLogGet(count int) (entries []SlowLogEntry, err error) {
+	args := []interface{}{"SLOWLOG", "GET"}
+
+	if count != 0 {
+		args = append(args, count)
+	}
+
+	err = r.do("SLOWLOG GET", func(client driver.Client) error {
+		entries, err = mustBeSlowLogEntries(client, process(client, newCmd(args...)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) SlowLogLen() (l int, err error) {
+	err = r.do("SLOWLOG LEN", func(client driver.Client) error {
+		l, err = mustBeInt(client, process(client, newCmd("SLOWLOG", "LEN")))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) SlowLogReset() (err error) {
+	err = r.do("SLOWLOG RESET", func(client driver.Client) error {
+		_, err = mustBeStatus(client, process(client, newCmd("SLOWLOG", "RESET")))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) Time() (t time.Time, err error) {
+	err = r.do("TIME", func(client driver.Client) error {
+		t, err = mustBeTime(client, process(client, newCmdWith(parseTime, "TIME")))
+		return err
+	})
+	return
+}
@@ -0,0 +1,274 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/huandu/go-assert"
+)
+
+func TestInfo(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	f := factory(t)
+	r := f.New(ctx)
+	resetRedis(t, r)
+
+	a.NilError(r.Set("info-key", "v"))
+	_, err := r.Get("info-key")
+	a.NilError(err)
+
+	info, err := r.Info()
+	a.NilError(err)
+	a.Assert(info.Server.RedisVersion != "")
+	a.Equal(info.Server.RedisVersion, info.Raw["server"]["redis_version"])
+	a.Assert(info.Clients.ConnectedClients >= 1)
+	a.Assert(info.Memory.UsedMemory > 0)
+	a.Assert(info.Stats.KeyspaceHits > 0)
+	a.Equal(info.Replication.Role, "master")
+	a.Equal(info.Keyspace["db0"].Keys, int64(1))
+
+	info, err = r.Info("memory")
+	a.NilError(err)
+	a.Assert(info.Memory.UsedMemory > 0)
+	a.Equal(info.Server.RedisVersion, "")
+	a.Equal(len(info.Raw), 1)
+}
+
+func TestParseServerInfo(t *testing.T) {
+	a := assert.New(t)
+	info := parseServerInfo("# Server\r\nredis_version:6.2.6\r\nuptime_in_seconds:100\r\n\r\n" +
+		"# Persistence\r\nloading:0\r\naof_enabled:1\r\n\r\n" +
+		"# Stats\r\nkeyspace_hits:3\r\nkeyspace_misses:1\r\ninstantaneous_input_kbps:1.50\r\n\r\n" +
+		"# Keyspace\r\ndb0:keys=10,expires=2,avg_ttl=1500\r\n")
+	a.Equal(info.Server.RedisVersion, "6.2.6")
+	a.Equal(info.Server.UptimeInSeconds, int64(100))
+	a.Assert(!info.Persistence.Loading)
+	a.Assert(info.Persistence.AOFEnabled)
+	a.Equal(info.Stats.HitRatio(), 0.75)
+	a.Equal(info.Stats.InstantaneousInputKbps, 1.5)
+	a.Equal(info.Keyspace, map[string]KeyspaceInfo{
+		"db0": {Keys: 10, Expires: 2, AvgTTL: 1500 * time.Millisecond},
+	})
+	a.Equal(info.Raw["stats"]["keyspace_misses"], "1")
+}
+
+func TestServerMethods(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	f := factory(t)
+	r := f.New(ctx)
+	resetRedis(t, r)
+
+	a.NilError(r.Set("server-key-1", "v"))
+	a.NilError(r.Set("server-key-2", "v"))
+
+	size, err := r.DBSize()
+	a.NilError(err)
+	a.Equal(size, 2)
+
+	a.NilError(r.FlushDB(Async()))
+	a.NilError(r.Set("server-key-1", "v"))
+	a.NilError(r.FlushDB())
+	size, err = r.DBSize()
+	a.NilError(err)
+	a.Equal(size, 0)
+
+	now, err := r.Time()
+	a.NilError(err)
+	a.Assert(time.Since(now) < time.Minute && time.Until(now) < time.Minute)
+
+	kvs, err := r.ConfigGet("maxmemory-policy")
+	a.NilError(err)
+	a.Equal(len(kvs), 1)
+	policy := kvs[0].Value
+	defer r.ConfigSet("maxmemory-policy", policy)
+
+	a.NilError(r.ConfigSet("maxmemory-policy", "allkeys-lru"))
+	kvs, err = r.ConfigGet("maxmemory-policy")
+	a.NilError(err)
+	a.Equal(kvs, KeyAndValues{MakeKeyAndValue("maxmemory-policy", "allkeys-lru")})
+
+	a.NilError(r.ConfigResetStat())
+	info, err := r.Info("stats")
+	a.NilError(err)
+	a.Equal(info.Stats.KeyspaceHits, int64(0))
+
+	lastSave, err := r.LastSave()
+	a.NilError(err)
+	a.Assert(!lastSave.IsZero())
+
+	a.NilError(r.BgSave())
+	a.NilError(r.BgRewriteAOF())
+}
+
+func TestSlowLogAndLatency(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	f := factory(t)
+	r := f.New(ctx)
+	resetRedis(t, r)
+
+	kvs, err := r.ConfigGet("slowlog-log-slower-than")
+	a.NilError(err)
+	defer r.ConfigSet("slowlog-log-slower-than", kvs[0].Value)
+	kvs, err = r.ConfigGet("latency-monitor-threshold")
+	a.NilError(err)
+	defer r.ConfigSet("latency-monitor-threshold", kvs[0].Value)
+
+	a.NilError(r.SlowLogReset())
+	_, err = r.LatencyReset()
+	a.NilError(err)
+	a.NilError(r.ConfigSet("slowlog-log-slower-than", "0"))
+	a.NilError(r.ConfigSet("latency-monitor-threshold", "1"))
+
+	_, err = r.Eval("local i = 0 while i < 5000000 do i = i + 1 end return i", nil)
+	a.NilError(err)
+
+	l, err := r.SlowLogLen()
+	a.NilError(err)
+	a.Assert(l >= 2)
+
+	entries, err := r.SlowLogGet(1)
+	a.NilError(err)
+	a.Equal(len(entries), 1)
+	a.Equal(entries[0].Args[0], "SLOWLOG")
+	a.Equal(entries[0].Args[1], "LEN")
+
+	entries, err = r.SlowLogGet(0)
+	a.NilError(err)
+	var eval *SlowLogEntry
+
+	for i := range entries {
+		if strings.ToUpper(entries[i].Args[0]) == "EVAL" {
+			eval = &entries[i]
+		}
+	}
+
+	a.Assert(eval != nil)
+	a.Assert(eval.Duration >= time.Millisecond)
+	a.Assert(eval.ClientAddr != "")
+
+	events, err := r.LatencyLatest()
+	a.NilError(err)
+	a.Assert(len(events) >= 1)
+	a.Equal(events[0].Event, "command")
+	a.Assert(events[0].Max >= time.Millisecond)
+
+	samples, err := r.LatencyHistory("command")
+	a.NilError(err)
+	a.Assert(len(samples) >= 1)
+	a.Assert(samples[0].Latency >= time.Millisecond)
+
+	var future error
+	_, err = r.Pipeline(func(p Redis) error {
+		_, future = p.LatencyLatest()
+		return nil
+	})
+	a.NilError(err)
+	mvs, ok := MakeMultiValue(future).MultiValues()
+	a.Assert(ok)
+	le, ok := mvs[0].LatencyEvent()
+	a.Assert(ok)
+	a.Equal(le, events[0])
+
+	reset, err := r.LatencyReset("command")
+	a.NilError(err)
+	a.Equal(reset, 1)
+}
+
+func TestMemoryAndObject(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	f := factory(t)
+	r := f.New(ctx)
+	resetRedis(t, r)
+
+	a.NilError(r.Set("memory-int", "12345"))
+	a.NilError(r.Set("memory-str", "v"))
+	a.NilError(r.Set("memory-raw", strings.Repeat("v", 100)))
+
+	encoding, exists, err := r.ObjectEncoding("memory-int")
+	a.NilError(err)
+	a.Assert(exists)
+	a.Equal(encoding, EncodingInt)
+	encoding, _, err = r.ObjectEncoding("memory-str")
+	a.NilError(err)
+	a.Equal(encoding, EncodingEmbStr)
+	encoding, _, err = r.ObjectEncoding("memory-raw")
+	a.NilError(err)
+	a.Equal(encoding, EncodingRaw)
+	_, exists, err = r.ObjectEncoding("memory-not-exist")
+	a.NilError(err)
+	a.Assert(!exists)
+
+	refCount, exists, err := r.ObjectRefCount("memory-str")
+	a.NilError(err)
+	a.Assert(exists)
+	a.Assert(refCount >= 1)
+
+	idle, exists, err := r.ObjectIdleTime("memory-str")
+	a.NilError(err)
+	a.Assert(exists)
+	a.Assert(idle >= 0 && idle < time.Minute)
+
+	// OBJECT FREQ 只能在 LFU 淘汰策略下使用。
+	kvs, err := r.ConfigGet("maxmemory-policy")
+	a.NilError(err)
+	a.Equal(len(kvs), 1)
+	defer r.ConfigSet("maxmemory-policy", kvs[0].Value)
+
+	_, _, err = r.ObjectFreq("memory-str")
+	a.Assert(err != nil)
+	a.NilError(r.ConfigSet("maxmemory-policy", "allkeys-lfu"))
+	freq, exists, err := r.ObjectFreq("memory-str")
+	a.NilError(err)
+	a.Assert(exists)
+	a.Assert(freq >= 0)
+
+	usage, exists, err := r.MemoryUsage("memory-raw")
+	a.NilError(err)
+	a.Assert(exists)
+	a.Assert(usage >= 100)
+	usage, exists, err = r.MemoryUsage("memory-raw", Samples(0))
+	a.NilError(err)
+	a.Assert(exists)
+	a.Assert(usage >= 100)
+	_, exists, err = r.MemoryUsage("memory-not-exist")
+	a.NilError(err)
+	a.Assert(!exists)
+
+	stats, err := r.MemoryStats()
+	a.NilError(err)
+	a.Assert(stats.TotalAllocated > 0)
+	a.Assert(stats.PeakAllocated > 0)
+	a.Assert(stats.KeysCount >= 3)
+	a.Assert(stats.Fragmentation > 0)
+	a.Assert(stats.DB[0].OverheadHashtableMain > 0)
+	a.Equal(stats.Raw["total.allocated"], strconv.FormatInt(stats.TotalAllocated, 10))
+
+	report, err := r.MemoryDoctor()
+	a.NilError(err)
+	a.Assert(report != "")
+
+	a.NilError(r.MemoryPurge())
+
+	mvs, err := r.Pipeline(func(p Redis) error {
+		p.ObjectEncoding("memory-int")
+		p.MemoryUsage("memory-raw")
+		return nil
+	})
+	a.NilError(err)
+	a.Equal(len(mvs), 2)
+
+	bs, ok := mvs[0].BulkString()
+	a.Assert(ok)
+	a.Equal(bs.String(), string(EncodingInt))
+
+	usage, ok = mvs[1].Int64()
+	a.Assert(ok)
+	a.Assert(usage >= 100)
+}
@@ -0,0 +1,228 @@
+package redis
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ServerInfo 代表 INFO 返回的服务器信息。
+//
+// 常用的字段被解析到各个 section 对应的结构里，如果需要读取没有列出的字段，可以使用 Raw。
+// 如果调用 Info 时只指定了部分 section，其他 section 的字段都是零值。
+type ServerInfo struct {
+	Server      ServerInfoServer
+	Clients     ServerInfoClients
+	Memory      ServerInfoMemory
+	Persistence ServerInfoPersistence
+	Stats       ServerInfoStats
+	Replication ServerInfoReplication
+	Keyspace    map[string]KeyspaceInfo // Keyspace 的 key 是数据库名，比如 db0。
+
+	Raw map[string]map[string]string // Raw 保存所有 section 的原始字段，section 名字是小写的，比如 memory。
+}
+
+// ServerInfoServer 代表 INFO 的 server section。
+type ServerInfoServer struct {
+	RedisVersion    string `info:"redis_version"`
+	RedisMode       string `info:"redis_mode"`
+	OS              string `info:"os"`
+	ArchBits        int    `info:"arch_bits"`
+	ProcessID       int    `info:"process_id"`
+	RunID           string `info:"run_id"`
+	TCPPort         int    `info:"tcp_port"`
+	UptimeInSeconds int64  `info:"uptime_in_seconds"`
+	Hz              int    `info:"hz"`
+	ConfigFile      string `info:"config_file"`
+}
+
+// ServerInfoClients 代表 INFO 的 clients section。
+type ServerInfoClients struct {
+	ConnectedClients int `info:"connected_clients"`
+	MaxClients       int `info:"maxclients"` // MaxClients 需要 Redis 7.0 及以上版本。
+	BlockedClients   int `info:"blocked_clients"`
+	TrackingClients  int `info:"tracking_clients"`
+}
+
+// ServerInfoMemory 代表 INFO 的 memory section。
+type ServerInfoMemory struct {
+	UsedMemory            int64   `info:"used_memory"`
+	UsedMemoryRSS         int64   `info:"used_memory_rss"`
+	UsedMemoryPeak        int64   `info:"used_memory_peak"`
+	UsedMemoryLua         int64   `info:"used_memory_lua"`
+	TotalSystemMemory     int64   `info:"total_system_memory"`
+	MaxMemory             int64   `info:"maxmemory"`
+	MaxMemoryPolicy       string  `info:"maxmemory_policy"`
+	MemFragmentationRatio float64 `info:"mem_fragmentation_ratio"`
+	MemAllocator          string  `info:"mem_allocator"`
+}
+
+// ServerInfoPersistence 代表 INFO 的 persistence section。
+type ServerInfoPersistence struct {
+	Loading                 bool   `info:"loading"`
+	RDBChangesSinceLastSave int64  `info:"rdb_changes_since_last_save"`
+	RDBBgsaveInProgress     bool   `info:"rdb_bgsave_in_progress"`
+	RDBLastSaveTime         int64  `info:"rdb_last_save_time"` // RDBLastSaveTime 是 unix 时间戳，单位是秒。
+	RDBLastBgsaveStatus     string `info:"rdb_last_bgsave_status"`
+	AOFEnabled              bool   `info:"aof_enabled"`
+	AOFRewriteInProgress    bool   `info:"aof_rewrite_in_progress"`
+	AOFLastBgrewriteStatus  string `info:"aof_last_bgrewrite_status"`
+	AOFLastWriteStatus      string `info:"aof_last_write_status"`
+	AOFRewriteScheduled     bool   `info:"aof_rewrite_scheduled"`
+	AOFLastRewriteTimeSec   int64  `info:"aof_last_rewrite_time_sec"`
+}
+
+// ServerInfoStats 代表 INFO 的 stats section。
+type ServerInfoStats struct {
+	TotalConnectionsReceived int64   `info:"total_connections_received"`
+	TotalCommandsProcessed   int64   `info:"total_commands_processed"`
+	InstantaneousOpsPerSec   int64   `info:"instantaneous_ops_per_sec"`
+	TotalNetInputBytes       int64   `info:"total_net_input_bytes"`
+	TotalNetOutputBytes      int64   `info:"total_net_output_bytes"`
+	InstantaneousInputKbps   float64 `info:"instantaneous_input_kbps"`
+	InstantaneousOutputKbps  float64 `info:"instantaneous_output_kbps"`
+	RejectedConnections      int64   `info:"rejected_connections"`
+	ExpiredKeys              int64   `info:"expired_keys"`
+	EvictedKeys              int64   `info:"evicted_keys"`
+	KeyspaceHits             int64   `info:"keyspace_hits"`
+	KeyspaceMisses           int64   `info:"keyspace_misses"`
+	PubSubChannels           int64   `info:"pubsub_channels"`
+	PubSubPatterns           int64   `info:"pubsub_patterns"`
+	LatestForkUsec           int64   `info:"latest_fork_usec"`
+}
+
+// HitRatio 返回 keyspace 的命中率，如果还没有任何查询则返回 0。
+func (stats *ServerInfoStats) HitRatio() float64 {
+	total := stats.KeyspaceHits + stats.KeyspaceMisses
+
+	if total == 0 {
+		return 0
+	}
+
+	return float64(stats.KeyspaceHits) / float64(total)
+}
+
+// ServerInfoReplication 代表 INFO 的 replication section。
+type ServerInfoReplication struct {
+	Role             string `info:"role"`
+	ConnectedSlaves  int    `info:"connected_slaves"`
+	MasterHost       string `info:"master_host"`
+	MasterPort       int    `info:"master_port"`
+	MasterLinkStatus string `info:"master_link_status"`
+	MasterReplOffset int64  `info:"master_repl_offset"`
+	SlaveReplOffset  int64  `info:"slave_repl_offset"`
+}
+
+// KeyspaceInfo 代表 INFO 的 keyspace section 中一个数据库的统计信息。
+type KeyspaceInfo struct {
+	Keys    int64
+	Expires int64
+	AvgTTL  time.Duration
+}
+
+// parseServerInfo 解析 INFO 返回的文本。
+func parseServerInfo(s string) (info ServerInfo) {
+	info.Raw = map[string]map[string]string{}
+	var fields map[string]string
+
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+
+		if line == "" {
+			continue
+		}
+
+		if line[0] == '#' {
+			section := strings.ToLower(strings.TrimSpace(line[1:]))
+			fields = map[string]string{}
+			info.Raw[section] = fields
+			continue
+		}
+
+		idx := strings.IndexByte(line, ':')
+
+		if idx < 0 || fields == nil {
+			continue
+		}
+
+		fields[line[:idx]] = line[idx+1:]
+	}
+
+	fillInfoFields(&info.Server, info.Raw["server"])
+	fillInfoFields(&info.Clients, info.Raw["clients"])
+	fillInfoFields(&info.Memory, info.Raw["memory"])
+	fillInfoFields(&info.Persistence, info.Raw["persistence"])
+	fillInfoFields(&info.Stats, info.Raw["stats"])
+	fillInfoFields(&info.Replication, info.Raw["replication"])
+
+	if keyspace := info.Raw["keyspace"]; len(keyspace) != 0 {
+		info.Keyspace = make(map[string]KeyspaceInfo, len(keyspace))
+
+		for db, value := range keyspace {
+			info.Keyspace[db] = parseKeyspaceInfo(value)
+		}
+	}
+
+	return
+}
+
+// parseKeyspaceInfo 解析形如 keys=1,expires=0,avg_ttl=0 的统计信息。
+func parseKeyspaceInfo(s string) (ki KeyspaceInfo) {
+	for _, kv := range strings.Split(s, ",") {
+		idx := strings.IndexByte(kv, '=')
+
+		if idx < 0 {
+			continue
+		}
+
+		n := parseInt64(kv[idx+1:])
+
+		switch kv[:idx] {
+		case "keys":
+			ki.Keys = n
+		case "expires":
+			ki.Expires = n
+		case "avg_ttl":
+			ki.AvgTTL = time.Duration(n) * time.Millisecond
+		}
+	}
+
+	return
+}
+
+// fillInfoFields 根据 info tag 将 fields 中的值填入 v 指向的结构，无法解析的值会被忽略。
+func fillInfoFields(v interface{}, fields map[string]string) {
+	if len(fields) == 0 {
+		return
+	}
+
+	rv := reflect.ValueOf(v).Elem()
+	rt := rv.Type()
+
+	for i := 0; i < rt.NumField(); i++ {
+		name := rt.Field(i).Tag.Get("info")
+		value, ok := fields[name]
+
+		if name == "" || !ok {
+			continue
+		}
+
+		field := rv.Field(i)
+
+		switch field.Kind() {
+		case reflect.String:
+			field.SetString(value)
+		case reflect.Int, reflect.Int64:
+			if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+				field.SetInt(n)
+			}
+		case reflect.Float64:
+			if f, err := strconv.ParseFloat(value, 64); err == nil {
+				field.SetFloat(f)
+			}
+		case reflect.Bool:
+			field.SetBool(value == "1")
+		}
+	}
+}
@@ -29,14 +29,8 @@ func (r *redisImpl) SAdd(key string, members ...string) (added int, err error) {
 		return
 	}
 
-	data := make([]interface{}, 0, len(members))
-
-	for _, m := range members {
-		data = append(data, m)
-	}
-
 	err = r.do("SADD", func(client driver.Client) error {
-		added, err = mustBeInt(client, client.SAdd(key, data...))
+		added, err = mustBeInt(client, process(client, newCmd(appendArgs([]interface{}{"SADD", key}, members)...)))
 		return err
 	})
 	return
@@ -44,7 +38,7 @@ func (r *redisImpl) SAdd(key string, members ...string) (added int, err error) {
 
 func (r *redisImpl) SCard(key string) (count int, err error) {
 	err = r.do("SCARD", func(client driver.Client) error {
-		count, err = mustBeInt(client, client.SCard(key))
+		count, err = mustBeInt(client, process(client, newCmd("SCARD", key)))
 		return err
 	})
 	return
@@ -56,7 +50,7 @@ func (r *redisImpl) SDiff(keys ...string) (members []BulkString, err error) {
 	}
 
 	err = r.do("SDIFF", func(client driver.Client) error {
-		members, err = mustBeBulkStrings(client, client.SDiff(keys...))
+		members, err = mustBeBulkStrings(client, process(client, newCmd(appendArgs([]interface{}{"SDIFF"}, keys)...)))
 		return err
 	})
 	return
@@ -68,7 +62,7 @@ func (r *redisImpl) SDiffStore(dst string, keys ...string) (count int, err error
 	}
 
 	err = r.do("SDIFFSTORE", func(client driver.Client) error {
-		count, err = mustBeInt(client, client.SDiffStore(dst, keys...))
+		count, err = mustBeInt(client, process(client, newCmd(appendArgs([]interface{}{"SDIFFSTORE", dst}, keys)...)))
 		return err
 	})
 	return
@@ -80,7 +74,7 @@ func (r *redisImpl) SInter(keys ...string) (members []BulkString, err error) {
 	}
 
 	err = r.do("SINTER", func(client driver.Client) error {
-		members, err = mustBeBulkStrings(client, client.SInter(keys...))
+		members, err = mustBeBulkStrings(client, process(client, newCmd(appendArgs([]interface{}{"SINTER"}, keys)...)))
 		return err
 	})
 	return
@@ -92,7 +86,7 @@ func (r *redisImpl) SInterStore(dst string, keys ...string) (count int, err erro
 	}
 
 	err = r.do("SINTERSTORE", func(client driver.Client) error {
-		count, err = mustBeInt(client, client.SInterStore(dst, keys...))
+		count, err = mustBeInt(client, process(client, newCmd(appendArgs([]interface{}{"SINTERSTORE", dst}, keys)...)))
 		return err
 	})
 	return
@@ -100,7 +94,7 @@ func (r *redisImpl) SInterStore(dst string, keys ...string) (count int, err erro
 
 func (r *redisImpl) SIsMember(key string, member string) (exists bool, err error) {
 	err = r.do("SISMEMBER", func(client driver.Client) error {
-		exists, err = mustBeBool(client, client.SIsMember(key, member))
+		exists, err = mustBeBool(client, process(client, newCmdWith(parseBool, "SISMEMBER", key, member)))
 		return err
 	})
 	return
@@ -108,7 +102,7 @@ func (r *redisImpl) SIsMember(key string, member string) (exists bool, err error
 
 func (r *redisImpl) SMembers(key string) (members []BulkString, err error) {
 	err = r.do("SMEMBERS", func(client driver.Client) error {
-		members, err = mustBeBulkStrings(client, client.SMembers(key))
+		members, err = mustBeBulkStrings(client, process(client, newCmd("SMEMBERS", key)))
 		return err
 	})
 	return
@@ -116,7 +110,7 @@ func (r *redisImpl) SMembers(key string) (members []BulkString, err error) {
 
 func (r *redisImpl) SMove(src string, dst string, member string) (moved bool, err error) {
 	err = r.do("SMOVE", func(client driver.Client) error {
-		moved, err = mustBeBool(client, client.SMove(src, dst, member))
+		moved, err = mustBeBool(client, process(client, newCmdWith(parseBool, "SMOVE", src, dst, member)))
 		return err
 	})
 	return
@@ -124,7 +118,7 @@ func (r *redisImpl) SMove(src string, dst string, member string) (moved bool, er
 
 func (r *redisImpl) SPop(key string) (member BulkString, err error) {
 	err = r.do("SPOP", func(client driver.Client) error {
-		member, err = mustBeBulkString(client, client.SPop(key))
+		member, err = mustBeBulkString(client, process(client, newCmd("SPOP", key)))
 		return err
 	})
 	return
@@ -132,7 +126,7 @@ func (r *redisImpl) SPop(key string) (member BulkString, err error) {
 
 func (r *redisImpl) SPopN(key string, count int) (members []BulkString, err error) {
 	err = r.do("SPOP-N", func(client driver.Client) error {
-		members, err = mustBeBulkStrings(client, client.SPopN(key, int64(count)))
+		members, err = mustBeBulkStrings(client, process(client, newCmd("SPOP", key, count)))
 		return err
 	})
 	return
@@ -140,7 +134,7 @@ func (r *redisImpl) SPopN(key string, count int) (members []BulkString, err erro
 
 func (r *redisImpl) SRandMember(key string) (member BulkString, err error) {
 	err = r.do("SRANDMEMBER", func(client driver.Client) error {
-		member, err = mustBeBulkString(client, client.SRandMember(key))
+		member, err = mustBeBulkString(client, process(client, newCmd("SRANDMEMBER", key)))
 		return err
 	})
 	return
@@ -148,7 +142,7 @@ func (r *redisImpl) SRandMember(key string) (member BulkString, err error) {
 
 func (r *redisImpl) SRandMemberN(key string, count int) (members []BulkString, err error) {
 	err = r.do("SRANDMEMBER-N", func(client driver.Client) error {
-		members, err = mustBeBulkStrings(client, client.SRandMemberN(key, int64(count)))
+		members, err = mustBeBulkStrings(client, process(client, newCmd("SRANDMEMBER", key, count)))
 		return err
 	})
 	return
@@ -159,14 +153,8 @@ func (r *redisImpl) SRem(key string, members ...string) (removed int, err error)
 		return
 	}
 
-	data := make([]interface{}, 0, len(members))
-
-	for _, m := range members {
-		data = append(data, m)
-	}
-
 	err = r.do("SREM", func(client driver.Client) error {
-		removed, err = mustBeInt(client, client.SRem(key, data...))
+		removed, err = mustBeInt(client, process(client, newCmd(appendArgs([]interface{}{"SREM", key}, members)...)))
 		return err
 	})
 	return
@@ -178,7 +166,7 @@ func (r *redisImpl) SUnion(keys ...string) (members []BulkString, err error) {
 	}
 
 	err = r.do("SUNION", func(client driver.Client) error {
-		members, err = mustBeBulkStrings(client, client.SUnion(keys...))
+		members, err = mustBeBulkStrings(client, process(client, newCmd(appendArgs([]interface{}{"SUNION"}, keys)...)))
 		return err
 	})
 	return
@@ -190,7 +178,7 @@ func (r *redisImpl) SUnionStore(dst string, keys ...string) (count int, err erro
 	}
 
 	err = r.do("SUNIONSTORE", func(client driver.Client) error {
-		count, err = mustBeInt(client, client.SUnionStore(dst, keys...))
+		count, err = mustBeInt(client, process(client, newCmd(appendArgs([]interface{}{"SUNIONSTORE", dst}, keys)...)))
 		return err
 	})
 	return
@@ -0,0 +1,106 @@
+package redis
+
+import (
+	"time"
+)
+
+// SlowLogEntry 代表 SLOWLOG GET 返回的一条慢查询记录。
+type SlowLogEntry struct {
+	ID         int64         // ID 是慢查询记录的唯一 ID。
+	Time       time.Time     // Time 是命令开始执行的时间。
+	Duration   time.Duration // Duration 是命令执行的时间，精度是微秒。
+	Args       []string      // Args 是命令及其参数，过长的参数会被 Redis 截断。
+	ClientAddr string        // ClientAddr 是客户端地址，需要 Redis 4.0 及以上版本。
+	ClientName string        // ClientName 是客户端通过 CLIENT SETNAME 设置的名字，需要 Redis 4.0 及以上版本。
+}
+
+// parseSlowLogEntry 解析一条慢查询记录，格式是：
+//     [id, timestamp, duration, [arg, ...], client-addr, client-name]
+func parseSlowLogEntry(mvs []MultiValue) (entry SlowLogEntry, ok bool) {
+	if len(mvs) < 4 {
+		return
+	}
+
+	id, ok1 := mvs[0].Int64()
+	ts, ok2 := mvs[1].Int64()
+	us, ok3 := mvs[2].Int64()
+	args, ok4 := mvs[3].MultiValues()
+
+	if !ok1 || !ok2 || !ok3 || !ok4 {
+		return
+	}
+
+	entry.ID = id
+	entry.Time = time.Unix(ts, 0)
+	entry.Duration = time.Duration(us) * time.Microsecond
+	entry.Args = make([]string, 0, len(args))
+
+	for _, arg := range args {
+		entry.Args = append(entry.Args, multiValueString(arg))
+	}
+
+	if len(mvs) >= 6 {
+		entry.ClientAddr = multiValueString(mvs[4])
+		entry.ClientName = multiValueString(mvs[5])
+	}
+
+	ok = true
+	return
+}
+
+// LatencyEvent 代表 LATENCY LATEST 返回的一个事件的最新延迟数据。
+type LatencyEvent struct {
+	Event  string        // Event 是事件名，比如 command、fast-command 等。
+	Time   time.Time     // Time 是最近一次出现延迟的时间。
+	Latest time.Duration // Latest 是最近一次的延迟，精度是毫秒。
+	Max    time.Duration // Max 是这个事件历史上最大的延迟，精度是毫秒。
+}
+
+// parseLatencyEvent 解析一个延迟事件，格式是：
+//     [event, timestamp, latest-ms, max-ms]
+func parseLatencyEvent(mvs []MultiValue) (le LatencyEvent, ok bool) {
+	if len(mvs) < 4 {
+		return
+	}
+
+	ts, ok1 := mvs[1].Int64()
+	latest, ok2 := mvs[2].Int64()
+	max, ok3 := mvs[3].Int64()
+
+	if !ok1 || !ok2 || !ok3 {
+		return
+	}
+
+	le.Event = multiValueString(mvs[0])
+	le.Time = time.Unix(ts, 0)
+	le.Latest = time.Duration(latest) * time.Millisecond
+	le.Max = time.Duration(max) * time.Millisecond
+	ok = true
+	return
+}
+
+// LatencySample 代表 LATENCY HISTORY 返回的一个延迟采样。
+type LatencySample struct {
+	Time    time.Time     // Time 是采样的时间。
+	Latency time.Duration // Latency 是采样到的延迟，精度是毫秒。
+}
+
+// parseLatencySample 解析一个延迟采样，格式是：
+//     [timestamp, latency-ms]
+func parseLatencySample(mvs []MultiValue) (ls LatencySample, ok bool) {
+	if len(mvs) < 2 {
+		return
+	}
+
+	ts, ok1 := mvs[0].Int64()
+	latency, ok2 := mvs[1].Int64()
+
+	if !ok1 || !ok2 {
+		return
+	}
+
+	ls.Time = time.Unix(ts, 0)
+	ls.Latency = time.Duration(latency) * time.Millisecond
+	ok = true
+	return
+}
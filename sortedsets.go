@@ -2,7 +2,6 @@ package redis
 
 import (
 	"github.com/altstory/go-redis/internal/driver"
-	"github.com/go-redis/redis"
 )
 
 // SortedSets 代表 Redis 跟 zset 相关的接口，详见 https://redis.io/commands#sorted_set。
@@ -45,17 +44,15 @@ func (r *redisImpl) ZAdd(key string, mss ...MemberAndScore) (added int, err erro
 		return
 	}
 
-	zs := make([]redis.Z, 0, len(mss))
+	args := make([]interface{}, 0, 2+2*len(mss))
+	args = append(args, "ZADD", key)
 
 	for _, ms := range mss {
-		zs = append(zs, redis.Z{
-			Member: ms.Member,
-			Score:  ms.Score,
-		})
+		args = append(args, ms.Score, ms.Member)
 	}
 
 	err = r.do("ZADD", func(client driver.Client) error {
-		added, err = mustBeInt(client, client.ZAdd(key, zs...))
+		added, err = mustBeInt(client, process(client, newCmd(args...)))
 		return err
 	})
 	return
@@ -63,7 +60,7 @@ func (r *redisImpl) ZAdd(key string, mss ...MemberAndScore) (added int, err erro
 
 func (r *redisImpl) ZCard(key string) (count int, err error) {
 	err = r.do("ZCARD", func(client driver.Client) error {
-		count, err = mustBeInt(client, client.ZCard(key))
+		count, err = mustBeInt(client, process(client, newCmd("ZCARD", key)))
 		return err
 	})
 	return
@@ -71,7 +68,7 @@ func (r *redisImpl) ZCard(key string) (count int, err error) {
 
 func (r *redisImpl) ZCount(key string, min ScoreRange, max ScoreRange) (count int, err error) {
 	err = r.do("ZCOUNT", func(client driver.Client) error {
-		count, err = mustBeInt(client, client.ZCount(key, min.String(), max.String()))
+		count, err = mustBeInt(client, process(client, newCmd("ZCOUNT", key, min.String(), max.String())))
 		return err
 	})
 	return
@@ -79,7 +76,7 @@ func (r *redisImpl) ZCount(key string, min ScoreRange, max ScoreRange) (count in
 
 func (r *redisImpl) ZIncrBy(key string, incr float64, member string) (score float64, err error) {
 	err = r.do("ZINCRBY", func(client driver.Client) error {
-		score, err = mustBeFloat64(client, client.ZIncrBy(key, incr, member))
+		score, err = mustBeFloat64(client, process(client, newCmdWith(parseFloat, "ZINCRBY", key, incr, member)))
 		return err
 	})
 	return
@@ -90,14 +87,8 @@ func (r *redisImpl) ZInterStore(dst string, keys []string, options ...StoreOptio
 		return
 	}
 
-	var zstore redis.ZStore
-
-	for _, opt := range options {
-		opt.fillZStore(&zstore)
-	}
-
 	err = r.do("ZINTERSTORE", func(client driver.Client) error {
-		count, err = mustBeInt(client, client.ZInterStore(dst, zstore, keys...))
+		count, err = mustBeInt(client, process(client, newCmd(zstoreArgs("ZINTERSTORE", dst, keys, options)...)))
 		return err
 	})
 	return
@@ -105,7 +96,7 @@ func (r *redisImpl) ZInterStore(dst string, keys []string, options ...StoreOptio
 
 func (r *redisImpl) ZLexCount(key string, min MemberRange, max MemberRange) (count int, err error) {
 	err = r.do("ZLEXCOUNT", func(client driver.Client) error {
-		count, err = mustBeInt(client, client.ZLexCount(key, min.String(), max.String()))
+		count, err = mustBeInt(client, process(client, newCmd("ZLEXCOUNT", key, min.String(), max.String())))
 		return err
 	})
 	return
@@ -113,7 +104,7 @@ func (r *redisImpl) ZLexCount(key string, min MemberRange, max MemberRange) (cou
 
 func (r *redisImpl) ZPopMax(key string) (ms MemberAndScore, err error) {
 	err = r.do("ZPOPMAX", func(client driver.Client) error {
-		ms, err = mustBeMemberAndScore(client, client.ZPopMax(key))
+		ms, err = mustBeMemberAndScore(client, process(client, newCmdWith(parseMemberAndScore, "ZPOPMAX", key)))
 		return err
 	})
 	return
@@ -121,7 +112,7 @@ func (r *redisImpl) ZPopMax(key string) (ms MemberAndScore, err error) {
 
 func (r *redisImpl) ZPopMaxN(key string, count int) (mss MemberAndScores, err error) {
 	err = r.do("ZPOPMAX-N", func(client driver.Client) error {
-		mss, err = mustBeMemberAndScores(client, client.ZPopMax(key, int64(count)))
+		mss, err = mustBeMemberAndScores(client, process(client, newCmdWith(parseMemberAndScores, "ZPOPMAX", key, count)))
 		return err
 	})
 	return
@@ -129,7 +120,7 @@ func (r *redisImpl) ZPopMaxN(key string, count int) (mss MemberAndScores, err er
 
 func (r *redisImpl) ZPopMin(key string) (ms MemberAndScore, err error) {
 	err = r.do("ZPOPMIN", func(client driver.Client) error {
-		ms, err = mustBeMemberAndScore(client, client.ZPopMin(key))
+		ms, err = mustBeMemberAndScore(client, process(client, newCmdWith(parseMemberAndScore, "ZPOPMIN", key)))
 		return err
 	})
 	return
@@ -137,7 +128,7 @@ func (r *redisImpl) ZPopMin(key string) (ms MemberAndScore, err error) {
 
 func (r *redisImpl) ZPopMinN(key string, count int) (mss MemberAndScores, err error) {
 	err = r.do("ZPOPMIN-N", func(client driver.Client) error {
-		mss, err = mustBeMemberAndScores(client, client.ZPopMin(key, int64(count)))
+		mss, err = mustBeMemberAndScores(client, process(client, newCmdWith(parseMemberAndScores, "ZPOPMIN", key, count)))
 		return err
 	})
 	return
@@ -145,13 +136,7 @@ func (r *redisImpl) ZPopMinN(key string, count int) (mss MemberAndScores, err er
 
 func (r *redisImpl) ZRange(key string, start float64, stop float64) (members []BulkString, err error) {
 	err = r.do("ZRANGE", func(client driver.Client) error {
-		cmd := redis.NewStringSliceCmd("ZRANGE", key, start, stop)
-
-		if err = client.Process(cmd); err != nil {
-			return err
-		}
-
-		members, err = mustBeBulkStrings(client, cmd)
+		members, err = mustBeBulkStrings(client, process(client, newCmd("ZRANGE", key, start, stop)))
 		return err
 	})
 	return
@@ -159,64 +144,31 @@ func (r *redisImpl) ZRange(key string, start float64, stop float64) (members []B
 
 func (r *redisImpl) ZRangeWithScores(key string, start float64, stop float64) (mss MemberAndScores, err error) {
 	err = r.do("ZRANGE-WITHSCORES", func(client driver.Client) error {
-		cmd := redis.NewSliceCmd("ZRANGE", key, start, stop, "WITHSCORES")
-
-		if err = client.Process(cmd); err != nil {
-			return err
-		}
-
-		mss, err = mustBeMemberAndScores(client, cmd)
+		mss, err = mustBeMemberAndScores(client, process(client, newCmdWith(parseMemberAndScores, "ZRANGE", key, start, stop, "WITHSCORES")))
 		return err
 	})
 	return
 }
 
 func (r *redisImpl) ZRangeByLex(key string, min MemberRange, max MemberRange, options ...RangeOption) (members []BulkString, err error) {
-	zrangeby := redis.ZRangeBy{
-		Min: min.String(),
-		Max: max.String(),
-	}
-
-	for _, opt := range options {
-		opt.fillZRangeBy(&zrangeby)
-	}
-
 	err = r.do("ZRANGEBYLEX", func(client driver.Client) error {
-		members, err = mustBeBulkStrings(client, client.ZRangeByLex(key, zrangeby))
+		members, err = mustBeBulkStrings(client, process(client, newCmd(zrangeByArgs("ZRANGEBYLEX", key, min.String(), max.String(), false, options)...)))
 		return err
 	})
 	return
 }
 
 func (r *redisImpl) ZRangeByScore(key string, min ScoreRange, max ScoreRange, options ...RangeOption) (members []BulkString, err error) {
-	zrangeby := redis.ZRangeBy{
-		Min: min.String(),
-		Max: max.String(),
-	}
-
-	for _, opt := range options {
-		opt.fillZRangeBy(&zrangeby)
-	}
-
 	err = r.do("ZRANGEBYSCORE", func(client driver.Client) error {
-		members, err = mustBeBulkStrings(client, client.ZRangeByScore(key, zrangeby))
+		members, err = mustBeBulkStrings(client, process(client, newCmd(zrangeByArgs("ZRANGEBYSCORE", key, min.String(), max.String(), false, options)...)))
 		return err
 	})
 	return
 }
 
 func (r *redisImpl) ZRangeByScoreWithScores(key string, min ScoreRange, max ScoreRange, options ...RangeOption) (mss MemberAndScores, err error) {
-	zrangeby := redis.ZRangeBy{
-		Min: min.String(),
-		Max: max.String(),
-	}
-
-	for _, opt := range options {
-		opt.fillZRangeBy(&zrangeby)
-	}
-
 	err = r.do("ZRANGEBYSCORE-WITHSCORES", func(client driver.Client) error {
-		mss, err = mustBeMemberAndScores(client, client.ZRangeByScoreWithScores(key, zrangeby))
+		mss, err = mustBeMemberAndScores(client, process(client, newCmdWith(parseMemberAndScores, zrangeByArgs("ZRANGEBYSCORE", key, min.String(), max.String(), true, options)...)))
 		return err
 	})
 	return
@@ -224,18 +176,7 @@ func (r *redisImpl) ZRangeByScoreWithScores(key string, min ScoreRange, max Scor
 
 func (r *redisImpl) ZRank(key string, member string) (rank int, exists bool, err error) {
 	err = r.do("ZRANK", func(client driver.Client) error {
-		cmd := redis.NewIntCmd("ZRANK", key, member)
-
-		if err = client.Process(cmd); err != nil {
-			if err != redis.Nil {
-				return err
-			}
-
-			return nil
-		}
-
-		exists = true
-		rank, err = mustBeInt(client, cmd)
+		rank, exists, err = mustBeIntOrNil(client, process(client, newCmd("ZRANK", key, member)))
 		return err
 	})
 	return
@@ -246,14 +187,8 @@ func (r *redisImpl) ZRem(key string, members ...string) (removed int, err error)
 		return
 	}
 
-	data := make([]interface{}, 0, len(members))
-
-	for _, m := range members {
-		data = append(data, m)
-	}
-
 	err = r.do("ZREM", func(client driver.Client) error {
-		removed, err = mustBeInt(client, client.ZRem(key, data...))
+		removed, err = mustBeInt(client, process(client, newCmd(appendArgs([]interface{}{"ZREM", key}, members)...)))
 		return err
 	})
 	return
@@ -261,7 +196,7 @@ func (r *redisImpl) ZRem(key string, members ...string) (removed int, err error)
 
 func (r *redisImpl) ZRemRangeByLex(key string, min MemberRange, max MemberRange) (removed int, err error) {
 	err = r.do("ZREMRANGEBYLEX", func(client driver.Client) error {
-		removed, err = mustBeInt(client, client.ZRemRangeByLex(key, min.String(), max.String()))
+		removed, err = mustBeInt(client, process(client, newCmd("ZREMRANGEBYLEX", key, min.String(), max.String())))
 		return err
 	})
 	return
@@ -269,7 +204,7 @@ func (r *redisImpl) ZRemRangeByLex(key string, min MemberRange, max MemberRange)
 
 func (r *redisImpl) ZRemRangeByRank(key string, start int, stop int) (removed int, err error) {
 	err = r.do("ZREMRANGEBYRANK", func(client driver.Client) error {
-		removed, err = mustBeInt(client, client.ZRemRangeByRank(key, int64(start), int64(stop)))
+		removed, err = mustBeInt(client, process(client, newCmd("ZREMRANGEBYRANK", key, start, stop)))
 		return err
 	})
 	return
@@ -277,7 +212,7 @@ func (r *redisImpl) ZRemRangeByRank(key string, start int, stop int) (removed in
 
 func (r *redisImpl) ZRemRangeByScore(key string, min ScoreRange, max ScoreRange) (removed int, err error) {
 	err = r.do("ZREMRANGEBYSCORE", func(client driver.Client) error {
-		removed, err = mustBeInt(client, client.ZRemRangeByScore(key, min.String(), max.String()))
+		removed, err = mustBeInt(client, process(client, newCmd("ZREMRANGEBYSCORE", key, min.String(), max.String())))
 		return err
 	})
 	return
@@ -285,13 +220,7 @@ func (r *redisImpl) ZRemRangeByScore(key string, min ScoreRange, max ScoreRange)
 
 func (r *redisImpl) ZRevRange(key string, start float64, stop float64) (members []BulkString, err error) {
 	err = r.do("ZREVRANGE", func(client driver.Client) error {
-		cmd := redis.NewStringSliceCmd("ZREVRANGE", key, start, stop)
-
-		if err = client.Process(cmd); err != nil {
-			return err
-		}
-
-		members, err = mustBeBulkStrings(client, cmd)
+		members, err = mustBeBulkStrings(client, process(client, newCmd("ZREVRANGE", key, start, stop)))
 		return err
 	})
 	return
@@ -299,64 +228,31 @@ func (r *redisImpl) ZRevRange(key string, start float64, stop float64) (members
 
 func (r *redisImpl) ZRevRangeWithScores(key string, start float64, stop float64) (mss MemberAndScores, err error) {
 	err = r.do("ZREVRANGE-WITHSCORES", func(client driver.Client) error {
-		cmd := redis.NewSliceCmd("ZREVRANGE", key, start, stop, "WITHSCORES")
-
-		if err = client.Process(cmd); err != nil {
-			return err
-		}
-
-		mss, err = mustBeMemberAndScores(client, cmd)
+		mss, err = mustBeMemberAndScores(client, process(client, newCmdWith(parseMemberAndScores, "ZREVRANGE", key, start, stop, "WITHSCORES")))
 		return err
 	})
 	return
 }
 
 func (r *redisImpl) ZRevRangeByLex(key string, min MemberRange, max MemberRange, options ...RangeOption) (members []BulkString, err error) {
-	zrangeby := redis.ZRangeBy{
-		Min: min.String(),
-		Max: max.String(),
-	}
-
-	for _, opt := range options {
-		opt.fillZRangeBy(&zrangeby)
-	}
-
 	err = r.do("ZREVRANGEBYLEX", func(client driver.Client) error {
-		members, err = mustBeBulkStrings(client, client.ZRevRangeByLex(key, zrangeby))
+		members, err = mustBeBulkStrings(client, process(client, newCmd(zrangeByArgs("ZREVRANGEBYLEX", key, max.String(), min.String(), false, options)...)))
 		return err
 	})
 	return
 }
 
 func (r *redisImpl) ZRevRangeByScore(key string, min ScoreRange, max ScoreRange, options ...RangeOption) (members []BulkString, err error) {
-	zrangeby := redis.ZRangeBy{
-		Min: min.String(),
-		Max: max.String(),
-	}
-
-	for _, opt := range options {
-		opt.fillZRangeBy(&zrangeby)
-	}
-
 	err = r.do("ZREVRANGEBYSCORE", func(client driver.Client) error {
-		members, err = mustBeBulkStrings(client, client.ZRevRangeByScore(key, zrangeby))
+		members, err = mustBeBulkStrings(client, process(client, newCmd(zrangeByArgs("ZREVRANGEBYSCORE", key, max.String(), min.String(), false, options)...)))
 		return err
 	})
 	return
 }
 
 func (r *redisImpl) ZRevRangeByScoreWithScores(key string, min ScoreRange, max ScoreRange, options ...RangeOption) (mss MemberAndScores, err error) {
-	zrangeby := redis.ZRangeBy{
-		Min: min.String(),
-		Max: max.String(),
-	}
-
-	for _, opt := range options {
-		opt.fillZRangeBy(&zrangeby)
-	}
-
 	err = r.do("ZREVRANGEBYSCORE-WITHSCORES", func(client driver.Client) error {
-		mss, err = mustBeMemberAndScores(client, client.ZRevRangeByScoreWithScores(key, zrangeby))
+		mss, err = mustBeMemberAndScores(client, process(client, newCmdWith(parseMemberAndScores, zrangeByArgs("ZREVRANGEBYSCORE", key, max.String(), min.String(), true, options)...)))
 		return err
 	})
 	return
@@ -364,18 +260,7 @@ func (r *redisImpl) ZRevRangeByScoreWithScores(key string, min ScoreRange, max S
 
 func (r *redisImpl) ZRevRank(key string, member string) (rank int, exists bool, err error) {
 	err = r.do("ZREVRANK", func(client driver.Client) error {
-		cmd := redis.NewIntCmd("ZREVRANK", key, member)
-
-		if err = client.Process(cmd); err != nil {
-			if err != redis.Nil {
-				return err
-			}
-
-			return nil
-		}
-
-		exists = true
-		rank, err = mustBeInt(client, cmd)
+		rank, exists, err = mustBeIntOrNil(client, process(client, newCmd("ZREVRANK", key, member)))
 		return err
 	})
 	return
@@ -383,18 +268,7 @@ func (r *redisImpl) ZRevRank(key string, member string) (rank int, exists bool,
 
 func (r *redisImpl) ZScore(key string, member string) (score float64, exists bool, err error) {
 	err = r.do("ZSCORE", func(client driver.Client) error {
-		cmd := redis.NewFloatCmd("ZSCORE", key, member)
-
-		if err = client.Process(cmd); err != nil {
-			if err != redis.Nil {
-				return err
-			}
-
-			return nil
-		}
-
-		exists = true
-		score, err = mustBeFloat64(client, cmd)
+		score, exists, err = mustBeFloat64OrNil(client, process(client, newCmdWith(parseFloat, "ZSCORE", key, member)))
 		return err
 	})
 	return
@@ -405,15 +279,39 @@ func (r *redisImpl) ZUnionStore(dst string, keys []string, options ...StoreOptio
 		return
 	}
 
-	var zstore redis.ZStore
-
-	for _, opt := range options {
-		opt.fillZStore(&zstore)
-	}
-
 	err = r.do("ZUNIONSTORE", func(client driver.Client) error {
-		count, err = mustBeInt(client, client.ZUnionStore(dst, zstore, keys...))
+		count, err = mustBeInt(client, process(client, newCmd(zstoreArgs("ZUNIONSTORE", dst, keys, options)...)))
 		return err
 	})
 	return
 }
+
+// zstoreArgs 生成 ZINTERSTORE 和 ZUNIONSTORE 的参数。
+func zstoreArgs(name string, dst string, keys []string, options []StoreOption) []interface{} {
+	args := make([]interface{}, 0, 3+len(keys)+2*len(options))
+	args = append(args, name, dst, len(keys))
+	args = appendArgs(args, keys)
+
+	for _, opt := range options {
+		args = append(args, opt.Args()...)
+	}
+
+	return args
+}
+
+// zrangeByArgs 生成 ZRANGEBYSCORE、ZRANGEBYLEX 等命令的参数，
+// 对于 ZREV* 系列命令，调用者需要自己交换 min 和 max。
+func zrangeByArgs(name string, key string, min, max string, withScores bool, options []RangeOption) []interface{} {
+	args := make([]interface{}, 0, 8)
+	args = append(args, name, key, min, max)
+
+	if withScores {
+		args = append(args, "WITHSCORES")
+	}
+
+	for _, opt := range options {
+		args = append(args, opt.Args()...)
+	}
+
+	return args
+}
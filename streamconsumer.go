@@ -0,0 +1,243 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/altstory/go-log"
+	"github.com/altstory/go-runner"
+)
+
+const (
+	// DefaultStreamConsumerBatchSize 是 StreamConsumer 每次读取 entry 的默认数量。
+	DefaultStreamConsumerBatchSize = 10
+
+	// DefaultStreamConsumerBlock 是 StreamConsumer 每次 XREADGROUP 的默认阻塞时间。
+	DefaultStreamConsumerBlock = 5 * time.Second
+
+	// DefaultStreamConsumerClaimMinIdle 是 StreamConsumer 认领其他 consumer 未确认 entry 的默认闲置时间。
+	DefaultStreamConsumerClaimMinIdle = time.Minute
+
+	// DefaultStreamConsumerClaimInterval 是 StreamConsumer 检查未确认 entry 的默认间隔。
+	DefaultStreamConsumerClaimInterval = 10 * time.Second
+
+	// DefaultStreamConsumerRetryBackoff 是 StreamConsumer 遇到 Redis 错误后的默认重试间隔。
+	DefaultStreamConsumerRetryBackoff = time.Second
+)
+
+var (
+	// ErrStreamConsumerStarted 代表 StreamConsumer 已经启动过了。
+	ErrStreamConsumerStarted = errors.New("go-redis: stream consumer is already started")
+)
+
+// StreamHandler 是 StreamConsumer 处理 entry 的函数。
+// 返回 nil 时 entry 会被 XACK 确认，否则 entry 会留在 pending 列表中，
+// 在闲置超过 ClaimMinIdle 之后被重新认领和处理。
+type StreamHandler func(ctx context.Context, stream string, entry StreamEntry) error
+
+// StreamConsumerConfig 代表 StreamConsumer 的配置。
+type StreamConsumerConfig struct {
+	Stream   string `config:"stream"`   // Stream 是需要消费的 stream 的 key。
+	Group    string `config:"group"`    // Group 是 consumer group 名字，如果不存在会自动创建。
+	Consumer string `config:"consumer"` // Consumer 是 consumer 名字，同一个 group 里的 consumer 名字需要唯一。
+
+	BatchSize     int           `config:"batch_size"`     // BatchSize 是每次读取 entry 的数量，默认是 DefaultStreamConsumerBatchSize。
+	Block         time.Duration `config:"block"`          // Block 是每次 XREADGROUP 的阻塞时间，默认是 DefaultStreamConsumerBlock。
+	ClaimMinIdle  time.Duration `config:"claim_min_idle"` // ClaimMinIdle 是 entry 闲置多久之后会被重新认领，默认是 DefaultStreamConsumerClaimMinIdle。
+	ClaimInterval time.Duration `config:"claim_interval"` // ClaimInterval 是检查闲置 entry 的间隔，默认是 DefaultStreamConsumerClaimInterval。
+}
+
+// StreamConsumer 在一个独立的 goroutine 里循环读取 consumer group 中的 entry 并交给 handler 处理。
+type StreamConsumer struct {
+	factory *Factory
+	config  StreamConsumerConfig
+	handler StreamHandler
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewStreamConsumer 创建一个新的 StreamConsumer，需要调用 Start 之后才会开始消费。
+func NewStreamConsumer(f *Factory, config *StreamConsumerConfig, handler StreamHandler) *StreamConsumer {
+	c := *config
+
+	if c.BatchSize <= 0 {
+		c.BatchSize = DefaultStreamConsumerBatchSize
+	}
+
+	if c.Block <= 0 {
+		c.Block = DefaultStreamConsumerBlock
+	}
+
+	if c.ClaimMinIdle <= 0 {
+		c.ClaimMinIdle = DefaultStreamConsumerClaimMinIdle
+	}
+
+	if c.ClaimInterval <= 0 {
+		c.ClaimInterval = DefaultStreamConsumerClaimInterval
+	}
+
+	return &StreamConsumer{
+		factory: f,
+		config:  c,
+		handler: handler,
+	}
+}
+
+// Start 创建 consumer group（如果不存在）并启动消费循环。
+// 消费循环会一直运行，直到 ctx 被取消或者调用了 Stop。
+func (sc *StreamConsumer) Start(ctx context.Context) error {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	if sc.done != nil {
+		return ErrStreamConsumerStarted
+	}
+
+	r := sc.factory.New(ctx)
+
+	if r == nil {
+		return errors.New("go-redis: factory is not initialized")
+	}
+
+	if err := r.XGroupCreateMkStream(sc.config.Stream, sc.config.Group, "0"); err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	sc.cancel = cancel
+	sc.done = make(chan struct{})
+	go sc.loop(ctx, sc.done)
+	return nil
+}
+
+// Stop 停止消费循环，并等待正在处理的 entry 处理完毕。
+func (sc *StreamConsumer) Stop() {
+	sc.mu.Lock()
+	cancel := sc.cancel
+	done := sc.done
+	sc.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+
+	cancel()
+	<-done
+}
+
+func (sc *StreamConsumer) loop(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	config := &sc.config
+	claimCursor := "0-0"
+	lastClaim := time.Now()
+
+	for ctx.Err() == nil {
+		r := sc.factory.New(ctx)
+
+		if time.Since(lastClaim) >= config.ClaimInterval {
+			next, entries, err := r.XAutoClaim(config.Stream, config.Group, config.Consumer, config.ClaimMinIdle, claimCursor, config.BatchSize)
+
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+
+				// 认领失败可能是持续的，比如服务器不支持 XAUTOCLAIM 或者没有权限，
+				// 这里不能阻止读取新的 entry，等下个周期再重新认领。
+				log.Errorf(ctx, "err=%v||stream=%v||group=%v||consumer=%v||go-redis: fail to claim idle stream entries", err, config.Stream, config.Group, config.Consumer)
+				claimCursor = "0-0"
+				lastClaim = time.Now()
+				continue
+			}
+
+			sc.handle(ctx, r, entries)
+
+			// 游标回到 0-0 代表已经扫描完整个 pending 列表，等下个周期再开始。
+			if claimCursor = next; claimCursor == "0-0" || claimCursor == "" {
+				claimCursor = "0-0"
+				lastClaim = time.Now()
+			}
+
+			continue
+		}
+
+		streams, err := r.XReadGroup(config.Group, config.Consumer, []string{config.Stream}, []string{">"}, ReadCount(config.BatchSize), Block(config.Block))
+
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+
+			log.Errorf(ctx, "err=%v||stream=%v||group=%v||consumer=%v||go-redis: fail to read stream entries", err, config.Stream, config.Group, config.Consumer)
+			sc.backoff(ctx)
+			continue
+		}
+
+		for _, s := range streams {
+			sc.handle(ctx, r, s.Entries)
+		}
+	}
+}
+
+func (sc *StreamConsumer) handle(ctx context.Context, r Redis, entries StreamEntries) {
+	config := &sc.config
+
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err := sc.call(ctx, entry); err != nil {
+			log.Errorf(ctx, "err=%v||stream=%v||group=%v||consumer=%v||id=%v||go-redis: fail to handle stream entry", err, config.Stream, config.Group, config.Consumer, entry.ID)
+			continue
+		}
+
+		if _, err := r.XAck(config.Stream, config.Group, entry.ID); err != nil {
+			log.Errorf(ctx, "err=%v||stream=%v||group=%v||consumer=%v||id=%v||go-redis: fail to ack stream entry", err, config.Stream, config.Group, config.Consumer, entry.ID)
+		}
+	}
+}
+
+func (sc *StreamConsumer) call(ctx context.Context, entry StreamEntry) (err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			err = fmt.Errorf("go-redis: stream handler panics: %v", e)
+		}
+	}()
+
+	return sc.handler(ctx, sc.config.Stream, entry)
+}
+
+func (sc *StreamConsumer) backoff(ctx context.Context) {
+	timer := time.NewTimer(DefaultStreamConsumerRetryBackoff)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
+
+// AddStreamConsumer 在 runner 启动时用 factory 创建并启动一个 StreamConsumer，
+// 在 runner 退出时停止这个 StreamConsumer。
+// 和 Register 一样，factory 在 AddClient 周期结束前并不可用，所以这里传入的是 Register 的返回值。
+func AddStreamConsumer(factory **Factory, config *StreamConsumerConfig, handler StreamHandler) {
+	var sc *StreamConsumer
+
+	runner.OnStart(func(ctx context.Context) error {
+		sc = NewStreamConsumer(*factory, config, handler)
+		return sc.Start(ctx)
+	})
+	runner.OnExit(func(ctx context.Context) {
+		if sc != nil {
+			sc.Stop()
+		}
+	})
+}
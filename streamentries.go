@@ -0,0 +1,49 @@
+package redis
+
+// Stream 相关命令中常用的特殊 ID。
+const (
+	StreamAutoID     = "*" // StreamAutoID 用于 XADD，让 Redis 自动生成 ID。
+	StreamRangeStart = "-" // StreamRangeStart 代表 stream 中最小的 ID。
+	StreamRangeStop  = "+" // StreamRangeStop 代表 stream 中最大的 ID。
+	StreamLastID     = "$" // StreamLastID 用于 XREAD，代表只读取新加入的 entry。
+)
+
+// StreamEntries 代表一系列 stream entry。
+type StreamEntries []StreamEntry
+
+// IDs 返回所有 entry 的 ID。
+func (ses StreamEntries) IDs() []string {
+	if len(ses) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(ses))
+
+	for _, se := range ses {
+		ids = append(ids, se.ID)
+	}
+
+	return ids
+}
+
+// StreamEntry 代表 stream 中的一个 entry。
+//
+// 为了让结果稳定，Fields 统一按照字段名排序，不一定与写入时的顺序一致。
+type StreamEntry struct {
+	ID     string
+	Fields KeyAndValues
+}
+
+// MakeStreamEntry 可以方便的创建一个 StreamEntry 实例。
+func MakeStreamEntry(id string, fields ...KeyAndValue) StreamEntry {
+	return StreamEntry{
+		ID:     id,
+		Fields: fields,
+	}
+}
+
+// StreamAndEntries 代表从一个 stream 中读取到的所有 entry。
+type StreamAndEntries struct {
+	Stream  string
+	Entries StreamEntries
+}
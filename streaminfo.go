@@ -0,0 +1,133 @@
+package redis
+
+import (
+	"sort"
+	"strconv"
+	"time"
+)
+
+// StreamPending 代表 XPENDING 返回的 consumer group 待确认 entry 的汇总信息。
+type StreamPending struct {
+	Count     int            // Count 是待确认的 entry 总数。
+	Lower     string         // Lower 是待确认的 entry 中最小的 ID。
+	Higher    string         // Higher 是待确认的 entry 中最大的 ID。
+	Consumers map[string]int // Consumers 是每个 consumer 待确认的 entry 数量。
+}
+
+// StreamPendingEntry 代表 XPENDING 扩展形式返回的一个待确认 entry。
+type StreamPendingEntry struct {
+	ID            string        // ID 是 entry 的 ID。
+	Consumer      string        // Consumer 是当前持有这个 entry 的 consumer。
+	Idle          time.Duration // Idle 是距离这个 entry 上次被读取的时间。
+	DeliveryCount int           // DeliveryCount 是这个 entry 被读取的次数。
+}
+
+// StreamInfo 代表 XINFO STREAM 返回的 stream 信息。
+type StreamInfo struct {
+	Length          int         // Length 是 stream 中 entry 的数量。
+	RadixTreeKeys   int         // RadixTreeKeys 是底层 radix tree 的 key 数量。
+	RadixTreeNodes  int         // RadixTreeNodes 是底层 radix tree 的节点数量。
+	Groups          int         // Groups 是 consumer group 的数量。
+	LastGeneratedID string      // LastGeneratedID 是最后一个生成的 ID。
+	FirstEntry      StreamEntry // FirstEntry 是 stream 中第一个 entry，如果 stream 为空，这个值为零值。
+	LastEntry       StreamEntry // LastEntry 是 stream 中最后一个 entry，如果 stream 为空，这个值为零值。
+}
+
+func parseStreamInfo(mvs []MultiValue) (info StreamInfo) {
+	m := parseMultiValueMap(mvs)
+	info.Length, _ = m["length"].Int()
+	info.RadixTreeKeys, _ = m["radix-tree-keys"].Int()
+	info.RadixTreeNodes, _ = m["radix-tree-nodes"].Int()
+	info.Groups, _ = m["groups"].Int()
+	info.LastGeneratedID = multiValueString(m["last-generated-id"])
+
+	if entry, ok := m["first-entry"].MultiValues(); ok && len(entry) != 0 {
+		info.FirstEntry = parseStreamEntry(entry)
+	}
+
+	if entry, ok := m["last-entry"].MultiValues(); ok && len(entry) != 0 {
+		info.LastEntry = parseStreamEntry(entry)
+	}
+
+	return
+}
+
+// StreamGroupInfo 代表 XINFO GROUPS 返回的 consumer group 信息。
+type StreamGroupInfo struct {
+	Name            string // Name 是 consumer group 的名字。
+	Consumers       int    // Consumers 是 consumer group 里 consumer 的数量。
+	Pending         int    // Pending 是 consumer group 里待确认 entry 的数量。
+	LastDeliveredID string // LastDeliveredID 是最后一个被读取的 entry 的 ID。
+}
+
+func parseStreamGroupInfo(mvs []MultiValue) (info StreamGroupInfo) {
+	m := parseMultiValueMap(mvs)
+	info.Name = multiValueString(m["name"])
+	info.Consumers, _ = m["consumers"].Int()
+	info.Pending, _ = m["pending"].Int()
+	info.LastDeliveredID = multiValueString(m["last-delivered-id"])
+	return
+}
+
+// StreamConsumerInfo 代表 XINFO CONSUMERS 返回的 consumer 信息。
+type StreamConsumerInfo struct {
+	Name    string        // Name 是 consumer 的名字。
+	Pending int           // Pending 是 consumer 待确认 entry 的数量。
+	Idle    time.Duration // Idle 是距离 consumer 上次读取 entry 的时间。
+}
+
+func parseStreamConsumerInfo(mvs []MultiValue) (info StreamConsumerInfo) {
+	m := parseMultiValueMap(mvs)
+	info.Name = multiValueString(m["name"])
+	info.Pending, _ = m["pending"].Int()
+
+	if idle, ok := m["idle"].Int64(); ok {
+		info.Idle = time.Duration(idle) * time.Millisecond
+	}
+
+	return
+}
+
+// parseStreamEntry 解析形如 [id, [field1, value1, ...]] 的 entry。
+func parseStreamEntry(mvs []MultiValue) (se StreamEntry) {
+	if len(mvs) != 2 {
+		panic(ErrUnexpectedResponseType)
+	}
+
+	se.ID = multiValueString(mvs[0])
+	values, _ := mvs[1].MultiValues()
+	se.Fields = make(KeyAndValues, 0, len(values)/2)
+
+	for i := 0; i+1 < len(values); i += 2 {
+		se.Fields = append(se.Fields, KeyAndValue{
+			Key:   multiValueString(values[i]),
+			Value: multiValueString(values[i+1]),
+		})
+	}
+
+	sort.Slice(se.Fields, func(i, j int) bool {
+		return se.Fields[i].Key < se.Fields[j].Key
+	})
+	return
+}
+
+// parseMultiValueMap 将形如 [key1, value1, key2, value2, ...] 的应答转化成 map。
+func parseMultiValueMap(mvs []MultiValue) map[string]MultiValue {
+	m := make(map[string]MultiValue, len(mvs)/2)
+
+	for i := 0; i+1 < len(mvs); i += 2 {
+		m[multiValueString(mvs[i])] = mvs[i+1]
+	}
+
+	return m
+}
+
+// multiValueString 将字符串应答转成 string，RESP3 的 double 也会转成字符串形式。
+func multiValueString(mv MultiValue) string {
+	if f, ok := mv.Float64(); ok {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+
+	bs, _ := mv.BulkString()
+	return bs.String()
+}
@@ -1,6 +1,410 @@
 package redis
 
+import (
+	"errors"
+	"time"
+
+	"github.com/altstory/go-redis/internal/driver"
+)
+
+var (
+	// ErrStreamIDsMismatch 表示 XREAD 等命令传入的 stream 数量与 ID 数量不一致。
+	ErrStreamIDsMismatch = errors.New("go-redis: number of streams and ids mismatch")
+)
+
 // Streams 代表 Redis 跟 stream 相关的接口，详见 https://redis.io/commands#stream。
 type Streams interface {
-	// TODO:
+	XAck(key string, group string, ids ...string) (acked int, err error)
+	XAdd(key string, id string, fields []KeyAndValue, options ...TrimOption) (addedID string, err error)
+	XAutoClaim(key string, group string, consumer string, minIdle time.Duration, start string, count int) (next string, entries StreamEntries, err error) // XAUTOCLAIM key group consumer min-idle-time start [COUNT count]，count 不大于 0 时使用 Redis 的默认值
+	XClaim(key string, group string, consumer string, minIdle time.Duration, ids ...string) (entries StreamEntries, err error)
+	XClaimJustID(key string, group string, consumer string, minIdle time.Duration, ids ...string) (claimedIDs []string, err error) // XCLAIM ... JUSTID
+	XDel(key string, ids ...string) (deleted int, err error)
+	XGroupCreate(key string, group string, id string) (err error)
+	XGroupCreateMkStream(key string, group string, id string) (err error) // XGROUP CREATE key group id MKSTREAM
+	XGroupDelConsumer(key string, group string, consumer string) (pending int, err error)
+	XGroupDestroy(key string, group string) (destroyed bool, err error)
+	XGroupSetID(key string, group string, id string) (err error)
+	XInfoConsumers(key string, group string) (consumers []StreamConsumerInfo, err error)
+	XInfoGroups(key string) (groups []StreamGroupInfo, err error)
+	XInfoStream(key string) (info StreamInfo, err error)
+	XLen(key string) (l int, err error)
+	XPending(key string, group string) (pending StreamPending, err error)
+	XPendingExt(key string, group string, start string, end string, count int, options ...PendingOption) (entries []StreamPendingEntry, err error) // XPENDING key group [IDLE min-idle] start end count [consumer]
+	XRange(key string, start string, end string) (entries StreamEntries, err error)
+	XRangeN(key string, start string, end string, count int) (entries StreamEntries, err error) // XRANGE key start end COUNT count
+	XRead(keys []string, ids []string, options ...StreamReadOption) (streams []StreamAndEntries, err error)
+	XReadGroup(group string, consumer string, keys []string, ids []string, options ...StreamReadOption) (streams []StreamAndEntries, err error)
+	XRevRange(key string, end string, start string) (entries StreamEntries, err error)
+	XRevRangeN(key string, end string, start string, count int) (entries StreamEntries, err error) // XREVRANGE key end start COUNT count
+	XTrim(key string, option TrimOption) (deleted int, err error)
+}
+
+func (r *redisImpl) XAck(key string, group string, ids ...string) (acked int, err error) {
+	if len(ids) == 0 {
+		return
+	}
+
+	err = r.do("XACK", func(client driver.Client) error {
+		acked, err = mustBeInt(client, process(client, newCmd(appendArgs([]interface{}{"XACK", key, group}, ids)...)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) XAdd(key string, id string, fields []KeyAndValue, options ...TrimOption) (addedID string, err error) {
+	if len(fields) == 0 {
+		return
+	}
+
+	if id == "" {
+		id = StreamAutoID
+	}
+
+	err = r.do("XADD", func(client driver.Client) error {
+		args := make([]interface{}, 0, 6+2*len(fields))
+		args = append(args, "XADD", key)
+
+		for _, opt := range options {
+			args = append(args, opt.Args()...)
+		}
+
+		args = append(args, id)
+
+		for _, fv := range fields {
+			args = append(args, fv.Key, fv.Value)
+		}
+
+		var bs BulkString
+		bs, err = mustBeBulkString(client, process(client, newCmd(args...)))
+		addedID = bs.String()
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) XAutoClaim(key string, group string, consumer string, minIdle time.Duration, start string, count int) (next string, entries StreamEntries, err error) {
+	args := []interface{}{"XAUTOCLAIM", key, group, consumer, int64(minIdle / time.Millisecond), start}
+
+	if count > 0 {
+		args = append(args, "COUNT", count)
+	}
+
+	err = r.do("XAUTOCLAIM", func(client driver.Client) error {
+		next, entries, err = mustBeAutoClaimed(client, process(client, newCmd(args...)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) XClaim(key string, group string, consumer string, minIdle time.Duration, ids ...string) (entries StreamEntries, err error) {
+	if len(ids) == 0 {
+		return
+	}
+
+	args := make([]interface{}, 0, 6+len(ids))
+	args = append(args, "XCLAIM", key, group, consumer, int64(minIdle/time.Millisecond))
+	args = appendArgs(args, ids)
+
+	err = r.do("XCLAIM", func(client driver.Client) error {
+		entries, err = mustBeStreamEntries(client, process(client, newCmdWith(parseStreamEntries, args...)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) XClaimJustID(key string, group string, consumer string, minIdle time.Duration, ids ...string) (claimedIDs []string, err error) {
+	if len(ids) == 0 {
+		return
+	}
+
+	args := make([]interface{}, 0, 6+len(ids))
+	args = append(args, "XCLAIM", key, group, consumer, int64(minIdle/time.Millisecond))
+	args = appendArgs(args, ids)
+	args = append(args, "JUSTID")
+
+	err = r.do("XCLAIM-JUSTID", func(client driver.Client) error {
+		claimedIDs, err = mustBeStrings(client, process(client, newCmd(args...)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) XDel(key string, ids ...string) (deleted int, err error) {
+	if len(ids) == 0 {
+		return
+	}
+
+	err = r.do("XDEL", func(client driver.Client) error {
+		deleted, err = mustBeInt(client, process(client, newCmd(appendArgs([]interface{}{"XDEL", key}, ids)...)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) XGroupCreate(key string, group string, id string) (err error) {
+	err = r.do("XGROUP CREATE", func(client driver.Client) error {
+		_, err = mustBeStatus(client, process(client, newCmd("XGROUP", "CREATE", key, group, id)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) XGroupCreateMkStream(key string, group string, id string) (err error) {
+	err = r.do("XGROUP CREATE-MKSTREAM", func(client driver.Client) error {
+		_, err = mustBeStatus(client, process(client, newCmd("XGROUP", "CREATE", key, group, id, "MKSTREAM")))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) XGroupDelConsumer(key string, group string, consumer string) (pending int, err error) {
+	err = r.do("XGROUP DELCONSUMER", func(client driver.Client) error {
+		pending, err = mustBeInt(client, process(client, newCmd("XGROUP", "DELCONSUMER", key, group, consumer)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) XGroupDestroy(key string, group string) (destroyed bool, err error) {
+	err = r.do("XGROUP DESTROY", func(client driver.Client) error {
+		destroyed, err = mustBeBool(client, process(client, newCmdWith(parseBool, "XGROUP", "DESTROY", key, group)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) XGroupSetID(key string, group string, id string) (err error) {
+	err = r.do("XGROUP SETID", func(client driver.Client) error {
+		_, err = mustBeStatus(client, process(client, newCmd("XGROUP", "SETID", key, group, id)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) XInfoConsumers(key string, group string) (consumers []StreamConsumerInfo, err error) {
+	err = r.do("XINFO CONSUMERS", func(client driver.Client) error {
+		var mvs []MultiValue
+		mvs, err = mustBeMultiValues(client, process(client, newCmd("XINFO", "CONSUMERS", key, group)))
+
+		for _, mv := range mvs {
+			info, _ := mv.MultiValues()
+			consumers = append(consumers, parseStreamConsumerInfo(info))
+		}
+
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) XInfoGroups(key string) (groups []StreamGroupInfo, err error) {
+	err = r.do("XINFO GROUPS", func(client driver.Client) error {
+		var mvs []MultiValue
+		mvs, err = mustBeMultiValues(client, process(client, newCmd("XINFO", "GROUPS", key)))
+
+		for _, mv := range mvs {
+			info, _ := mv.MultiValues()
+			groups = append(groups, parseStreamGroupInfo(info))
+		}
+
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) XInfoStream(key string) (info StreamInfo, err error) {
+	err = r.do("XINFO STREAM", func(client driver.Client) error {
+		var mvs []MultiValue
+		mvs, err = mustBeMultiValues(client, process(client, newCmd("XINFO", "STREAM", key)))
+
+		if err != nil {
+			return err
+		}
+
+		info = parseStreamInfo(mvs)
+		return nil
+	})
+	return
+}
+
+func (r *redisImpl) XLen(key string) (l int, err error) {
+	err = r.do("XLEN", func(client driver.Client) error {
+		l, err = mustBeInt(client, process(client, newCmd("XLEN", key)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) XPending(key string, group string) (pending StreamPending, err error) {
+	err = r.do("XPENDING", func(client driver.Client) error {
+		pending, err = mustBeStreamPending(client, process(client, newCmdWith(parseStreamPending, "XPENDING", key, group)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) XPendingExt(key string, group string, start string, end string, count int, options ...PendingOption) (entries []StreamPendingEntry, err error) {
+	err = r.do("XPENDING-EXT", func(client driver.Client) error {
+		args := make([]interface{}, 0, 9)
+		args = append(args, "XPENDING", key, group)
+		consumer := ""
+
+		for _, opt := range options {
+			switch opt.t {
+			case pendingOptionMinIdle:
+				args = append(args, "IDLE", int64(opt.opt.(time.Duration)/time.Millisecond))
+			case pendingOptionConsumer:
+				consumer = opt.opt.(string)
+			}
+		}
+
+		args = append(args, start, end, count)
+
+		if consumer != "" {
+			args = append(args, consumer)
+		}
+
+		entries, err = mustBeStreamPendingEntries(client, process(client, newCmdWith(parseStreamPendingEntries, args...)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) XRange(key string, start string, end string) (entries StreamEntries, err error) {
+	err = r.do("XRANGE", func(client driver.Client) error {
+		entries, err = mustBeStreamEntries(client, process(client, newCmdWith(parseStreamEntries, "XRANGE", key, start, end)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) XRangeN(key string, start string, end string, count int) (entries StreamEntries, err error) {
+	err = r.do("XRANGE-N", func(client driver.Client) error {
+		entries, err = mustBeStreamEntries(client, process(client, newCmdWith(parseStreamEntries, "XRANGE", key, start, end, "COUNT", count)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) XRead(keys []string, ids []string, options ...StreamReadOption) (streams []StreamAndEntries, err error) {
+	if len(keys) != len(ids) {
+		err = ErrStreamIDsMismatch
+		return
+	}
+
+	if len(keys) == 0 {
+		return
+	}
+
+	args := make([]interface{}, 0, 6+2*len(keys))
+	args = append(args, "XREAD")
+	block, blocking := time.Duration(0), false
+
+	for _, opt := range options {
+		switch opt.t {
+		case streamReadOptionCount:
+			args = append(args, "COUNT", opt.opt)
+		case streamReadOptionBlock:
+			block, blocking = r.blockTimeout(opt.opt.(time.Duration)), true
+			args = append(args, "BLOCK", int64(block/time.Millisecond))
+		}
+	}
+
+	args = append(args, "STREAMS")
+	args = appendArgs(args, keys)
+	args = appendArgs(args, ids)
+
+	err = r.do("XREAD", func(client driver.Client) error {
+		cmder, err := r.processBlocking(client, newStreamReadCmd(block, blocking, args))
+
+		if err != nil {
+			return err
+		}
+
+		streams, err = mustBeStreamAndEntriesList(client, cmder)
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) XReadGroup(group string, consumer string, keys []string, ids []string, options ...StreamReadOption) (streams []StreamAndEntries, err error) {
+	if len(keys) != len(ids) {
+		err = ErrStreamIDsMismatch
+		return
+	}
+
+	if len(keys) == 0 {
+		return
+	}
+
+	args := make([]interface{}, 0, 10+2*len(keys))
+	args = append(args, "XREADGROUP", "GROUP", group, consumer)
+	block, blocking := time.Duration(0), false
+
+	for _, opt := range options {
+		switch opt.t {
+		case streamReadOptionCount:
+			args = append(args, "COUNT", opt.opt)
+		case streamReadOptionBlock:
+			block, blocking = r.blockTimeout(opt.opt.(time.Duration)), true
+			args = append(args, "BLOCK", int64(block/time.Millisecond))
+		case streamReadOptionNoAck:
+			args = append(args, "NOACK")
+		}
+	}
+
+	args = append(args, "STREAMS")
+	args = appendArgs(args, keys)
+	args = appendArgs(args, ids)
+
+	err = r.do("XREADGROUP", func(client driver.Client) error {
+		cmder, err := r.processBlocking(client, newStreamReadCmd(block, blocking, args))
+
+		if err != nil {
+			return err
+		}
+
+		streams, err = mustBeStreamAndEntriesList(client, cmder)
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) XRevRange(key string, end string, start string) (entries StreamEntries, err error) {
+	err = r.do("XREVRANGE", func(client driver.Client) error {
+		entries, err = mustBeStreamEntries(client, process(client, newCmdWith(parseStreamEntries, "XREVRANGE", key, end, start)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) XRevRangeN(key string, end string, start string, count int) (entries StreamEntries, err error) {
+	err = r.do("XREVRANGE-N", func(client driver.Client) error {
+		entries, err = mustBeStreamEntries(client, process(client, newCmdWith(parseStreamEntries, "XREVRANGE", key, end, start, "COUNT", count)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) XTrim(key string, option TrimOption) (deleted int, err error) {
+	err = r.do("XTRIM", func(client driver.Client) error {
+		args := make([]interface{}, 0, 5)
+		args = append(args, "XTRIM", key)
+		args = append(args, option.Args()...)
+
+		deleted, err = mustBeInt(client, process(client, newCmd(args...)))
+		return err
+	})
+	return
+}
+
+// newStreamReadCmd 创建 XREAD 或 XREADGROUP 命令，如果设置了 BLOCK，读取应答时会相应延长读超时。
+func newStreamReadCmd(block time.Duration, blocking bool, args []interface{}) *command {
+	cmd := newCmdWith(parseStreamAndEntriesList, args...)
+
+	if blocking {
+		cmd.SetBlockTimeout(block)
+	}
+
+	return cmd
 }
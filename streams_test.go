@@ -0,0 +1,325 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/altstory/go-redis/internal/driver"
+	"github.com/huandu/go-assert"
+)
+
+func TestStreamMethods(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	f := factory(t)
+	r := f.New(ctx)
+	resetRedis(t, r)
+
+	key := "stream-key"
+	fields := []KeyAndValue{MakeKeyAndValue("a", "1"), MakeKeyAndValue("b", "2")}
+
+	id1, err := r.XAdd(key, "1-1", fields)
+	a.NilError(err)
+	a.Equal(id1, "1-1")
+
+	id2, err := r.XAdd(key, StreamAutoID, fields)
+	a.NilError(err)
+	a.Assert(id2 != "")
+
+	_, err = r.XAdd(key, StreamAutoID, fields, MaxLen(2))
+	a.NilError(err)
+
+	l, err := r.XLen(key)
+	a.NilError(err)
+	a.Equal(l, 2)
+
+	entries, err := r.XRange(key, StreamRangeStart, StreamRangeStop)
+	a.NilError(err)
+	a.Equal(len(entries), 2)
+	a.Equal(entries[0].ID, id2)
+	a.Equal(entries[0].Fields, KeyAndValues(fields))
+
+	entries, err = r.XRevRangeN(key, StreamRangeStop, StreamRangeStart, 1)
+	a.NilError(err)
+	a.Equal(len(entries), 1)
+	a.Assert(entries[0].ID != id2)
+
+	streams, err := r.XRead([]string{key}, []string{"0"}, ReadCount(1))
+	a.NilError(err)
+	a.Equal(len(streams), 1)
+	a.Equal(streams[0].Stream, key)
+	a.Equal(streams[0].Entries.IDs(), []string{id2})
+
+	streams, err = r.XRead([]string{key}, []string{StreamLastID}, Block(50*time.Millisecond))
+	a.NilError(err)
+	a.Equal(len(streams), 0)
+
+	// 阻塞读取时会受 context 的超时控制。
+	timeoutCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	streams, err = f.New(timeoutCtx).XRead([]string{key}, []string{StreamLastID}, Block(0))
+	a.Assert(err == nil || err == context.DeadlineExceeded)
+	a.Equal(len(streams), 0)
+	a.Assert(time.Since(start) < time.Second)
+
+	deleted, err := r.XDel(key, id2)
+	a.NilError(err)
+	a.Equal(deleted, 1)
+
+	deleted, err = r.XTrim(key, MaxLen(0))
+	a.NilError(err)
+	a.Equal(deleted, 1)
+
+	_, err = r.XRead([]string{key}, nil)
+	a.Equal(err, ErrStreamIDsMismatch)
+
+	var futureID error
+	mvs, err := r.Pipeline(func(p Redis) error {
+		_, futureID = p.XAdd(key, StreamAutoID, fields)
+		p.XRange(key, StreamRangeStart, StreamRangeStop)
+		return nil
+	})
+	a.NilError(err)
+	results, ok := mvs[1].MultiValues()
+	a.Assert(ok)
+	a.Equal(len(results), 1)
+	se, ok := results[0].StreamEntry()
+	a.Assert(ok)
+	id, ok := MakeMultiValue(futureID).BulkString()
+	a.Assert(ok)
+	a.Equal(se, MakeStreamEntry(id.String(), fields...))
+}
+
+func TestStreamGroupMethods(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	f := factory(t)
+	r := f.New(ctx)
+	resetRedis(t, r)
+
+	key := "stream-group-key"
+	group := "group"
+	fields := []KeyAndValue{MakeKeyAndValue("a", "1")}
+
+	a.NilError(r.XGroupCreateMkStream(key, group, "0"))
+	a.Assert(r.XGroupCreate(key, group, "0") != nil)
+
+	id1, err := r.XAdd(key, StreamAutoID, fields)
+	a.NilError(err)
+	id2, err := r.XAdd(key, StreamAutoID, fields)
+	a.NilError(err)
+
+	streams, err := r.XReadGroup(group, "c1", []string{key}, []string{">"}, ReadCount(1))
+	a.NilError(err)
+	a.Equal(len(streams), 1)
+	a.Equal(streams[0].Entries.IDs(), []string{id1})
+
+	streams, err = r.XReadGroup(group, "c2", []string{key}, []string{">"})
+	a.NilError(err)
+	a.Equal(streams[0].Entries.IDs(), []string{id2})
+
+	pending, err := r.XPending(key, group)
+	a.NilError(err)
+	a.Equal(pending, StreamPending{
+		Count:     2,
+		Lower:     id1,
+		Higher:    id2,
+		Consumers: map[string]int{"c1": 1, "c2": 1},
+	})
+
+	pendingEntries, err := r.XPendingExt(key, group, StreamRangeStart, StreamRangeStop, 10, ByConsumer("c2"))
+	a.NilError(err)
+	a.Equal(len(pendingEntries), 1)
+	a.Equal(pendingEntries[0].ID, id2)
+	a.Equal(pendingEntries[0].Consumer, "c2")
+	a.Equal(pendingEntries[0].DeliveryCount, 1)
+
+	pendingEntries, err = r.XPendingExt(key, group, StreamRangeStart, StreamRangeStop, 10, MinIdle(time.Hour))
+	a.NilError(err)
+	a.Equal(len(pendingEntries), 0)
+
+	entries, err := r.XClaim(key, group, "c2", 0, id1)
+	a.NilError(err)
+	a.Equal(entries.IDs(), []string{id1})
+
+	ids, err := r.XClaimJustID(key, group, "c1", 0, id1, id2)
+	a.NilError(err)
+	a.Equal(ids, []string{id1, id2})
+
+	next, entries, err := r.XAutoClaim(key, group, "c3", 0, "0-0", 10)
+	a.NilError(err)
+	a.Equal(next, "0-0")
+	a.Equal(entries.IDs(), []string{id1, id2})
+	a.Equal(entries[0].Fields, KeyAndValues(fields))
+
+	// count 为 0 时使用 Redis 的默认值。
+	next, entries, err = r.XAutoClaim(key, group, "c3", 0, "0-0", 0)
+	a.NilError(err)
+	a.Equal(next, "0-0")
+	a.Equal(entries.IDs(), []string{id1, id2})
+
+	acked, err := r.XAck(key, group, id1)
+	a.NilError(err)
+	a.Equal(acked, 1)
+
+	info, err := r.XInfoStream(key)
+	a.NilError(err)
+	a.Equal(info.Length, 2)
+	a.Equal(info.Groups, 1)
+	a.Equal(info.LastGeneratedID, id2)
+	a.Equal(info.FirstEntry, MakeStreamEntry(id1, fields...))
+	a.Equal(info.LastEntry, MakeStreamEntry(id2, fields...))
+
+	groups, err := r.XInfoGroups(key)
+	a.NilError(err)
+	a.Equal(len(groups), 1)
+	a.Equal(groups[0].Name, group)
+	a.Equal(groups[0].Consumers, 3)
+	a.Equal(groups[0].Pending, 1)
+	a.Equal(groups[0].LastDeliveredID, id2)
+
+	consumers, err := r.XInfoConsumers(key, group)
+	a.NilError(err)
+	a.Equal(len(consumers), 3)
+	a.Equal(consumers[2].Name, "c3")
+	a.Equal(consumers[2].Pending, 1)
+
+	pendingCount, err := r.XGroupDelConsumer(key, group, "c3")
+	a.NilError(err)
+	a.Equal(pendingCount, 1)
+
+	a.NilError(r.XGroupSetID(key, group, "0"))
+	streams, err = r.XReadGroup(group, "c1", []string{key}, []string{">"}, NoAck())
+	a.NilError(err)
+	a.Equal(streams[0].Entries.IDs(), []string{id1, id2})
+
+	pending, err = r.XPending(key, group)
+	a.NilError(err)
+	a.Equal(pending.Count, 0)
+
+	destroyed, err := r.XGroupDestroy(key, group)
+	a.NilError(err)
+	a.Assert(destroyed)
+}
+
+func TestStreamReadGroupCancel(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	f := factory(t)
+	r := f.New(ctx)
+	resetRedis(t, r)
+
+	key := "stream-cancel-key"
+	group := "group"
+	a.NilError(r.XGroupCreateMkStream(key, group, "$"))
+
+	// 被取消的 XREADGROUP 不能在之后读到 entry，否则 entry 会留在 PEL 里，没有人处理。
+	cancelCtx, cancel := context.WithCancel(ctx)
+	time.AfterFunc(100*time.Millisecond, cancel)
+	start := time.Now()
+	_, err := f.New(cancelCtx).XReadGroup(group, "c1", []string{key}, []string{">"}, Block(0))
+	a.Equal(err, context.Canceled)
+	a.Assert(time.Since(start) < time.Second)
+
+	id, err := r.XAdd(key, StreamAutoID, []KeyAndValue{MakeKeyAndValue("a", "1")})
+	a.NilError(err)
+	time.Sleep(100 * time.Millisecond)
+
+	pending, err := r.XPending(key, group)
+	a.NilError(err)
+	a.Equal(pending.Count, 0)
+
+	streams, err := r.XReadGroup(group, "c2", []string{key}, []string{">"})
+	a.NilError(err)
+	a.Equal(len(streams), 1)
+	a.Equal(streams[0].Entries.IDs(), []string{id})
+}
+
+func TestStreamConsumer(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	f := factory(t)
+	r := f.New(ctx)
+	resetRedis(t, r)
+
+	key := "stream-consumer-key"
+	group := "group"
+	handled := make(chan string, 10)
+	failed := false
+	sc := NewStreamConsumer(f, &StreamConsumerConfig{
+		Stream:        key,
+		Group:         group,
+		Consumer:      "consumer",
+		Block:         50 * time.Millisecond,
+		ClaimMinIdle:  time.Millisecond,
+		ClaimInterval: 100 * time.Millisecond,
+	}, func(ctx context.Context, stream string, entry StreamEntry) error {
+		// 第一次处理失败，entry 会在 ClaimMinIdle 之后被重新认领。
+		if !failed {
+			failed = true
+			panic("handler fails")
+		}
+
+		handled <- entry.ID
+		return nil
+	})
+	a.NilError(sc.Start(ctx))
+	a.Equal(sc.Start(ctx), ErrStreamConsumerStarted)
+
+	id, err := r.XAdd(key, StreamAutoID, []KeyAndValue{MakeKeyAndValue("a", "1")})
+	a.NilError(err)
+
+	select {
+	case handledID := <-handled:
+		a.Equal(handledID, id)
+	case <-time.After(5 * time.Second):
+		t.Fatalf("stream entry is not handled")
+	}
+
+	sc.Stop()
+
+	pending, err := r.XPending(key, group)
+	a.NilError(err)
+	a.Equal(pending.Count, 0)
+}
+
+func TestStreamConsumerClaimFails(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	f := factory(t)
+	r := f.New(ctx)
+	resetRedis(t, r)
+
+	// 禁止执行 XAUTOCLAIM，认领会一直失败。
+	a.NilError(f.client.Process(driver.NewCmd("ACL", "SETUSER", "default", "-xautoclaim")))
+	defer f.client.Process(driver.NewCmd("ACL", "SETUSER", "default", "+xautoclaim"))
+
+	key := "stream-consumer-claim-fails"
+	handled := make(chan string, 10)
+	sc := NewStreamConsumer(f, &StreamConsumerConfig{
+		Stream:        key,
+		Group:         "group",
+		Consumer:      "consumer",
+		Block:         50 * time.Millisecond,
+		ClaimInterval: 100 * time.Millisecond,
+	}, func(ctx context.Context, stream string, entry StreamEntry) error {
+		handled <- entry.ID
+		return nil
+	})
+	a.NilError(sc.Start(ctx))
+	defer sc.Stop()
+
+	// 等认领失败之后再添加 entry，认领失败不能影响读取新的 entry。
+	time.Sleep(300 * time.Millisecond)
+	id, err := r.XAdd(key, StreamAutoID, []KeyAndValue{MakeKeyAndValue("a", "1")})
+	a.NilError(err)
+
+	select {
+	case handledID := <-handled:
+		a.Equal(handledID, id)
+	case <-time.After(5 * time.Second):
+		t.Fatalf("stream entry is not handled")
+	}
+}
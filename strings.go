@@ -4,25 +4,22 @@ import (
 	"time"
 
 	"github.com/altstory/go-redis/internal/driver"
-	"github.com/go-redis/redis"
 )
 
 // Strings 代表 Redis 跟字符串相关的接口，详见 https://redis.io/commands#string。
 type Strings interface {
 	Append(key string, value string) (l int, err error)
 
-	// TODO: BitCount(key string) (count int, err error)
-	// TODO: BitCountWithRange(key string, start int, end int) (count int, err error)
-	// TODO: BitField
-	// TODO: BitOp
-	// TODO: BitPos
+	BitCount(key string) (count int, err error)
+	BitCountWithRange(key string, start int, end int) (count int, err error)
+	BitField(key string, ops *BitFieldOps) (values []MultiValue, err error)
+	BitOp(op BitOperation, dst string, keys ...string) (size int, err error)
+	BitPos(key string, bit int, positions ...int) (pos int, err error) // BITPOS key bit [start [end]]
 
 	Decr(key string) (value int64, err error)
 	DecrBy(key string, decr int64) (value int64, err error)
 	Get(key string) (value BulkString, err error)
-
-	// TODO: GetBit
-
+	GetBit(key string, offset int) (bit int, err error)
 	GetRange(key string, start int, end int) (value BulkString, err error)
 	GetSet(key string, value string) (old BulkString, err error)
 	Incr(key string) (value int64, err error)
@@ -32,9 +29,7 @@ type Strings interface {
 	MSet(kvs ...KeyAndValue) (err error)
 	MSetNX(kvs ...KeyAndValue) (isSet bool, err error)
 	Set(key string, value string, options ...SetOption) (isSet bool, err error)
-
-	// TODO: SetBit
-
+	SetBit(key string, offset int, bit int) (old int, err error)
 	SetEx(key string, timeout time.Duration, value string) (err error)
 	SetNX(key string, value string) (isSet bool, err error)
 	SetRange(key string, offset int, value string) (modified int, err error)
@@ -43,7 +38,73 @@ type Strings interface {
 
 func (r *redisImpl) Append(key string, value string) (l int, err error) {
 	err = r.do("APPEND", func(client driver.Client) error {
-		l, err = mustBeInt(client, client.Append(key, value))
+		l, err = mustBeInt(client, process(client, newCmd("APPEND", key, value)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) BitCount(key string) (count int, err error) {
+	err = r.do("BITCOUNT", func(client driver.Client) error {
+		count, err = mustBeInt(client, process(client, newCmd("BITCOUNT", key)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) BitCountWithRange(key string, start int, end int) (count int, err error) {
+	err = r.do("BITCOUNT", func(client driver.Client) error {
+		count, err = mustBeInt(client, process(client, newCmd("BITCOUNT", key, start, end)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) BitField(key string, ops *BitFieldOps) (values []MultiValue, err error) {
+	if ops == nil || len(ops.args) == 0 {
+		return
+	}
+
+	args := make([]interface{}, 0, 2+len(ops.args))
+	args = append(args, "BITFIELD", key)
+	args = append(args, ops.Args()...)
+
+	err = r.do("BITFIELD", func(client driver.Client) error {
+		values, err = mustBeMultiValues(client, process(client, newCmd(args...)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) BitOp(op BitOperation, dst string, keys ...string) (size int, err error) {
+	if len(keys) == 0 {
+		return
+	}
+
+	args := make([]interface{}, 0, 3+len(keys))
+	args = append(args, "BITOP", string(op), dst)
+
+	for _, key := range keys {
+		args = append(args, key)
+	}
+
+	err = r.do("BITOP", func(client driver.Client) error {
+		size, err = mustBeInt(client, process(client, newCmd(args...)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) BitPos(key string, bit int, positions ...int) (pos int, err error) {
+	args := make([]interface{}, 0, 3+len(positions))
+	args = append(args, "BITPOS", key, bit)
+
+	for _, position := range positions {
+		args = append(args, position)
+	}
+
+	err = r.do("BITPOS", func(client driver.Client) error {
+		pos, err = mustBeInt(client, process(client, newCmd(args...)))
 		return err
 	})
 	return
@@ -51,7 +112,7 @@ func (r *redisImpl) Append(key string, value string) (l int, err error) {
 
 func (r *redisImpl) Decr(key string) (value int64, err error) {
 	err = r.do("DECR", func(client driver.Client) error {
-		value, err = mustBeInt64(client, client.Decr(key))
+		value, err = mustBeInt64(client, process(client, newCmd("DECR", key)))
 		return err
 	})
 	return
@@ -59,7 +120,7 @@ func (r *redisImpl) Decr(key string) (value int64, err error) {
 
 func (r *redisImpl) DecrBy(key string, decr int64) (value int64, err error) {
 	err = r.do("DECRBY", func(client driver.Client) error {
-		value, err = mustBeInt64(client, client.DecrBy(key, decr))
+		value, err = mustBeInt64(client, process(client, newCmd("DECRBY", key, decr)))
 		return err
 	})
 	return
@@ -67,7 +128,15 @@ func (r *redisImpl) DecrBy(key string, decr int64) (value int64, err error) {
 
 func (r *redisImpl) Get(key string) (value BulkString, err error) {
 	err = r.do("GET", func(client driver.Client) error {
-		value, err = mustBeBulkString(client, client.Get(key))
+		value, err = mustBeBulkString(client, r.processCached(client, key, cacheID("GET", key), newCmd("GET", key)))
+		return err
+	})
+	return
+}
+
+func (r *redisImpl) GetBit(key string, offset int) (bit int, err error) {
+	err = r.do("GETBIT", func(client driver.Client) error {
+		bit, err = mustBeInt(client, process(client, newCmd("GETBIT", key, offset)))
 		return err
 	})
 	return
@@ -75,7 +144,7 @@ func (r *redisImpl) Get(key string) (value BulkString, err error) {
 
 func (r *redisImpl) GetRange(key string, start int, end int) (value BulkString, err error) {
 	err = r.do("GETRANGE", func(client driver.Client) error {
-		value, err = mustBeBulkString(client, client.GetRange(key, int64(start), int64(end)))
+		value, err = mustBeBulkString(client, process(client, newCmd("GETRANGE", key, start, end)))
 		return err
 	})
 	return
@@ -83,7 +152,7 @@ func (r *redisImpl) GetRange(key string, start int, end int) (value BulkString,
 
 func (r *redisImpl) GetSet(key string, value string) (old BulkString, err error) {
 	err = r.do("GETSET", func(client driver.Client) error {
-		old, err = mustBeBulkString(client, client.GetSet(key, value))
+		old, err = mustBeBulkString(client, process(client, newCmd("GETSET", key, value)))
 		return err
 	})
 	return
@@ -91,7 +160,7 @@ func (r *redisImpl) GetSet(key string, value string) (old BulkString, err error)
 
 func (r *redisImpl) Incr(key string) (value int64, err error) {
 	err = r.do("INCR", func(client driver.Client) error {
-		value, err = mustBeInt64(client, client.Incr(key))
+		value, err = mustBeInt64(client, process(client, newCmd("INCR", key)))
 		return err
 	})
 	return
@@ -99,7 +168,7 @@ func (r *redisImpl) Incr(key string) (value int64, err error) {
 
 func (r *redisImpl) IncrBy(key string, incr int64) (value int64, err error) {
 	err = r.do("INCRBY", func(client driver.Client) error {
-		value, err = mustBeInt64(client, client.IncrBy(key, incr))
+		value, err = mustBeInt64(client, process(client, newCmd("INCRBY", key, incr)))
 		return err
 	})
 	return
@@ -107,7 +176,7 @@ func (r *redisImpl) IncrBy(key string, incr int64) (value int64, err error) {
 
 func (r *redisImpl) IncrByFloat(key string, incr float64) (value float64, err error) {
 	err = r.do("INCRBYFLOAT", func(client driver.Client) error {
-		value, err = mustBeFloat64(client, client.IncrByFloat(key, incr))
+		value, err = mustBeFloat64(client, process(client, newCmdWith(parseFloat, "INCRBYFLOAT", key, incr)))
 		return err
 	})
 	return
@@ -119,7 +188,7 @@ func (r *redisImpl) MGet(keys ...string) (values []BulkString, err error) {
 	}
 
 	err = r.do("MGET", func(client driver.Client) error {
-		values, err = mustBeBulkStrings(client, client.MGet(keys...))
+		values, err = mustBeBulkStrings(client, r.processMGet(client, keys))
 		return err
 	})
 	return
@@ -130,14 +199,15 @@ func (r *redisImpl) MSet(kvs ...KeyAndValue) (err error) {
 		return
 	}
 
-	values := make([]interface{}, 0, 2*len(kvs))
+	values := make([]interface{}, 0, 1+2*len(kvs))
+	values = append(values, "MSET")
 
 	for _, kv := range kvs {
 		values = append(values, kv.Key, kv.Value)
 	}
 
 	err = r.do("MSET", func(client driver.Client) error {
-		_, err = mustBeStatus(client, client.MSet(values...))
+		_, err = mustBeStatus(client, process(client, newCmd(values...)))
 		return err
 	})
 	return
@@ -148,14 +218,15 @@ func (r *redisImpl) MSetNX(kvs ...KeyAndValue) (isSet bool, err error) {
 		return
 	}
 
-	values := make([]interface{}, 0, 2*len(kvs))
+	values := make([]interface{}, 0, 1+2*len(kvs))
+	values = append(values, "MSETNX")
 
 	for _, kv := range kvs {
 		values = append(values, kv.Key, kv.Value)
 	}
 
 	err = r.do("MSETNX", func(client driver.Client) error {
-		isSet, err = mustBeBool(client, client.MSetNX(values...))
+		isSet, err = mustBeBool(client, process(client, newCmdWith(parseBool, values...)))
 		return err
 	})
 	return
@@ -170,26 +241,35 @@ func (r *redisImpl) Set(key string, value string, options ...SetOption) (isSet b
 			args = append(args, opt.Args()...)
 		}
 
-		cmd := redis.NewStatusCmd(args...)
-
-		if err = client.Process(cmd); err != nil {
-			if err != redis.Nil {
-				return err
-			}
-
-			return nil
-		}
+		// 设置了 NX 或 XX 的时候，如果没有设置成功 Redis 会返回 nil。
+		_, isSet, err = mustBeStatusOrNil(client, process(client, newCmd(args...)))
+		return err
+	})
+	return
+}
 
-		isSet = true
-		_, err = mustBeStatus(client, cmd)
+func (r *redisImpl) SetBit(key string, offset int, bit int) (old int, err error) {
+	err = r.do("SETBIT", func(client driver.Client) error {
+		old, err = mustBeInt(client, process(client, newCmd("SETBIT", key, offset, bit)))
 		return err
 	})
 	return
 }
 
 func (r *redisImpl) SetEx(key string, timeout time.Duration, value string) (err error) {
+	args := make([]interface{}, 0, 5)
+	args = append(args, "SET", key, value)
+
+	if timeout > 0 {
+		if timeout%time.Second == 0 {
+			args = append(args, "EX", int64(timeout/time.Second))
+		} else {
+			args = append(args, "PX", int64(timeout/time.Millisecond))
+		}
+	}
+
 	err = r.do("SETEX", func(client driver.Client) error {
-		_, err = mustBeStatus(client, client.Set(key, value, timeout))
+		_, err = mustBeStatus(client, process(client, newCmd(args...)))
 		return err
 	})
 	return
@@ -197,7 +277,7 @@ func (r *redisImpl) SetEx(key string, timeout time.Duration, value string) (err
 
 func (r *redisImpl) SetNX(key string, value string) (isSet bool, err error) {
 	err = r.do("SETNX", func(client driver.Client) error {
-		isSet, err = mustBeBool(client, client.SetNX(key, value, 0))
+		isSet, err = mustBeBool(client, process(client, newCmdWith(parseBool, "SETNX", key, value)))
 		return err
 	})
 	return
@@ -205,7 +285,7 @@ func (r *redisImpl) SetNX(key string, value string) (isSet bool, err error) {
 
 func (r *redisImpl) SetRange(key string, offset int, value string) (modified int, err error) {
 	err = r.do("SETRANGE", func(client driver.Client) error {
-		modified, err = mustBeInt(client, client.SetRange(key, int64(offset), value))
+		modified, err = mustBeInt(client, process(client, newCmd("SETRANGE", key, offset, value)))
 		return err
 	})
 	return
@@ -213,7 +293,7 @@ func (r *redisImpl) SetRange(key string, offset int, value string) (modified int
 
 func (r *redisImpl) StrLen(key string) (l int, err error) {
 	err = r.do("STRLEN", func(client driver.Client) error {
-		l, err = mustBeInt(client, client.StrLen(key))
+		l, err = mustBeInt(client, process(client, newCmd("STRLEN", key)))
 		return err
 	})
 	return
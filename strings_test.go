@@ -0,0 +1,118 @@
+package redis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+func TestBitMethods(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	f := factory(t)
+	r := f.New(ctx)
+	resetRedis(t, r)
+
+	// 模拟两天的日活，每个 bit 代表一个用户。
+	for _, uid := range []int{1, 3, 5, 100} {
+		_, err := r.SetBit("dau-1", uid, 1)
+		a.NilError(err)
+	}
+
+	for _, uid := range []int{3, 7, 100} {
+		_, err := r.SetBit("dau-2", uid, 1)
+		a.NilError(err)
+	}
+
+	old, err := r.SetBit("dau-1", 3, 1)
+	a.NilError(err)
+	a.Equal(old, 1)
+
+	bit, err := r.GetBit("dau-1", 5)
+	a.NilError(err)
+	a.Equal(bit, 1)
+	bit, err = r.GetBit("dau-1", 6)
+	a.NilError(err)
+	a.Equal(bit, 0)
+
+	count, err := r.BitCount("dau-1")
+	a.NilError(err)
+	a.Equal(count, 4)
+	count, err = r.BitCountWithRange("dau-1", 0, 0)
+	a.NilError(err)
+	a.Equal(count, 3)
+
+	size, err := r.BitOp(BitOpAnd, "dau-both", "dau-1", "dau-2")
+	a.NilError(err)
+	a.Equal(size, 13)
+	count, err = r.BitCount("dau-both")
+	a.NilError(err)
+	a.Equal(count, 2)
+
+	_, err = r.BitOp(BitOpOr, "dau-any", "dau-1", "dau-2")
+	a.NilError(err)
+	count, err = r.BitCount("dau-any")
+	a.NilError(err)
+	a.Equal(count, 5)
+
+	pos, err := r.BitPos("dau-1", 1)
+	a.NilError(err)
+	a.Equal(pos, 1)
+	pos, err = r.BitPos("dau-1", 1, 1)
+	a.NilError(err)
+	a.Equal(pos, 100)
+	pos, err = r.BitPos("dau-1", 1, 1, 2)
+	a.NilError(err)
+	a.Equal(pos, -1)
+
+	values, err := r.BitField("bitfield", NewBitFieldOps().
+		Set(U8, 0, 200).
+		Get(U8, 0).
+		IncrBy(U8, 0, 100).
+		Overflow(OverflowSat).
+		IncrBy(U8, 0, 250).
+		Overflow(OverflowFail).
+		IncrBy(U8, 0, 1).
+		Get(SignedBits(4), 8).
+		Set(UnsignedBits(4), 8, 15).
+		Get(I8, 8))
+	a.NilError(err)
+	a.Equal(len(values), 8)
+
+	expected := []int64{0, 200, 44, 255}
+
+	for i, v := range expected {
+		n, ok := values[i].Int64()
+		a.Assert(ok)
+		a.Equal(n, v)
+	}
+
+	a.Assert(values[4].IsNil())
+
+	n, ok := values[5].Int64()
+	a.Assert(ok)
+	a.Equal(n, int64(0))
+	n, ok = values[7].Int64()
+	a.Assert(ok)
+	a.Equal(n, int64(-16))
+
+	values, err = r.BitField("bitfield", nil)
+	a.NilError(err)
+	a.Equal(len(values), 0)
+
+	var futureValues error
+	mvs, err := r.Pipeline(func(p Redis) error {
+		_, futureValues = p.BitField("bitfield", NewBitFieldOps().
+			Overflow(OverflowFail).
+			IncrBy(I8, 0, 1000))
+		return nil
+	})
+	a.NilError(err)
+	a.Equal(len(mvs), 1)
+
+	results, ok := MakeMultiValue(futureValues).MultiValues()
+	a.Assert(ok)
+	a.Equal(len(results), 1)
+	a.Assert(results[0].IsNil())
+}
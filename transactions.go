@@ -1,6 +1,146 @@
 package redis
 
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/altstory/go-redis/internal/driver"
+)
+
+const (
+	// DefaultWatchMaxAttempts 是 Watch 默认最多尝试执行事务的次数。
+	DefaultWatchMaxAttempts = 5
+
+	// DefaultWatchRetryBackoff 是 Watch 默认的重试等待时间。
+	DefaultWatchRetryBackoff = 10 * time.Millisecond
+)
+
+var (
+	// ErrNestedTransaction 表示在 transaction 或 pipeline 的回调里再次调用了 Multi、Pipeline 或 Watch。
+	ErrNestedTransaction = errors.New("go-redis: nested transaction or pipeline is not supported")
+
+	// ErrTxAborted 表示由于 WATCH 的 key 被修改，EXEC 没有执行任何命令。
+	// 如果是 Watch 返回这个错误，说明已经用完了所有的重试次数。
+	ErrTxAborted = errors.New("go-redis: transaction is aborted as watched keys are modified")
+)
+
 // Transactions 代表 Redis 跟事务相关的接口，详见 https://redis.io/commands#transactions。
 type Transactions interface {
-	// TODO:
+	// Multi 使用 MULTI/EXEC 执行 fn 里面调用的所有命令。
+	//
+	// fn 里面的 r 上调用的所有命令都只会被缓存起来，返回的 err 是一个 *FutureMultiValue，
+	// 等 EXEC 之后可以用 MakeMultiValue 拿到真正的结果，也可以直接使用 Multi 返回的 mvs，
+	// mvs 按顺序保存了每一个命令的结果。
+	//
+	// 如果 fn 返回了 error，缓存的命令会被直接丢弃，不会有任何命令（包括 MULTI）发送给 Redis，
+	// 因此也不需要 DISCARD，Multi 返回这个错误。
+	// 由于命令返回的 *FutureMultiValue 也是 error，fn 直接返回这种 error 不会被当做失败。
+	Multi(fn func(r Redis) error) (mvs []MultiValue, err error)
+
+	// Watch 使用 WATCH 监视 keys，实现 check-and-set 语义。
+	//
+	// Watch 会固定使用一个连接执行 fn，在 fn 里使用 tx 读取数据时会立即执行，
+	// 需要修改数据时应该调用 tx.Multi 并将其返回的错误原样返回。
+	// 如果 keys 在 WATCH 之后被修改，tx.Multi 会返回 ErrTxAborted，
+	// 这时 Watch 会在等待一段时间后重新执行 fn，直到成功或者用完所有的尝试次数，
+	// 最终失败时返回 ErrTxAborted。
+	//
+	// 可以通过 MaxAttempts 和 RetryBackoff 选项来控制重试行为。
+	Watch(keys []string, fn func(tx Redis) error, options ...WatchOption) (err error)
+}
+
+func (r *redisImpl) Multi(fn func(r Redis) error) (mvs []MultiValue, err error) {
+	if isPipelined(r.client) {
+		err = ErrNestedTransaction
+		return
+	}
+
+	err = r.do("MULTI", func(client driver.Client) error {
+		pipe := &pipeliner{}
+
+		if e := fn(newRedis(r.ctx, pipe)); e != nil && !isFutureMultiValue(e) {
+			return e
+		}
+
+		e := client.ProcessTxPipeline(pipe.cmds)
+
+		if isTxFailed(e) {
+			if e == driver.ErrTxFailed {
+				return ErrTxAborted
+			}
+
+			return e
+		}
+
+		mvs, err = parsePipelinedReply(pipe.cmds, e)
+		return err
+	})
+	return
+}
+
+// watcher 是支持 WATCH 的 driver.Client，在 transaction 和 pipeline 里的 client 不支持 WATCH。
+type watcher interface {
+	Watch(fn func(tx driver.Client) error, keys ...string) error
+}
+
+func (r *redisImpl) Watch(keys []string, fn func(tx Redis) error, options ...WatchOption) (err error) {
+	w, ok := r.client.(watcher)
+
+	if !ok {
+		err = ErrNestedTransaction
+		return
+	}
+
+	maxAttempts := DefaultWatchMaxAttempts
+	backoff := DefaultWatchRetryBackoff
+
+	for _, opt := range options {
+		switch opt.t {
+		case watchOptionMaxAttempts:
+			maxAttempts = opt.opt.(int)
+		case watchOptionRetryBackoff:
+			backoff = opt.opt.(time.Duration)
+		}
+	}
+
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+
+	err = r.do("WATCH", func(client driver.Client) error {
+		for attempt := 1; ; attempt++ {
+			err := w.Watch(func(tx driver.Client) error {
+				return fn(newRedis(r.ctx, tx))
+			}, keys...)
+
+			if err != ErrTxAborted || attempt >= maxAttempts {
+				return err
+			}
+
+			if backoff <= 0 {
+				continue
+			}
+
+			select {
+			case <-r.ctx.Done():
+				return r.ctx.Err()
+			case <-time.After(backoff):
+			}
+		}
+	})
+	return
+}
+
+// isTxFailed 判断 err 是否代表整个事务没有被执行。
+func isTxFailed(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if err == driver.ErrTxFailed {
+		return true
+	}
+
+	return isRedisError(err) && strings.HasPrefix(err.Error(), "EXECABORT")
 }
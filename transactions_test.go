@@ -0,0 +1,152 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+func TestMulti(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	f := factory(t)
+	r := f.New(ctx)
+	resetRedis(t, r)
+
+	key := "multi-key"
+	var futureValue error
+
+	mvs, err := r.Multi(func(r Redis) error {
+		r.Set(key, "2")
+		r.Incr(key)
+		_, futureValue = r.Get(key)
+		return nil
+	})
+	a.NilError(err)
+	a.Equal(len(mvs), 3)
+
+	status, ok := mvs[0].Status()
+	a.Assert(ok)
+	a.Equal(status, "OK")
+
+	n, ok := mvs[1].Int64()
+	a.Assert(ok)
+	a.Equal(n, int64(3))
+
+	v, ok := MakeMultiValue(futureValue).BulkString()
+	a.Assert(ok)
+	a.Equal(v.String(), "3")
+
+	// 命令执行失败的错误存在对应的 MultiValue 里。
+	mvs, err = r.Multi(func(r Redis) error {
+		r.LPush(key, "foo")
+		_, err := r.Get(key)
+		return err
+	})
+	a.NilError(err)
+	a.Equal(len(mvs), 2)
+	a.Assert(mvs[0].IsErr())
+	a.Assert(!mvs[1].IsErr())
+
+	// 回调出错的时候，所有命令都不会被执行。
+	errCallback := errors.New("callback error")
+	_, err = r.Multi(func(r Redis) error {
+		r.Set(key, "10")
+		return errCallback
+	})
+	a.Equal(err, errCallback)
+
+	v, err = r.Get(key)
+	a.NilError(err)
+	a.Equal(v.String(), "3")
+
+	_, err = r.Multi(func(r Redis) error {
+		r.Set("multi-discarded-1", "v")
+		r.LPush("multi-discarded-2", "v")
+		r.Incr(key)
+		return errCallback
+	})
+	a.Equal(err, errCallback)
+	existing, err := r.Exists("multi-discarded-1", "multi-discarded-2")
+	a.NilError(err)
+	a.Equal(existing, 0)
+	v, err = r.Get(key)
+	a.NilError(err)
+	a.Equal(v.String(), "3")
+
+	_, err = r.Multi(func(r Redis) error {
+		_, err := r.Multi(func(r Redis) error {
+			return nil
+		})
+		return err
+	})
+	a.Equal(err, ErrNestedTransaction)
+}
+
+func TestWatch(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	f := factory(t)
+	r := f.New(ctx)
+	resetRedis(t, r)
+
+	key := "watch-key"
+	_, err := r.Set(key, "1")
+	a.NilError(err)
+
+	attempts := 0
+	err = r.Watch([]string{key}, func(tx Redis) error {
+		attempts++
+		v, err := tx.Get(key)
+
+		if err != nil {
+			return err
+		}
+
+		// 第一次执行时在其他连接上修改 key，让事务失败并重试。
+		if attempts == 1 {
+			if _, err := r.Incr(key); err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.Multi(func(p Redis) error {
+			_, err := p.Set(key, v.String()+"0")
+			return err
+		})
+		return err
+	})
+	a.NilError(err)
+	a.Equal(attempts, 2)
+
+	v, err := r.Get(key)
+	a.NilError(err)
+	a.Equal(v.String(), "20")
+
+	// 用完所有重试次数后返回 ErrTxAborted。
+	attempts = 0
+	err = r.Watch([]string{key}, func(tx Redis) error {
+		attempts++
+
+		if _, err := r.Incr(key); err != nil {
+			return err
+		}
+
+		_, err := tx.Multi(func(p Redis) error {
+			_, err := p.Set(key, "0")
+			return err
+		})
+		return err
+	}, MaxAttempts(3), RetryBackoff(0))
+	a.Equal(err, ErrTxAborted)
+	a.Equal(attempts, 3)
+
+	err = r.Watch([]string{key}, func(tx Redis) error {
+		return tx.Watch([]string{key}, func(Redis) error {
+			return nil
+		})
+	})
+	a.Equal(err, ErrNestedTransaction)
+}
@@ -0,0 +1,132 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/huandu/go-assert"
+)
+
+func TestResolveUniversalConfig(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+
+	config, err := resolveUniversalConfig(ctx, &UniversalConfig{
+		Addrs: []string{testAddr},
+		DB:    1,
+	})
+	a.NilError(err)
+	a.Assert(config.Client != nil)
+	a.Equal(config.Client.Addr, testAddr)
+	a.Equal(config.Client.DB, 1)
+
+	config, err = resolveUniversalConfig(ctx, &UniversalConfig{
+		Addrs:      []string{"127.0.0.1:26379", "127.0.0.1:26380"},
+		MasterName: "mymaster",
+	})
+	a.NilError(err)
+	a.Assert(config.Failover != nil)
+	a.Equal(config.Failover.MasterName, "mymaster")
+	a.Equal(config.Failover.SentinelAddrs, []string{"127.0.0.1:26379", "127.0.0.1:26380"})
+
+	// 所有地址都无法连接时无法判断服务器拓扑。
+	unreachable := &UniversalConfig{
+		Addrs:       []string{"127.0.0.1:1", "127.0.0.1:2"},
+		DialTimeout: 100 * time.Millisecond,
+	}
+	_, err = resolveUniversalConfig(ctx, unreachable)
+	a.Assert(errors.Is(err, ErrUniversalUnreachable))
+	a.Assert(strings.Contains(err.Error(), "127.0.0.1:2"))
+
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = resolveUniversalConfig(cancelCtx, unreachable)
+	a.Equal(err, context.Canceled)
+
+	// NewFactory 不会连接服务器，直到调用 Conn 时才探测服务器拓扑。
+	f := NewFactory(&Config{
+		Universal: unreachable,
+	})
+	a.Assert(f.client == nil)
+	a.Assert(errors.Is(f.Conn(ctx), ErrUniversalUnreachable))
+	a.Assert(f.client == nil)
+	a.NilError(f.Close())
+}
+
+func TestUniversalProbe(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	f := factory(t)
+	r := f.New(ctx)
+	resetRedis(t, r)
+
+	config, err := resolveUniversalConfig(ctx, &UniversalConfig{
+		Addrs: []string{"127.0.0.1:1", testAddr},
+		DB:    2,
+
+		DialTimeout: 100 * time.Millisecond,
+	})
+	a.NilError(err)
+	a.Assert(config.Client != nil)
+	a.Equal(config.Client.Addr, testAddr)
+	a.Equal(config.Client.DB, 2)
+
+	f = NewFactory(&Config{
+		Universal: &UniversalConfig{
+			Addrs: []string{testAddr, testAddr},
+		},
+	})
+	defer f.Close()
+	a.NilError(f.Conn(ctx))
+	_, err = f.New(ctx).Set("universal", "v")
+	a.NilError(err)
+	value, err := r.Get("universal")
+	a.NilError(err)
+	a.Equal(value.String(), "v")
+
+	clusterFactory(t, &Config{
+		Client: &ClientConfig{
+			Addr: testClusterAddr,
+		},
+	}).Close()
+	config, err = resolveUniversalConfig(ctx, &UniversalConfig{
+		Addrs: []string{testClusterAddr, testAddr},
+	})
+	a.NilError(err)
+	a.Assert(config.Cluster != nil)
+	a.Equal(config.Cluster.Addrs, []string{testClusterAddr, testAddr})
+}
+
+func TestUniversalProbeAuthFailed(t *testing.T) {
+	a := assert.New(t)
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	a.NilError(err)
+	defer l.Close()
+
+	// 服务器拒绝所有认证请求。
+	go func() {
+		for {
+			cn, err := l.Accept()
+
+			if err != nil {
+				return
+			}
+
+			cn.Write([]byte("-WRONGPASS invalid username-password pair or user is disabled.\r\n"))
+			cn.Close()
+		}
+	}()
+
+	// 认证失败之后不会继续探测后面的地址。
+	_, err = resolveUniversalConfig(context.Background(), &UniversalConfig{
+		Addrs:    []string{l.Addr().String(), testAddr},
+		Password: "wrong",
+	})
+	a.Assert(err != nil)
+	a.Assert(!errors.Is(err, ErrUniversalUnreachable))
+	a.Assert(strings.HasPrefix(err.Error(), "WRONGPASS"))
+}
@@ -3,10 +3,11 @@ package redis
 import (
 	"errors"
 	"fmt"
+	"math/big"
 	"reflect"
 	"time"
 
-	"github.com/go-redis/redis"
+	"github.com/altstory/go-redis/internal/driver"
 )
 
 var (
@@ -54,6 +55,8 @@ func MakeMultiValue(v interface{}) (mv MultiValue) {
 		}
 	case string:
 		mv.data = data
+	case driver.Status:
+		mv.data = string(data)
 	case BulkString:
 		mv.data = data
 	case time.Duration:
@@ -68,23 +71,12 @@ func MakeMultiValue(v interface{}) (mv MultiValue) {
 		mv.data = data
 	case float64:
 		mv.data = data
-	case redis.Z:
-		mv.data = MemberAndScore{
-			Member: fmt.Sprint(data.Member),
-			Score:  data.Score,
-		}
-	case redis.ClusterSlot:
-		// FIXME: 支持 CLUSTER 相关命令的返回值。
-		panic(ErrNotImplemented)
-	case *redis.CommandInfo:
-		// FIXME: 支持 COMMAND 相关命令的返回值。
-		panic(ErrNotImplemented)
-	case redis.GeoLocation:
-		// FIXME: 支持 GEO 相关命令的返回值。
-		panic(ErrNotImplemented)
-	case *redis.GeoPos:
-		// FIXME: 支持 GEO 相关命令的返回值。
-		panic(ErrNotImplemented)
+	case *big.Int:
+		if data != nil {
+			mv.data = data
+		}
+	case ClusterSlot:
+		mv.data = data
 
 	case MultiValue:
 		mv = data
@@ -94,10 +86,28 @@ func MakeMultiValue(v interface{}) (mv MultiValue) {
 		mv.data = data
 	case ChanAndSub:
 		mv.data = data
-
-		// FIXME:
-	// case *scanValues:
-	// 	mv.data = data
+	case StreamEntry:
+		mv.data = data
+	case StreamAndEntries:
+		mv.data = data
+	case GeoLocation:
+		mv.data = data
+	case GeoPos:
+		mv.data = data
+	case *GeoPos:
+		if data != nil {
+			mv.data = *data
+		}
+	case SlowLogEntry:
+		mv.data = data
+	case LatencyEvent:
+		mv.data = data
+	case LatencySample:
+		mv.data = data
+	case StreamPending:
+		mv.data = data
+	case StreamPendingEntry:
+		mv.data = data
 
 	case []interface{}:
 		mvs := make([]MultiValue, 0, len(data))
@@ -107,6 +117,9 @@ func MakeMultiValue(v interface{}) (mv MultiValue) {
 		}
 
 		mv.data = mvs
+	case driver.Map:
+		// RESP3 的 map 展开成 [key1, value1, key2, value2, ...]，与 RESP2 的应答保持一致。
+		mv = MakeMultiValue([]interface{}(data))
 	case []error:
 		mvs := make([]MultiValue, 0, len(data))
 
@@ -158,7 +171,7 @@ func MakeMultiValue(v interface{}) (mv MultiValue) {
 		}
 
 		mv.data = mvs
-	case []redis.Z:
+	case []ClusterSlot:
 		mvs := make([]MultiValue, 0, len(data))
 
 		for _, v := range data {
@@ -166,7 +179,8 @@ func MakeMultiValue(v interface{}) (mv MultiValue) {
 		}
 
 		mv.data = mvs
-	case []redis.ClusterSlot:
+
+	case []BulkString:
 		mvs := make([]MultiValue, 0, len(data))
 
 		for _, v := range data {
@@ -174,7 +188,15 @@ func MakeMultiValue(v interface{}) (mv MultiValue) {
 		}
 
 		mv.data = mvs
-	case map[string]*redis.CommandInfo:
+	case []MultiValue:
+		mvs := make([]MultiValue, 0, len(data))
+
+		for _, v := range data {
+			mvs = append(mvs, v)
+		}
+
+		mv.data = mvs
+	case KeyAndValues:
 		mvs := make([]MultiValue, 0, len(data))
 
 		for _, v := range data {
@@ -182,7 +204,7 @@ func MakeMultiValue(v interface{}) (mv MultiValue) {
 		}
 
 		mv.data = mvs
-	case []redis.GeoLocation:
+	case MemberAndScores:
 		mvs := make([]MultiValue, 0, len(data))
 
 		for _, v := range data {
@@ -190,7 +212,7 @@ func MakeMultiValue(v interface{}) (mv MultiValue) {
 		}
 
 		mv.data = mvs
-	case []*redis.GeoPos:
+	case ChanAndSubs:
 		mvs := make([]MultiValue, 0, len(data))
 
 		for _, v := range data {
@@ -198,8 +220,15 @@ func MakeMultiValue(v interface{}) (mv MultiValue) {
 		}
 
 		mv.data = mvs
+	case GeoLocations:
+		mvs := make([]MultiValue, 0, len(data))
 
-	case []BulkString:
+		for _, v := range data {
+			mvs = append(mvs, MakeMultiValue(v))
+		}
+
+		mv.data = mvs
+	case []*GeoPos:
 		mvs := make([]MultiValue, 0, len(data))
 
 		for _, v := range data {
@@ -207,15 +236,15 @@ func MakeMultiValue(v interface{}) (mv MultiValue) {
 		}
 
 		mv.data = mvs
-	case []MultiValue:
+	case []SlowLogEntry:
 		mvs := make([]MultiValue, 0, len(data))
 
 		for _, v := range data {
-			mvs = append(mvs, v)
+			mvs = append(mvs, MakeMultiValue(v))
 		}
 
 		mv.data = mvs
-	case KeyAndValues:
+	case []LatencyEvent:
 		mvs := make([]MultiValue, 0, len(data))
 
 		for _, v := range data {
@@ -223,7 +252,7 @@ func MakeMultiValue(v interface{}) (mv MultiValue) {
 		}
 
 		mv.data = mvs
-	case MemberAndScores:
+	case []LatencySample:
 		mvs := make([]MultiValue, 0, len(data))
 
 		for _, v := range data {
@@ -231,7 +260,7 @@ func MakeMultiValue(v interface{}) (mv MultiValue) {
 		}
 
 		mv.data = mvs
-	case ChanAndSubs:
+	case StreamEntries:
 		mvs := make([]MultiValue, 0, len(data))
 
 		for _, v := range data {
@@ -239,16 +268,22 @@ func MakeMultiValue(v interface{}) (mv MultiValue) {
 		}
 
 		mv.data = mvs
+	case []StreamAndEntries:
+		mvs := make([]MultiValue, 0, len(data))
+
+		for _, v := range data {
+			mvs = append(mvs, MakeMultiValue(v))
+		}
 
-		// FIXME:
-	// case []scanValues:
-	// 	mvs := make([]MultiValue, 0, len(data))
+		mv.data = mvs
+	case []StreamPendingEntry:
+		mvs := make([]MultiValue, 0, len(data))
 
-	// 	for _, v := range data {
-	// 		mvs = append(mvs, MakeMultiValue(v))
-	// 	}
+		for _, v := range data {
+			mvs = append(mvs, MakeMultiValue(v))
+		}
 
-	// 	mv.data = mvs
+		mv.data = mvs
 
 	default:
 		val := reflect.ValueOf(v)
@@ -331,6 +366,12 @@ func (mv MultiValue) Float64() (n float64, ok bool) {
 	return
 }
 
+// BigInt 返回 RESP3 的 big number，如果 MultiValue 存储的类型不是 *big.Int，ok 为 false。
+func (mv MultiValue) BigInt() (n *big.Int, ok bool) {
+	n, ok = mv.data.(*big.Int)
+	return
+}
+
 // Bool 返回 bool 值，如果 MultiValue 存储的类型不是 bool 或 int/int64，ok 为 false。如果类型是 int/int64，非 0 代表 true。
 func (mv MultiValue) Bool() (v bool, ok bool) {
 	v, ok = mv.data.(bool)
@@ -364,10 +405,9 @@ func (mv MultiValue) BulkString() (v BulkString, ok bool) {
 
 	v, ok = mv.data.(BulkString)
 
-	// 由于 github.com/go-redis/redis 在底层不一定能精确区分 status 和 string，
-	// 例如 EVAL 返回里面被统一强制转成 string 了，因此这里只好做一个兼容。
-	// 所幸一般来说我们不会被 status 和 string 困扰，这两种类型基本不可能混用，
-	// 所以这个兼容不会影响到业务代码。
+	// 使用者一般不会关心一个字符串应答到底是 status 还是 bulk string，
+	// 例如 EVAL 的返回值里可能同时包含这两种类型，因此这里将 status 也当做 bulk string 返回。
+	// 所幸一般来说这两种类型基本不可能混用，所以这个兼容不会影响到业务代码。
 	if !ok {
 		s, okok := mv.data.(string)
 
@@ -415,26 +455,119 @@ func (mv MultiValue) MemberAndScore() (ms MemberAndScore, ok bool) {
 	return
 }
 
-func isPipelined(cmdable redis.Cmdable) (ok bool) {
-	_, ok = cmdable.(redis.Pipeliner)
+// ChanAndSub 返回一个 ChanAndSub，如果 MultiValue 存储的类型不是 ChanAndSub，ok 为 false。
+func (mv MultiValue) ChanAndSub() (cs ChanAndSub, ok bool) {
+	cs, ok = mv.data.(ChanAndSub)
 	return
 }
 
-func parsePipelinedReply(cmders []redis.Cmder, err error) ([]MultiValue, error) {
-	if err != nil && err != redis.Nil {
-		return nil, err
+// ClusterSlot 返回一个 ClusterSlot，如果 MultiValue 存储的类型不是 ClusterSlot，ok 为 false。
+func (mv MultiValue) ClusterSlot() (cs ClusterSlot, ok bool) {
+	cs, ok = mv.data.(ClusterSlot)
+	return
+}
+
+// SlowLogEntry 返回一个 SlowLogEntry，如果 MultiValue 存储的类型不是 SlowLogEntry，ok 为 false。
+// 如果 MultiValue 存储的是 SLOWLOG GET 原始的应答格式（比如在 pipeline 里面执行的 SlowLogGet），也会被解析成 SlowLogEntry。
+func (mv MultiValue) SlowLogEntry() (entry SlowLogEntry, ok bool) {
+	if entry, ok = mv.data.(SlowLogEntry); ok {
+		return
+	}
+
+	if mvs, isArray := mv.MultiValues(); isArray {
+		entry, ok = parseSlowLogEntry(mvs)
 	}
 
-	// 如果错误当前 err 是 redis.Nil，那么需要看看是否后面有其他错误存在，这才能确保所有都没有错。
-	if err == redis.Nil {
-		err = nil
+	return
+}
 
-		for _, cmder := range cmders {
-			if e := cmder.Err(); e != nil && e != redis.Nil {
-				err = e
-				break
-			}
-		}
+// LatencyEvent 返回一个 LatencyEvent，如果 MultiValue 存储的类型不是 LatencyEvent，ok 为 false。
+// 如果 MultiValue 存储的是 LATENCY LATEST 原始的应答格式，也会被解析成 LatencyEvent。
+func (mv MultiValue) LatencyEvent() (le LatencyEvent, ok bool) {
+	if le, ok = mv.data.(LatencyEvent); ok {
+		return
+	}
+
+	if mvs, isArray := mv.MultiValues(); isArray {
+		le, ok = parseLatencyEvent(mvs)
+	}
+
+	return
+}
+
+// LatencySample 返回一个 LatencySample，如果 MultiValue 存储的类型不是 LatencySample，ok 为 false。
+// 如果 MultiValue 存储的是 LATENCY HISTORY 原始的应答格式，也会被解析成 LatencySample。
+func (mv MultiValue) LatencySample() (ls LatencySample, ok bool) {
+	if ls, ok = mv.data.(LatencySample); ok {
+		return
+	}
+
+	if mvs, isArray := mv.MultiValues(); isArray {
+		ls, ok = parseLatencySample(mvs)
+	}
+
+	return
+}
+
+// GeoLocation 返回一个 GeoLocation，如果 MultiValue 存储的类型不是 GeoLocation，ok 为 false。
+func (mv MultiValue) GeoLocation() (gl GeoLocation, ok bool) {
+	gl, ok = mv.data.(GeoLocation)
+	return
+}
+
+// GeoPos 返回一个 GeoPos，如果 MultiValue 存储的类型不是 GeoPos，ok 为 false。
+func (mv MultiValue) GeoPos() (pos GeoPos, ok bool) {
+	pos, ok = mv.data.(GeoPos)
+	return
+}
+
+// StreamEntry 返回一个 StreamEntry，如果 MultiValue 存储的类型不是 StreamEntry，ok 为 false。
+func (mv MultiValue) StreamEntry() (se StreamEntry, ok bool) {
+	se, ok = mv.data.(StreamEntry)
+	return
+}
+
+// StreamAndEntries 返回一个 StreamAndEntries，如果 MultiValue 存储的类型不是 StreamAndEntries，ok 为 false。
+func (mv MultiValue) StreamAndEntries() (se StreamAndEntries, ok bool) {
+	se, ok = mv.data.(StreamAndEntries)
+	return
+}
+
+// StreamPending 返回一个 StreamPending，如果 MultiValue 存储的类型不是 StreamPending，ok 为 false。
+func (mv MultiValue) StreamPending() (sp StreamPending, ok bool) {
+	sp, ok = mv.data.(StreamPending)
+	return
+}
+
+// StreamPendingEntry 返回一个 StreamPendingEntry，如果 MultiValue 存储的类型不是 StreamPendingEntry，ok 为 false。
+func (mv MultiValue) StreamPendingEntry() (spe StreamPendingEntry, ok bool) {
+	spe, ok = mv.data.(StreamPendingEntry)
+	return
+}
+
+func isPipelined(client driver.Client) (ok bool) {
+	_, ok = client.(*pipeliner)
+	return
+}
+
+// isRedisError 判断 err 是否是 Redis 服务端返回的错误，而不是网络之类的错误。
+func isRedisError(err error) bool {
+	_, ok := err.(driver.Error)
+	return ok
+}
+
+// isFutureMultiValue 判断 err 是否只是一个 pipeline 或 transaction 中尚未执行的命令结果。
+func isFutureMultiValue(err error) (ok bool) {
+	_, ok = err.(*FutureMultiValue)
+	return
+}
+
+// parsePipelinedReply 将 pipeline 或 transaction 的所有结果解析成 MultiValue。
+// Redis 服务端返回的错误只属于某一个命令，这种错误会被存在对应的 MultiValue 里，
+// 只有网络错误之类导致整个请求失败的错误才会通过 err 返回。
+func parsePipelinedReply(cmders []driver.Cmder, err error) ([]MultiValue, error) {
+	if err != nil && !isRedisError(err) {
+		return nil, err
 	}
 
 	mvs := make([]MultiValue, 0, len(cmders))
@@ -442,8 +575,7 @@ func parsePipelinedReply(cmders []redis.Cmder, err error) ([]MultiValue, error)
 	for _, cmder := range cmders {
 		mv, e := parseCmder(cmder)
 
-		if e != nil && err != nil {
-			err = e
+		if e != nil {
 			mv = MakeMultiValue(e)
 		}
 
@@ -455,77 +587,35 @@ func parsePipelinedReply(cmders []redis.Cmder, err error) ([]MultiValue, error)
 
 // parseCmder 将任意的 cmder 解析成可以直接被使用的 MultiValue.
 // 需要注意的是，如果 cmder.Err() != nil，这里面的 error 会被存在 MultiValue 中，err 依然还是 nil。
-// 只有当 cmder 无法被解析（例如当前还不认识的 cmder）才会返回 err。
-func parseCmder(cmder redis.Cmder) (mv MultiValue, err error) {
-	err = cmder.Err()
-
-	if err == redis.Nil {
-		err = nil
-
-		if _, ok := cmder.(*redis.StringCmd); ok {
-			mv = MakeMultiValue(Null())
-		}
-
-		return
-	}
-
-	if err != nil {
-		mv = MakeMultiValue(err)
-		err = nil
-		return
-	}
-
-	switch c := cmder.(type) {
-	case *redis.Cmd:
-		mv = MakeMultiValue(c.Val())
-	case *redis.SliceCmd:
-		mv = MakeMultiValue(c.Val())
-	case *redis.StatusCmd:
-		s := c.Val()
-		mv = MakeMultiValue(s)
-	case *redis.IntCmd:
-		mv = MakeMultiValue(c.Val())
-	case *redis.DurationCmd:
-		mv = MakeMultiValue(c.Val())
-	case *redis.TimeCmd:
-		mv = MakeMultiValue(c.Val())
-	case *redis.BoolCmd:
-		mv = MakeMultiValue(c.Val())
-	case *redis.StringCmd:
-		mv = MakeMultiValue(MakeBulkString(c.Val()))
-	case *redis.FloatCmd:
-		mv = MakeMultiValue(c.Val())
-	case *redis.StringSliceCmd:
-		mv = MakeMultiValue(c.Val())
-	case *redis.BoolSliceCmd:
-		mv = MakeMultiValue(c.Val())
-	case *redis.StringStringMapCmd:
-		mv = MakeMultiValue(c.Val())
-	case *redis.StringIntMapCmd:
-		mv = MakeMultiValue(c.Val())
-	case *redis.StringStructMapCmd:
-		mv = MakeMultiValue(c.Val())
-	case *redis.ZSliceCmd:
-		mv = MakeMultiValue(c.Val())
-
-		// FIXME:
-	// case *redis.ScanCmd:
-	// 	keys, cursor := c.Val()
-	// 	mv = MakeMultiValue(&scanValues{
-	// 		cursor: int64(cursor),
-	// 		values: makeValues(keys...),
-	// 	})
-	case *redis.ClusterSlotsCmd, *redis.GeoLocationCmd, *redis.GeoPosCmd, *redis.CommandsInfoCmd:
-		err = ErrNotImplemented
-	default:
-		err = ErrNotImplemented
+// 只有当应答无法被解析（例如应答格式与命令不符）才会返回 err。
+func parseCmder(cmder driver.Cmder) (mv MultiValue, err error) {
+	if e := cmder.Err(); e != nil {
+		mv = MakeMultiValue(e)
+		return
+	}
+
+	mv = MakeMultiValue(cmder.Reply())
+
+	if mv.IsNil() {
+		return
+	}
+
+	if c, ok := cmder.(*command); ok && c.parse != nil {
+		v, ok := c.parse(mv)
+
+		if !ok {
+			err = ErrUnexpectedResponseType
+			return
+		}
+
+		mv = MakeMultiValue(v)
 	}
 
 	return
 }
 
-func mustBeMultiValue(cmdable redis.Cmdable, cmder redis.Cmder) (mv MultiValue, err error) {
-	if isPipelined(cmdable) {
+func mustBeMultiValue(client driver.Client, cmder driver.Cmder) (mv MultiValue, err error) {
+	if isPipelined(client) {
 		err = &FutureMultiValue{cmder}
 		return
 	}
@@ -544,8 +634,8 @@ func mustBeMultiValue(cmdable redis.Cmdable, cmder redis.Cmder) (mv MultiValue,
 	return
 }
 
-func mustBeBool(cmdable redis.Cmdable, cmder redis.Cmder) (v bool, err error) {
-	if isPipelined(cmdable) {
+func mustBeBool(client driver.Client, cmder driver.Cmder) (v bool, err error) {
+	if isPipelined(client) {
 		err = &FutureMultiValue{cmder}
 		return
 	}
@@ -569,8 +659,8 @@ func mustBeBool(cmdable redis.Cmdable, cmder redis.Cmder) (v bool, err error) {
 	return
 }
 
-func mustBeInt(cmdable redis.Cmdable, cmder redis.Cmder) (n int, err error) {
-	if isPipelined(cmdable) {
+func mustBeInt(client driver.Client, cmder driver.Cmder) (n int, err error) {
+	if isPipelined(client) {
 		err = &FutureMultiValue{cmder}
 		return
 	}
@@ -595,8 +685,8 @@ func mustBeInt(cmdable redis.Cmdable, cmder redis.Cmder) (n int, err error) {
 	return
 }
 
-func mustBeIntOrNil(cmdable redis.Cmdable, cmder redis.Cmder) (n int, exists bool, err error) {
-	if isPipelined(cmdable) {
+func mustBeIntOrNil(client driver.Client, cmder driver.Cmder) (n int, exists bool, err error) {
+	if isPipelined(client) {
 		err = &FutureMultiValue{cmder}
 		return
 	}
@@ -626,8 +716,8 @@ func mustBeIntOrNil(cmdable redis.Cmdable, cmder redis.Cmder) (n int, exists boo
 	return
 }
 
-func mustBeInt64(cmdable redis.Cmdable, cmder redis.Cmder) (n int64, err error) {
-	if isPipelined(cmdable) {
+func mustBeInt64OrNil(client driver.Client, cmder driver.Cmder) (n int64, exists bool, err error) {
+	if isPipelined(client) {
 		err = &FutureMultiValue{cmder}
 		return
 	}
@@ -642,6 +732,10 @@ func mustBeInt64(cmdable redis.Cmdable, cmder redis.Cmder) (n int64, err error)
 		return
 	}
 
+	if mv.IsNil() {
+		return
+	}
+
 	v, ok := mv.Int64()
 
 	if !ok {
@@ -649,11 +743,12 @@ func mustBeInt64(cmdable redis.Cmdable, cmder redis.Cmder) (n int64, err error)
 	}
 
 	n = v
+	exists = true
 	return
 }
 
-func mustBeFloat64(cmdable redis.Cmdable, cmder redis.Cmder) (n float64, err error) {
-	if isPipelined(cmdable) {
+func mustBeInt64(client driver.Client, cmder driver.Cmder) (n int64, err error) {
+	if isPipelined(client) {
 		err = &FutureMultiValue{cmder}
 		return
 	}
@@ -668,6 +763,62 @@ func mustBeFloat64(cmdable redis.Cmdable, cmder redis.Cmder) (n float64, err err
 		return
 	}
 
+	v, ok := mv.Int64()
+
+	if !ok {
+		panic(ErrUnexpectedResponseType)
+	}
+
+	n = v
+	return
+}
+
+func mustBeFloat64(client driver.Client, cmder driver.Cmder) (n float64, err error) {
+	if isPipelined(client) {
+		err = &FutureMultiValue{cmder}
+		return
+	}
+
+	mv, e := parseCmder(cmder)
+
+	if e != nil {
+		panic(e)
+	}
+
+	if err = mv.Err(); err != nil {
+		return
+	}
+
+	v, ok := mv.Float64()
+
+	if !ok {
+		panic(ErrUnexpectedResponseType)
+	}
+
+	n = v
+	return
+}
+
+func mustBeFloat64OrNil(client driver.Client, cmder driver.Cmder) (n float64, exists bool, err error) {
+	if isPipelined(client) {
+		err = &FutureMultiValue{cmder}
+		return
+	}
+
+	mv, e := parseCmder(cmder)
+
+	if e != nil {
+		panic(e)
+	}
+
+	if err = mv.Err(); err != nil {
+		return
+	}
+
+	if mv.IsNil() {
+		return
+	}
+
 	v, ok := mv.Float64()
 
 	if !ok {
@@ -675,11 +826,12 @@ func mustBeFloat64(cmdable redis.Cmdable, cmder redis.Cmder) (n float64, err err
 	}
 
 	n = v
+	exists = true
 	return
 }
 
-func mustBeStatus(cmdable redis.Cmdable, cmder redis.Cmder) (s string, err error) {
-	if isPipelined(cmdable) {
+func mustBeStatusOrNil(client driver.Client, cmder driver.Cmder) (s string, exists bool, err error) {
+	if isPipelined(client) {
 		err = &FutureMultiValue{cmder}
 		return
 	}
@@ -694,6 +846,10 @@ func mustBeStatus(cmdable redis.Cmdable, cmder redis.Cmder) (s string, err error
 		return
 	}
 
+	if mv.IsNil() {
+		return
+	}
+
 	v, ok := mv.Status()
 
 	if !ok {
@@ -701,11 +857,38 @@ func mustBeStatus(cmdable redis.Cmdable, cmder redis.Cmder) (s string, err error
 	}
 
 	s = v
+	exists = true
 	return
 }
 
-func mustBeBulkString(cmdable redis.Cmdable, cmder redis.Cmder) (s BulkString, err error) {
-	if isPipelined(cmdable) {
+func mustBeStatus(client driver.Client, cmder driver.Cmder) (s string, err error) {
+	if isPipelined(client) {
+		err = &FutureMultiValue{cmder}
+		return
+	}
+
+	mv, e := parseCmder(cmder)
+
+	if e != nil {
+		panic(e)
+	}
+
+	if err = mv.Err(); err != nil {
+		return
+	}
+
+	v, ok := mv.Status()
+
+	if !ok {
+		panic(ErrUnexpectedResponseType)
+	}
+
+	s = v
+	return
+}
+
+func mustBeBulkString(client driver.Client, cmder driver.Cmder) (s BulkString, err error) {
+	if isPipelined(client) {
 		err = &FutureMultiValue{cmder}
 		return
 	}
@@ -730,8 +913,39 @@ func mustBeBulkString(cmdable redis.Cmdable, cmder redis.Cmder) (s BulkString, e
 	return
 }
 
-func mustBeMultiValues(cmdable redis.Cmdable, cmder redis.Cmder) (mvs []MultiValue, err error) {
-	if isPipelined(cmdable) {
+func mustBeBulkStringOrNil(client driver.Client, cmder driver.Cmder) (s BulkString, exists bool, err error) {
+	if isPipelined(client) {
+		err = &FutureMultiValue{cmder}
+		return
+	}
+
+	mv, e := parseCmder(cmder)
+
+	if e != nil {
+		panic(e)
+	}
+
+	if err = mv.Err(); err != nil {
+		return
+	}
+
+	if mv.IsNil() {
+		return
+	}
+
+	v, ok := mv.BulkString()
+
+	if !ok {
+		panic(ErrUnexpectedResponseType)
+	}
+
+	s = v
+	exists = true
+	return
+}
+
+func mustBeMultiValues(client driver.Client, cmder driver.Cmder) (mvs []MultiValue, err error) {
+	if isPipelined(client) {
 		err = &FutureMultiValue{cmder}
 		return
 	}
@@ -756,8 +970,8 @@ func mustBeMultiValues(cmdable redis.Cmdable, cmder redis.Cmder) (mvs []MultiVal
 	return
 }
 
-func mustBeTime(cmdable redis.Cmdable, cmder redis.Cmder) (t time.Time, err error) {
-	if isPipelined(cmdable) {
+func mustBeTime(client driver.Client, cmder driver.Cmder) (t time.Time, err error) {
+	if isPipelined(client) {
 		err = &FutureMultiValue{cmder}
 		return
 	}
@@ -782,8 +996,8 @@ func mustBeTime(cmdable redis.Cmdable, cmder redis.Cmder) (t time.Time, err erro
 	return
 }
 
-func mustBeDuration(cmdable redis.Cmdable, cmder redis.Cmder) (d time.Duration, err error) {
-	if isPipelined(cmdable) {
+func mustBeDuration(client driver.Client, cmder driver.Cmder) (d time.Duration, err error) {
+	if isPipelined(client) {
 		err = &FutureMultiValue{cmder}
 		return
 	}
@@ -808,8 +1022,8 @@ func mustBeDuration(cmdable redis.Cmdable, cmder redis.Cmder) (d time.Duration,
 	return
 }
 
-func mustBeKeyAndValue(cmdable redis.Cmdable, cmder redis.Cmder) (kv KeyAndValue, err error) {
-	if isPipelined(cmdable) {
+func mustBeKeyAndValue(client driver.Client, cmder driver.Cmder) (kv KeyAndValue, err error) {
+	if isPipelined(client) {
 		err = &FutureMultiValue{cmder}
 		return
 	}
@@ -834,8 +1048,8 @@ func mustBeKeyAndValue(cmdable redis.Cmdable, cmder redis.Cmder) (kv KeyAndValue
 	return
 }
 
-func mustBeMemberAndScore(cmdable redis.Cmdable, cmder redis.Cmder) (ms MemberAndScore, err error) {
-	if isPipelined(cmdable) {
+func mustBeMemberAndScore(client driver.Client, cmder driver.Cmder) (ms MemberAndScore, err error) {
+	if isPipelined(client) {
 		err = &FutureMultiValue{cmder}
 		return
 	}
@@ -860,8 +1074,8 @@ func mustBeMemberAndScore(cmdable redis.Cmdable, cmder redis.Cmder) (ms MemberAn
 	return
 }
 
-func mustBeBulkStrings(cmdable redis.Cmdable, cmder redis.Cmder) (bss []BulkString, err error) {
-	mvs, e := mustBeMultiValues(cmdable, cmder)
+func mustBeBulkStrings(client driver.Client, cmder driver.Cmder) (bss []BulkString, err error) {
+	mvs, e := mustBeMultiValues(client, cmder)
 
 	if e != nil {
 		err = e
@@ -883,8 +1097,31 @@ func mustBeBulkStrings(cmdable redis.Cmdable, cmder redis.Cmder) (bss []BulkStri
 	return
 }
 
-func mustBeKeyAndValues(cmdable redis.Cmdable, cmder redis.Cmder) (kvs []KeyAndValue, err error) {
-	mvs, e := mustBeMultiValues(cmdable, cmder)
+func mustBeBools(client driver.Client, cmder driver.Cmder) (bs []bool, err error) {
+	mvs, e := mustBeMultiValues(client, cmder)
+
+	if e != nil {
+		err = e
+		return
+	}
+
+	bs = make([]bool, 0, len(mvs))
+
+	for _, mv := range mvs {
+		v, ok := mv.Bool()
+
+		if !ok {
+			panic(ErrUnexpectedResponseType)
+		}
+
+		bs = append(bs, v)
+	}
+
+	return
+}
+
+func mustBeKeyAndValues(client driver.Client, cmder driver.Cmder) (kvs []KeyAndValue, err error) {
+	mvs, e := mustBeMultiValues(client, cmder)
 
 	if e != nil {
 		err = e
@@ -906,8 +1143,8 @@ func mustBeKeyAndValues(cmdable redis.Cmdable, cmder redis.Cmder) (kvs []KeyAndV
 	return
 }
 
-func mustBeMemberAndScores(cmdable redis.Cmdable, cmder redis.Cmder) (mss MemberAndScores, err error) {
-	mvs, e := mustBeMultiValues(cmdable, cmder)
+func mustBeMemberAndScores(client driver.Client, cmder driver.Cmder) (mss MemberAndScores, err error) {
+	mvs, e := mustBeMultiValues(client, cmder)
 
 	if e != nil {
 		err = e
@@ -929,6 +1166,338 @@ func mustBeMemberAndScores(cmdable redis.Cmdable, cmder redis.Cmder) (mss Member
 	return
 }
 
+func mustBeChanAndSubs(client driver.Client, cmder driver.Cmder) (css ChanAndSubs, err error) {
+	mvs, e := mustBeMultiValues(client, cmder)
+
+	if e != nil {
+		err = e
+		return
+	}
+
+	css = make(ChanAndSubs, 0, len(mvs))
+
+	for _, mv := range mvs {
+		v, ok := mv.ChanAndSub()
+
+		if !ok {
+			panic(ErrUnexpectedResponseType)
+		}
+
+		css = append(css, v)
+	}
+
+	return
+}
+
+func mustBeSlowLogEntries(client driver.Client, cmder driver.Cmder) (values []SlowLogEntry, err error) {
+	mvs, e := mustBeMultiValues(client, cmder)
+
+	if e != nil {
+		err = e
+		return
+	}
+
+	values = make([]SlowLogEntry, 0, len(mvs))
+
+	for _, mv := range mvs {
+		v, ok := mv.SlowLogEntry()
+
+		if !ok {
+			panic(ErrUnexpectedResponseType)
+		}
+
+		values = append(values, v)
+	}
+
+	return
+}
+
+func mustBeLatencyEvents(client driver.Client, cmder driver.Cmder) (values []LatencyEvent, err error) {
+	mvs, e := mustBeMultiValues(client, cmder)
+
+	if e != nil {
+		err = e
+		return
+	}
+
+	values = make([]LatencyEvent, 0, len(mvs))
+
+	for _, mv := range mvs {
+		v, ok := mv.LatencyEvent()
+
+		if !ok {
+			panic(ErrUnexpectedResponseType)
+		}
+
+		values = append(values, v)
+	}
+
+	return
+}
+
+func mustBeLatencySamples(client driver.Client, cmder driver.Cmder) (values []LatencySample, err error) {
+	mvs, e := mustBeMultiValues(client, cmder)
+
+	if e != nil {
+		err = e
+		return
+	}
+
+	values = make([]LatencySample, 0, len(mvs))
+
+	for _, mv := range mvs {
+		v, ok := mv.LatencySample()
+
+		if !ok {
+			panic(ErrUnexpectedResponseType)
+		}
+
+		values = append(values, v)
+	}
+
+	return
+}
+
+func mustBeGeoLocations(client driver.Client, cmder driver.Cmder) (gls GeoLocations, err error) {
+	mvs, e := mustBeMultiValues(client, cmder)
+
+	if e != nil {
+		err = e
+		return
+	}
+
+	gls = make(GeoLocations, 0, len(mvs))
+
+	for _, mv := range mvs {
+		v, ok := mv.GeoLocation()
+
+		if !ok {
+			panic(ErrUnexpectedResponseType)
+		}
+
+		gls = append(gls, v)
+	}
+
+	return
+}
+
+func mustBeGeoPositions(client driver.Client, cmder driver.Cmder) (positions []*GeoPos, err error) {
+	mvs, e := mustBeMultiValues(client, cmder)
+
+	if e != nil {
+		err = e
+		return
+	}
+
+	positions = make([]*GeoPos, 0, len(mvs))
+
+	for _, mv := range mvs {
+		if mv.IsNil() {
+			positions = append(positions, nil)
+			continue
+		}
+
+		v, ok := mv.GeoPos()
+
+		if !ok {
+			panic(ErrUnexpectedResponseType)
+		}
+
+		positions = append(positions, &v)
+	}
+
+	return
+}
+
+func mustBeScanned(client driver.Client, cmder driver.Cmder) (cursor int64, values []string, err error) {
+	mvs, e := mustBeMultiValues(client, cmder)
+
+	if e != nil {
+		err = e
+		return
+	}
+
+	if len(mvs) != 2 {
+		panic(ErrUnexpectedResponseType)
+	}
+
+	c, ok := mvs[0].Int64()
+
+	if !ok {
+		panic(ErrUnexpectedResponseType)
+	}
+
+	elems, ok := mvs[1].MultiValues()
+
+	if !ok {
+		panic(ErrUnexpectedResponseType)
+	}
+
+	cursor = c
+	values = make([]string, 0, len(elems))
+
+	for _, elem := range elems {
+		values = append(values, multiValueString(elem))
+	}
+
+	return
+}
+
+func mustBeStreamEntries(client driver.Client, cmder driver.Cmder) (ses StreamEntries, err error) {
+	mvs, e := mustBeMultiValues(client, cmder)
+
+	if e != nil {
+		err = e
+		return
+	}
+
+	ses = make(StreamEntries, 0, len(mvs))
+
+	for _, mv := range mvs {
+		v, ok := mv.StreamEntry()
+
+		if !ok {
+			panic(ErrUnexpectedResponseType)
+		}
+
+		ses = append(ses, v)
+	}
+
+	return
+}
+
+func mustBeStreamAndEntriesList(client driver.Client, cmder driver.Cmder) (list []StreamAndEntries, err error) {
+	mvs, e := mustBeMultiValues(client, cmder)
+
+	if e != nil {
+		err = e
+		return
+	}
+
+	if len(mvs) == 0 {
+		return
+	}
+
+	list = make([]StreamAndEntries, 0, len(mvs))
+
+	for _, mv := range mvs {
+		v, ok := mv.StreamAndEntries()
+
+		if !ok {
+			panic(ErrUnexpectedResponseType)
+		}
+
+		list = append(list, v)
+	}
+
+	return
+}
+
+func mustBeStreamPending(client driver.Client, cmder driver.Cmder) (sp StreamPending, err error) {
+	if isPipelined(client) {
+		err = &FutureMultiValue{cmder}
+		return
+	}
+
+	mv, e := parseCmder(cmder)
+
+	if e != nil {
+		panic(e)
+	}
+
+	if err = mv.Err(); err != nil {
+		return
+	}
+
+	val, ok := mv.StreamPending()
+
+	if !ok {
+		panic(ErrUnexpectedResponseType)
+	}
+
+	sp = val
+	return
+}
+
+func mustBeStreamPendingEntries(client driver.Client, cmder driver.Cmder) (entries []StreamPendingEntry, err error) {
+	mvs, e := mustBeMultiValues(client, cmder)
+
+	if e != nil {
+		err = e
+		return
+	}
+
+	entries = make([]StreamPendingEntry, 0, len(mvs))
+
+	for _, mv := range mvs {
+		v, ok := mv.StreamPendingEntry()
+
+		if !ok {
+			panic(ErrUnexpectedResponseType)
+		}
+
+		entries = append(entries, v)
+	}
+
+	return
+}
+
+func mustBeAutoClaimed(client driver.Client, cmder driver.Cmder) (next string, entries StreamEntries, err error) {
+	mvs, e := mustBeMultiValues(client, cmder)
+
+	if e != nil {
+		err = e
+		return
+	}
+
+	if len(mvs) < 2 {
+		panic(ErrUnexpectedResponseType)
+	}
+
+	next = multiValueString(mvs[0])
+	claimed, ok := mvs[1].MultiValues()
+
+	if !ok {
+		panic(ErrUnexpectedResponseType)
+	}
+
+	entries = make(StreamEntries, 0, len(claimed))
+
+	for _, mv := range claimed {
+		// Redis 6.2 会将已经被删除的 entry 返回为 nil，跳过即可。
+		if mv.IsNil() {
+			continue
+		}
+
+		entry, ok := mv.MultiValues()
+
+		if !ok {
+			panic(ErrUnexpectedResponseType)
+		}
+
+		entries = append(entries, parseStreamEntry(entry))
+	}
+
+	return
+}
+
+func mustBeStrings(client driver.Client, cmder driver.Cmder) (strs []string, err error) {
+	bss, e := mustBeBulkStrings(client, cmder)
+
+	if e != nil {
+		err = e
+		return
+	}
+
+	strs = make([]string, 0, len(bss))
+
+	for _, bs := range bss {
+		strs = append(strs, bs.String())
+	}
+
+	return
+}
+
 // FutureMultiValue 表示一个还未获得结果的 MultiValue，
 // 一般来说使用者不需要直接用这个结构，而是把它当做 error 来用，
 // 使用 MakeMultiValue 来还原里面的值。
@@ -940,19 +1509,20 @@ func mustBeMemberAndScores(cmdable redis.Cmdable, cmder redis.Cmder) (mss Member
 //
 // 例如：
 //     var futureValue error
-//     _, err := client.Multi(r redis.Redis) error {
+//     _, err := client.Multi(func(r redis.Redis) error {
 //         r.Set("foo", "2")
 //         r.Incr("foo")
 //         _, futureValue = r.Get("foo")
+//         return nil
 //     })
 //     /* 检查 err，这里略过 */
 //
 //     // 使用 MakeMultiValue 获得真正的 MultiValue。
 //     val := redis.MakeMultiValue(futureValue)
-//     v, ok := val.Value()
+//     v, ok := val.BulkString()
 //     fmt.Println(v, ok) // Output: 3 true
 type FutureMultiValue struct {
-	cmder redis.Cmder
+	cmder driver.Cmder
 }
 
 // Error 返回错误信息。
@@ -966,3 +1536,4 @@ func (fmv *FutureMultiValue) MultiValue() MultiValue {
 	mv, _ := parseCmder(fmv.cmder)
 	return mv
 }
+